@@ -0,0 +1,132 @@
+// Package auth 小程序登录：wx.login code 换取 openid (code2session)，签发 HS256 JWT，
+// 并由 Gin 中间件把校验后的 openid 放入请求 context，供 Handler / Service 使用。
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// 鉴权错误 (Controller 通过 errors.Is 判断)
+var (
+	ErrInvalidCode     = errors.New("登录凭证 code 无效或已过期")
+	ErrInvalidToken    = errors.New("登录令牌无效")
+	ErrTokenExpired    = errors.New("登录令牌已过期")
+	ErrUnauthenticated = errors.New("未登录")
+	ErrForbidden       = errors.New("无权执行该操作")
+	ErrOutOfScope      = fmt.Errorf("%w: 不在负责区域内", ErrForbidden)
+)
+
+// 全局实例，main 按配置初始化 (测试中可直接替换)
+var (
+	Sessions    SessionExchanger // code2session 实现
+	Tokens      *Issuer          // 令牌签发与校验
+	RoleStore   RoleSource       // 管理员角色来源 (admins 集合)，为 nil 时只有 SuperAdmins
+	SuperAdmins map[string]bool  // 引导超级管理员 openid (auth.admin_openids)，不依赖角色集合
+	Devices     *DeviceVerifier  // 旅拍机请求签名校验，为 nil 时拒绝全部设备请求
+)
+
+type principalKey struct{}
+
+// principal 当前登录用户，角色在首次鉴权时才查询 (普通请求不产生额外读)
+type principal struct {
+	openID string
+	once   sync.Once
+	grant  Grant
+	err    error
+}
+
+// WithOpenID 返回携带 openid 的 context
+func WithOpenID(ctx context.Context, openID string) context.Context {
+	return context.WithValue(ctx, principalKey{}, &principal{openID: openID})
+}
+
+// OpenIDFrom 读取当前请求已校验的 openid，匿名请求返回 ok=false
+func OpenIDFrom(ctx context.Context) (string, bool) {
+	p, ok := ctx.Value(principalKey{}).(*principal)
+	return p.id(), ok && p.id() != ""
+}
+
+func (p *principal) id() string {
+	if p == nil {
+		return ""
+	}
+	return p.openID
+}
+
+// GrantFrom 当前用户的角色与负责区域 (SuperAdmins + RoleStore，同一请求内只查询一次)，匿名请求返回零值
+func GrantFrom(ctx context.Context) (Grant, error) {
+	p, _ := ctx.Value(principalKey{}).(*principal)
+	if p.id() == "" {
+		return Grant{}, nil
+	}
+	p.once.Do(func() {
+		if RoleStore != nil {
+			grant, err := RoleStore.GrantOf(ctx, p.openID)
+			if err != nil {
+				p.err = fmt.Errorf("查询管理员角色: %w", err)
+				return
+			}
+			p.grant = grant
+		}
+		if SuperAdmins[p.openID] && !slices.Contains(p.grant.Roles, RoleSuperAdmin) {
+			p.grant.Roles = append(p.grant.Roles, RoleSuperAdmin)
+		}
+	})
+	return p.grant, p.err
+}
+
+// Authorize 校验当前用户是否拥有权限 perm (不区分区域)
+// 匿名请求返回 ErrUnauthenticated，无权限返回 ErrForbidden，角色查询失败原样返回
+func Authorize(ctx context.Context, perm Permission) error {
+	_, err := ScopeOf(ctx, perm)
+	return err
+}
+
+// ScopeOf 当前用户拥有权限 perm 的区域范围，错误同 Authorize
+func ScopeOf(ctx context.Context, perm Permission) (Scope, error) {
+	if _, ok := OpenIDFrom(ctx); !ok {
+		return Scope{}, ErrUnauthenticated
+	}
+	grant, err := GrantFrom(ctx)
+	if err != nil {
+		return Scope{}, err
+	}
+	if !HasPermission(grant.Roles, perm) {
+		return Scope{}, ErrForbidden
+	}
+	return grant.Scope(), nil
+}
+
+// AuthorizeIn 校验当前用户在 regionOf 所在区域拥有权限 perm，超出负责区域返回 ErrOutOfScope
+// regionOf 仅在用户受区域限制时才调用，不限区域的管理员不产生额外查询
+func AuthorizeIn(ctx context.Context, perm Permission, regionOf RegionOf) error {
+	scope, err := ScopeOf(ctx, perm)
+	if err != nil || scope.All() {
+		return err
+	}
+	regionID, err := regionOf()
+	if err != nil {
+		return err
+	}
+	if !scope.Allows(regionID) {
+		return ErrOutOfScope
+	}
+	return nil
+}
+
+// RequireOwner 校验当前用户是否为内容发布者 (ownerOpenID)；非发布者需在内容所属区域拥有权限 override (如审核权限)
+// 匿名请求返回 ErrUnauthenticated，他人内容返回 ErrForbidden / ErrOutOfScope
+func RequireOwner(ctx context.Context, ownerOpenID string, override Permission, regionOf RegionOf) error {
+	openID, ok := OpenIDFrom(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if openID == ownerOpenID {
+		return nil
+	}
+	return AuthorizeIn(ctx, override, regionOf)
+}
@@ -0,0 +1,328 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const secret = "test-secret-0123456789abcdef0123456789"
+
+func TestIssueAndVerify(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	issuer, err := auth.NewIssuer(secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer.Now = func() time.Time { return now }
+
+	token, exp, err := issuer.Issue("o_alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.Equal(now.Add(time.Hour)) {
+		t.Errorf("exp = %v", exp)
+	}
+	claims, err := issuer.Verify(token)
+	if err != nil || claims.Subject != "o_alice" {
+		t.Fatalf("Verify = %+v, %v", claims, err)
+	}
+
+	// 篡改载荷
+	parts := strings.Split(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"cultural-tourism-backend","sub":"o_admin","exp":9999999999}`))
+	if _, err := issuer.Verify(parts[0] + "." + forged + "." + parts[2]); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("forged payload: err = %v", err)
+	}
+	// alg=none
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	if _, err := issuer.Verify(none + "." + parts[1] + "."); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("alg none: err = %v", err)
+	}
+	// 其他密钥签发
+	other, _ := auth.NewIssuer(strings.Repeat("x", 32), time.Hour)
+	if _, err := other.Verify(token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	// 过期
+	now = now.Add(time.Hour)
+	if _, err := issuer.Verify(token); !errors.Is(err, auth.ErrTokenExpired) {
+		t.Errorf("expired: err = %v", err)
+	}
+
+	if _, err := auth.NewIssuer("short", time.Hour); err == nil {
+		t.Error("short secret accepted")
+	}
+}
+
+func TestWeChatCode2Session(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/sns/jscode2session" || q.Get("appid") != "wx123" || q.Get("secret") != "s3cret" || q.Get("grant_type") != "authorization_code" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain") // 微信接口实际返回 text/plain
+		switch q.Get("js_code") {
+		case "good":
+			_, _ = w.Write([]byte(`{"openid":"o_alice","session_key":"sk","unionid":"u_alice"}`))
+		default:
+			_, _ = w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := auth.NewWeChatClient("wx123", "s3cret")
+	client.BaseURL = srv.URL
+
+	session, err := client.Code2Session(context.Background(), "good")
+	if err != nil || session.OpenID != "o_alice" || session.UnionID != "u_alice" {
+		t.Fatalf("Code2Session = %+v, %v", session, err)
+	}
+	if _, err := client.Code2Session(context.Background(), "used"); !errors.Is(err, auth.ErrInvalidCode) {
+		t.Errorf("invalid code: err = %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer, _ := auth.NewIssuer(secret, time.Hour)
+	prev := auth.Tokens
+	auth.Tokens = issuer
+	t.Cleanup(func() { auth.Tokens = prev })
+
+	r := gin.New()
+	r.Use(auth.Middleware())
+	r.GET("/whoami", func(c *gin.Context) {
+		openID, _ := auth.OpenIDFrom(c.Request.Context())
+		c.String(http.StatusOK, openID)
+	})
+	r.GET("/private", auth.RequireLogin(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	call := func(path, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	token, _, _ := issuer.Issue("o_alice")
+	if w := call("/whoami", "Bearer "+token); w.Code != http.StatusOK || w.Body.String() != "o_alice" {
+		t.Errorf("valid token: %d %q", w.Code, w.Body)
+	}
+	if w := call("/whoami", ""); w.Code != http.StatusOK || w.Body.String() != "" {
+		t.Errorf("anonymous: %d %q", w.Code, w.Body)
+	}
+	if w := call("/whoami", "Bearer garbage"); w.Code != http.StatusUnauthorized {
+		t.Errorf("invalid token: %d", w.Code)
+	}
+	if w := call("/private", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("RequireLogin anonymous: %d", w.Code)
+	}
+	if w := call("/private", "Bearer "+token); w.Code != http.StatusNoContent {
+		t.Errorf("RequireLogin with token: %d", w.Code)
+	}
+}
+
+// roleSource 按 openid 返回固定角色分配，并记录查询次数
+type roleSource struct {
+	grants map[string]auth.Grant
+	calls  int
+	err    error
+}
+
+func (s *roleSource) GrantOf(_ context.Context, openID string) (auth.Grant, error) {
+	s.calls++
+	return s.grants[openID], s.err
+}
+
+func TestAuthorize(t *testing.T) {
+	src := &roleSource{grants: map[string]auth.Grant{
+		"o_mod":   {Roles: []auth.Role{auth.RoleContentModerator}},
+		"o_local": {Roles: []auth.Role{auth.RoleContentModerator}, RegionIDs: []string{"r1"}},
+		"o_root":  {Roles: []auth.Role{auth.RoleMerchantOperator}, RegionIDs: []string{"r1"}},
+	}}
+	prevStore, prevSuper := auth.RoleStore, auth.SuperAdmins
+	auth.RoleStore, auth.SuperAdmins = src, map[string]bool{"o_root": true}
+	t.Cleanup(func() { auth.RoleStore, auth.SuperAdmins = prevStore, prevSuper })
+
+	if err := auth.Authorize(context.Background(), auth.PermModerate); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("anonymous: %v", err)
+	}
+
+	ctx := auth.WithOpenID(context.Background(), "o_mod")
+	if err := auth.Authorize(ctx, auth.PermModerate); err != nil {
+		t.Errorf("moderator moderate: %v", err)
+	}
+	if err := auth.Authorize(ctx, auth.PermManageRoles); !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("moderator manage roles: %v", err)
+	}
+	if src.calls != 1 {
+		t.Errorf("role lookups = %d, want 1 per request", src.calls)
+	}
+
+	// 引导超级管理员拥有全部权限；发布者可操作自己的内容
+	root := auth.WithOpenID(context.Background(), "o_root")
+	if err := auth.Authorize(root, auth.PermManageRoles); err != nil {
+		t.Errorf("super admin: %v", err)
+	}
+	alice := auth.WithOpenID(context.Background(), "o_alice")
+	if err := auth.RequireOwner(alice, "o_alice", auth.PermModerate, auth.Region("")); err != nil {
+		t.Errorf("owner: %v", err)
+	}
+	if err := auth.RequireOwner(alice, "o_bob", auth.PermModerate, auth.Region("")); !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("non-owner: %v", err)
+	}
+
+	// 负责区域：超级管理员不受限制；受限管理员只能操作区域内内容，区域仅在需要时查询
+	local := auth.WithOpenID(context.Background(), "o_local")
+	lookups := 0
+	regionOf := func(id string) auth.RegionOf {
+		return func() (string, error) { lookups++; return id, nil }
+	}
+	if err := auth.AuthorizeIn(local, auth.PermModerate, regionOf("r1")); err != nil {
+		t.Errorf("in scope: %v", err)
+	}
+	if err := auth.AuthorizeIn(local, auth.PermModerate, regionOf("r2")); !errors.Is(err, auth.ErrOutOfScope) || !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("out of scope: %v", err)
+	}
+	if err := auth.RequireOwner(local, "o_bob", auth.PermModerate, regionOf("")); !errors.Is(err, auth.ErrOutOfScope) {
+		t.Errorf("unassigned region: %v", err)
+	}
+	if err := auth.AuthorizeIn(root, auth.PermModerate, regionOf("r2")); err != nil {
+		t.Errorf("super admin out of region: %v", err)
+	}
+	if lookups != 3 {
+		t.Errorf("region lookups = %d, want 3 (none for unrestricted admins)", lookups)
+	}
+
+	src.err = errors.New("gateway down")
+	if err := auth.Authorize(auth.WithOpenID(context.Background(), "o_mod"), auth.PermModerate); err == nil || errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("lookup failure = %v, want upstream error", err)
+	}
+}
+
+type deviceSource map[string]*auth.Device
+
+func (s deviceSource) DeviceOf(_ context.Context, id string) (*auth.Device, error) {
+	if d, ok := s[id]; ok {
+		return d, nil
+	}
+	return nil, auth.ErrUnknownDevice
+}
+
+// nonceStore 内存 nonce 登记 (线上为 device_nonces 集合)
+type nonceStore map[string]time.Time
+
+func (s nonceStore) UseNonce(_ context.Context, deviceID, nonce string, expiresAt time.Time) error {
+	if _, used := s[deviceID+"\n"+nonce]; used {
+		return auth.ErrNonceReplayed
+	}
+	s[deviceID+"\n"+nonce] = expiresAt
+	return nil
+}
+
+// countingSource 统计设备查询次数
+type countingSource struct {
+	deviceSource
+	lookups int
+}
+
+func (s *countingSource) DeviceOf(ctx context.Context, id string) (*auth.Device, error) {
+	s.lookups++
+	return s.deviceSource.DeviceOf(ctx, id)
+}
+
+func TestDeviceVerifier(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	source := &countingSource{deviceSource: deviceSource{"d1": {ID: "d1", POIID: "booth1", Secret: "s3cret"}}}
+	nonces := nonceStore{}
+	verifier, err := auth.NewDeviceVerifier(source, nonces, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier.Now = func() time.Time { return now }
+
+	signed := func(deviceID, secret string, at time.Time, nonce, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/device/photos?x=1", strings.NewReader(body))
+		ts := strconv.FormatInt(at.Unix(), 10)
+		req.Header.Set(auth.HeaderDeviceID, deviceID)
+		req.Header.Set(auth.HeaderTimestamp, ts)
+		req.Header.Set(auth.HeaderNonce, nonce)
+		req.Header.Set(auth.HeaderSignature, auth.Sign(secret, http.MethodPost, "/api/device/photos?x=1", ts, nonce, []byte(body)))
+		return req
+	}
+
+	req := signed("d1", "s3cret", now, "nonce-01", `{"a":1}`)
+	device, err := verifier.Verify(req)
+	if err != nil || device.POIID != "booth1" {
+		t.Fatalf("Verify = %+v, %v", device, err)
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"a":1}` {
+		t.Errorf("body after verify = %q", body)
+	}
+	if exp := nonces["d1\nnonce-01"]; !exp.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("nonce expires at %v, want 2*window", exp)
+	}
+
+	tampered := signed("d1", "s3cret", now, "nonce-05", `{"a":1}`)
+	tampered.Body = io.NopCloser(strings.NewReader(`{"a":2}`))
+
+	cases := []struct {
+		name string
+		req  *http.Request
+		want error
+	}{
+		{"replayed nonce", signed("d1", "s3cret", now, "nonce-01", `{"a":1}`), auth.ErrNonceReplayed},
+		{"wrong secret", signed("d1", "other", now, "nonce-02", ""), auth.ErrInvalidSignature},
+		{"unknown device", signed("d2", "s3cret", now, "nonce-03", ""), auth.ErrInvalidSignature},
+		{"tampered body", tampered, auth.ErrInvalidSignature},
+	}
+	for _, tc := range cases {
+		if _, err := verifier.Verify(tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	// 伪造请求不占用 nonce
+	if _, err := verifier.Verify(signed("d1", "s3cret", now, "nonce-02", "")); err != nil {
+		t.Errorf("nonce used by rejected request: %v", err)
+	}
+
+	// 格式错误或时间戳过期的请求不查询设备
+	lookups := source.lookups
+	bad := signed("d1", "s3cret", now, "nonce-06", "")
+	bad.Header.Set(auth.HeaderSignature, strings.Repeat("z", 64))
+	junk := []struct {
+		name string
+		req  *http.Request
+		want error
+	}{
+		{"stale timestamp", signed("d1", "s3cret", now.Add(-6*time.Minute), "nonce-04", ""), auth.ErrSignatureExpired},
+		{"short nonce", signed("d1", "s3cret", now, "n1", ""), auth.ErrInvalidSignature},
+		{"missing headers", httptest.NewRequest(http.MethodGet, "/api/device/booth", nil), auth.ErrInvalidSignature},
+		{"non-hex signature", bad, auth.ErrInvalidSignature},
+	}
+	for _, tc := range junk {
+		if _, err := verifier.Verify(tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if source.lookups != lookups {
+		t.Errorf("device lookups for malformed requests = %d, want 0", source.lookups-lookups)
+	}
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 旅拍机签名请求头
+const (
+	HeaderDeviceID  = "X-Device-Id"
+	HeaderTimestamp = "X-Timestamp" // Unix 秒
+	HeaderNonce     = "X-Nonce"     // 每次请求随机生成 (8-64 个字符)，窗口期内不可重复
+	HeaderSignature = "X-Signature" // 十六进制 HMAC-SHA256，见 Sign
+)
+
+// maxSignedBody 参与签名的请求体上限
+const maxSignedBody = 10 << 20
+
+// nonce 长度范围 (如 16 字节随机数的十六进制编码为 32 个字符)
+const (
+	minNonceLen = 8
+	maxNonceLen = 64
+)
+
+// 设备鉴权错误 (中间件映射为 401)
+// ErrUnknownDevice 仅用于 DeviceSource 与 Verify 之间，Verify 对外统一返回 ErrInvalidSignature，
+// 调用方无法借此探测哪些设备 ID 存在
+var (
+	ErrUnknownDevice    = errors.New("设备不存在或已停用")
+	ErrInvalidSignature = errors.New("请求签名无效")
+	ErrSignatureExpired = errors.New("请求时间戳超出允许范围")
+	ErrNonceReplayed    = errors.New("请求 nonce 已使用")
+)
+
+// Device 已登记的旅拍机，Secret 为签名密钥
+type Device struct {
+	ID     string
+	POIID  string // 所属旅拍机点位 (type=booth)
+	Secret string
+}
+
+// DeviceSource 按设备 ID 查询设备，不存在 / 已停用 / 点位不是旅拍机时返回 ErrUnknownDevice
+type DeviceSource interface {
+	DeviceOf(ctx context.Context, deviceID string) (*Device, error)
+}
+
+// Sign 计算请求签名：HMAC-SHA256(secret, 待签字符串) 的十六进制编码
+// 待签字符串按行拼接：METHOD \n 路径 (含查询串) \n 时间戳 \n nonce \n 请求体 SHA-256 十六进制
+func Sign(secret, method, requestURI, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s", method, requestURI, timestamp, nonce, hex.EncodeToString(sum[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NonceStore 登记已使用的 nonce，多实例部署时必须由各实例共享 (见 services.DeviceNonceStore)
+type NonceStore interface {
+	// UseNonce 登记 deviceID 的 nonce，expiresAt 之前已登记过时返回 ErrNonceReplayed
+	UseNonce(ctx context.Context, deviceID, nonce string, expiresAt time.Time) error
+}
+
+// DeviceVerifier 旅拍机请求签名校验
+// 时间戳与服务器时间相差不超过 Window，nonce 在 2*Window 内不可重复 (超出窗口的请求已被时间戳拒绝)
+type DeviceVerifier struct {
+	Source DeviceSource
+	Nonces NonceStore
+	Window time.Duration
+	Now    func() time.Time // 为空时使用 time.Now (测试可注入)
+}
+
+// NewDeviceVerifier 创建签名校验器
+func NewDeviceVerifier(source DeviceSource, nonces NonceStore, window time.Duration) (*DeviceVerifier, error) {
+	if window <= 0 {
+		return nil, errors.New("设备签名时间窗口必须大于 0")
+	}
+	if source == nil || nonces == nil {
+		return nil, errors.New("设备签名校验缺少设备来源或 nonce 存储")
+	}
+	return &DeviceVerifier{Source: source, Nonces: nonces, Window: window}, nil
+}
+
+func (v *DeviceVerifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+// Verify 校验请求签名，返回调用方设备
+// 请求头格式与时间戳先于设备查询校验，格式错误的请求不产生数据读取；
+// 设备不存在与签名错误返回同一个 ErrInvalidSignature，且同样读取请求体并计算签名；
+// 读取后的请求体会放回 req.Body，Handler 可照常绑定
+func (v *DeviceVerifier) Verify(req *http.Request) (*Device, error) {
+	deviceID, timestamp := req.Header.Get(HeaderDeviceID), req.Header.Get(HeaderTimestamp)
+	nonce, signature := req.Header.Get(HeaderNonce), req.Header.Get(HeaderSignature)
+	if deviceID == "" || len(nonce) < minNonceLen || len(nonce) > maxNonceLen || len(signature) != sha256.Size*2 {
+		return nil, ErrInvalidSignature
+	}
+	if _, err := hex.DecodeString(signature); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	now := v.now()
+	if skew := now.Sub(time.Unix(ts, 0)); skew > v.Window || skew < -v.Window {
+		return nil, ErrSignatureExpired
+	}
+
+	device, err := v.Source.DeviceOf(req.Context(), deviceID)
+	unknown := errors.Is(err, ErrUnknownDevice)
+	if err != nil && !unknown {
+		return nil, err
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	var secret string
+	if !unknown {
+		secret = device.Secret
+	}
+	expected := Sign(secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) || unknown {
+		return nil, ErrInvalidSignature
+	}
+
+	// 签名通过后才登记 nonce，伪造请求无法占用合法设备的 nonce
+	if err := v.Nonces.UseNonce(req.Context(), deviceID, nonce, now.Add(2*v.Window)); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxSignedBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取请求体: %w", err)
+	}
+	if len(body) > maxSignedBody {
+		return nil, ErrInvalidSignature
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+type deviceKey struct{}
+
+// WithDevice 返回携带调用方设备的 context
+func WithDevice(ctx context.Context, device *Device) context.Context {
+	return context.WithValue(ctx, deviceKey{}, device)
+}
+
+// DeviceFrom 读取当前请求已校验的设备，非设备请求返回 ok=false
+func DeviceFrom(ctx context.Context) (*Device, bool) {
+	device, ok := ctx.Value(deviceKey{}).(*Device)
+	return device, ok && device != nil
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKey gin.Context 中保存 openid 的键
+const ContextKey = "openid"
+
+// Middleware 解析 Authorization: Bearer <token>
+// 令牌有效时把 openid 写入请求 context (auth.OpenIDFrom) 与 gin 上下文，角色在鉴权时按需查询 (auth.GrantFrom)；
+// 未携带令牌按匿名请求放行，携带了无效 / 过期令牌返回 401，前端据此重新登录
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok || Tokens == nil {
+			c.Next()
+			return
+		}
+
+		claims, err := Tokens.Verify(token)
+		if err != nil {
+			respondUnauthorized(c, err)
+			return
+		}
+		c.Set(ContextKey, claims.Subject)
+		c.Request = c.Request.WithContext(WithOpenID(c.Request.Context(), claims.Subject))
+		c.Next()
+	}
+}
+
+// RequireLogin 要求已登录 (需挂在 Middleware 之后)，匿名请求返回 401
+func RequireLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := OpenIDFrom(c.Request.Context()); !ok {
+			respondUnauthorized(c, ErrUnauthenticated)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission 要求当前用户拥有权限 perm (需挂在 Middleware 之后)
+// 匿名请求返回 401，无权限返回 403，角色查询失败返回 503
+func RequirePermission(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := Authorize(c.Request.Context(), perm)
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.Is(err, ErrUnauthenticated):
+			respondUnauthorized(c, err)
+		case errors.Is(err, ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "无权执行该操作", "code": "FORBIDDEN"})
+		default:
+			log.Printf("❌ %s %s 权限校验失败: %v", c.Request.Method, c.FullPath(), err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "服务繁忙，请稍后再试"})
+		}
+	}
+}
+
+// RequireDevice 要求请求携带有效的旅拍机签名 (见 Sign)，
+// 通过后把调用方设备 (含所属旅拍机点位) 写入请求 context (auth.DeviceFrom)，签名密钥不向下传递；
+// 签名缺失 / 无效 / 过期 / 重放返回 401，设备查询失败返回 503
+func RequireDevice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if Devices == nil {
+			respondDeviceUnauthorized(c, ErrInvalidSignature)
+			return
+		}
+		device, err := Devices.Verify(c.Request)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrSignatureExpired),
+			errors.Is(err, ErrNonceReplayed):
+			respondDeviceUnauthorized(c, err)
+			return
+		default:
+			log.Printf("❌ %s %s 设备签名校验失败: %v", c.Request.Method, c.FullPath(), err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "服务繁忙，请稍后再试"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithDevice(c.Request.Context(), &Device{ID: device.ID, POIID: device.POIID}))
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func respondUnauthorized(c *gin.Context, err error) {
+	body := gin.H{"error": "请先登录", "code": "UNAUTHENTICATED"}
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		body = gin.H{"error": "登录已过期，请重新登录", "code": "TOKEN_EXPIRED"}
+	case errors.Is(err, ErrInvalidToken):
+		body = gin.H{"error": "登录令牌无效，请重新登录", "code": "INVALID_TOKEN"}
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, body)
+}
+
+func respondDeviceUnauthorized(c *gin.Context, err error) {
+	body := gin.H{"error": "设备签名无效", "code": "INVALID_SIGNATURE"}
+	switch {
+	case errors.Is(err, ErrSignatureExpired):
+		body = gin.H{"error": "请求时间戳超出允许范围，请校准设备时间", "code": "SIGNATURE_EXPIRED"}
+	case errors.Is(err, ErrNonceReplayed):
+		body = gin.H{"error": "重复的请求", "code": "NONCE_REPLAYED"}
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, body)
+}
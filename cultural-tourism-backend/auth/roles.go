@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Role 管理员角色
+type Role string
+
+const (
+	RoleSuperAdmin       Role = "super_admin"       // 超级管理员：全部权限，含角色分配与设备凭证
+	RoleContentModerator Role = "content_moderator" // 内容审核员：点位 / 主题维护，照片 / 评论审核与删除
+	RoleMerchantOperator Role = "merchant_operator" // 商户运营：商品导流管理
+)
+
+// Permission 权限点，路由与 Service 按权限点而非角色判断
+type Permission string
+
+const (
+	PermManageContent  Permission = "content:manage" // 区域 / 点位 / 主题的增删改
+	PermManageProducts Permission = "product:manage" // 商品的增删改
+	PermModerate       Permission = "ugc:moderate"   // 照片 / 评论审核，修改或删除他人内容
+	PermManageRoles    Permission = "role:manage"    // 管理员角色分配
+	PermManageDevices  Permission = "device:manage"  // 旅拍机设备凭证的签发 / 轮换 / 吊销
+)
+
+// rolePermissions 角色 -> 权限集合
+var rolePermissions = map[Role][]Permission{
+	RoleSuperAdmin:       {PermManageContent, PermManageProducts, PermModerate, PermManageRoles, PermManageDevices},
+	RoleContentModerator: {PermManageContent, PermModerate},
+	RoleMerchantOperator: {PermManageProducts},
+}
+
+// RoleSource 按 openid 查询角色分配，未分配时返回零值
+type RoleSource interface {
+	GrantOf(ctx context.Context, openID string) (Grant, error)
+}
+
+// Grant 用户的角色分配
+// RegionIDs 为负责区域，对全部角色生效 (超级管理员除外)；为空表示不限区域
+type Grant struct {
+	Roles     []Role
+	RegionIDs []string
+}
+
+// Scope 权限生效的区域范围
+func (g Grant) Scope() Scope {
+	if len(g.RegionIDs) == 0 || slices.Contains(g.Roles, RoleSuperAdmin) {
+		return Scope{}
+	}
+	return Scope{RegionIDs: g.RegionIDs}
+}
+
+// Scope 区域范围，RegionIDs 为 nil 表示不限区域
+type Scope struct {
+	RegionIDs []string
+}
+
+// All 是否不限区域
+func (s Scope) All() bool {
+	return s.RegionIDs == nil
+}
+
+// Allows 区域 regionID 是否在范围内 (未归属区域的内容只对不限区域的管理员开放)
+func (s Scope) Allows(regionID string) bool {
+	return s.All() || (regionID != "" && slices.Contains(s.RegionIDs, regionID))
+}
+
+// RegionOf 延迟获取资源所属区域 (如按 theme_id 查询主题的 region_id)
+type RegionOf func() (string, error)
+
+// Region 已知区域
+func Region(regionID string) RegionOf {
+	return func() (string, error) { return regionID, nil }
+}
+
+// Roles 全部角色 (已排序)
+func Roles() []Role {
+	roles := make([]Role, 0, len(rolePermissions))
+	for r := range rolePermissions {
+		roles = append(roles, r)
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// Valid 是否为已定义的角色
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions 角色集合拥有的全部权限 (去重，已排序)
+func Permissions(roles []Role) []Permission {
+	var perms []Permission
+	for _, r := range roles {
+		for _, p := range rolePermissions[r] {
+			if !slices.Contains(perms, p) {
+				perms = append(perms, p)
+			}
+		}
+	}
+	slices.Sort(perms)
+	return perms
+}
+
+// HasPermission 角色集合是否拥有权限 perm
+func HasPermission(roles []Role, perm Permission) bool {
+	for _, r := range roles {
+		if slices.Contains(rolePermissions[r], perm) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// issuer 令牌签发方 (iss)，sub 为 openid
+const issuer = "cultural-tourism-backend"
+
+// jwtHeader HS256 固定头部 (base64url 编码后)
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims 令牌载荷
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"` // openid
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Issuer HS256 JWT 签发与校验
+type Issuer struct {
+	Secret []byte
+	TTL    time.Duration
+	Now    func() time.Time // 为空时使用 time.Now (测试可注入)
+}
+
+// NewIssuer 创建签发器，secret 至少 32 字节
+func NewIssuer(secret string, ttl time.Duration) (*Issuer, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("JWT 密钥长度不能少于 32 字节")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("令牌有效期必须大于 0")
+	}
+	return &Issuer{Secret: []byte(secret), TTL: ttl}, nil
+}
+
+func (i *Issuer) now() time.Time {
+	if i.Now != nil {
+		return i.Now()
+	}
+	return time.Now()
+}
+
+// Issue 为 openid 签发令牌，返回令牌及过期时间
+func (i *Issuer) Issue(openID string) (string, time.Time, error) {
+	now := i.now()
+	exp := now.Add(i.TTL)
+	payload, err := json.Marshal(Claims{Issuer: issuer, Subject: openID, IssuedAt: now.Unix(), ExpiresAt: exp.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + i.sign(signingInput), exp, nil
+}
+
+// Verify 校验签名、签发方与有效期，返回载荷
+func (i *Issuer) Verify(token string) (*Claims, error) {
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != jwtHeader {
+		return nil, ErrInvalidToken // 只接受本服务签发的 HS256 头部，拒绝 alg=none 等
+	}
+	payload, sig, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(i.sign(header+"."+payload))) {
+		return nil, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Issuer != issuer || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if i.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+func (i *Issuer) sign(signingInput string) string {
+	mac := hmac.New(sha256.New, i.Secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Session code2session 结果；SessionKey 只在服务端使用，不返回给前端
+type Session struct {
+	OpenID     string
+	UnionID    string
+	SessionKey string
+}
+
+// SessionExchanger 用 wx.login 获取的 code 换取会话
+type SessionExchanger interface {
+	Code2Session(ctx context.Context, code string) (*Session, error)
+}
+
+// DefaultWeChatURL 微信开放接口地址
+const DefaultWeChatURL = "https://api.weixin.qq.com"
+
+// WeChatClient 调用微信 jscode2session 接口
+type WeChatClient struct {
+	AppID      string
+	Secret     string
+	BaseURL    string // 为空时使用 DefaultWeChatURL
+	HTTPClient *http.Client
+}
+
+// NewWeChatClient 创建 code2session 客户端
+func NewWeChatClient(appID, secret string) *WeChatClient {
+	return &WeChatClient{AppID: appID, Secret: secret, HTTPClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// WeChatError 微信接口返回的业务错误
+type WeChatError struct {
+	Code    int
+	Message string
+}
+
+func (e *WeChatError) Error() string {
+	return fmt.Sprintf("code2session 失败 (errcode=%d): %s", e.Code, e.Message)
+}
+
+// Is 40029 (code 无效) / 40163 (code 已使用) 视为 ErrInvalidCode
+func (e *WeChatError) Is(target error) bool {
+	return target == ErrInvalidCode && (e.Code == 40029 || e.Code == 40163)
+}
+
+// Code2Session GET /sns/jscode2session
+func (w *WeChatClient) Code2Session(ctx context.Context, code string) (*Session, error) {
+	base := w.BaseURL
+	if base == "" {
+		base = DefaultWeChatURL
+	}
+	q := url.Values{
+		"appid":      {w.AppID},
+		"secret":     {w.Secret},
+		"js_code":    {code},
+		"grant_type": {"authorization_code"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/sns/jscode2session?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("code2session 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("code2session 返回 HTTP %d", resp.StatusCode)
+	}
+
+	// 微信接口出错时同样返回 200，通过 errcode 区分
+	var body struct {
+		OpenID     string `json:"openid"`
+		UnionID    string `json:"unionid"`
+		SessionKey string `json:"session_key"`
+		ErrCode    int    `json:"errcode"`
+		ErrMsg     string `json:"errmsg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("code2session 响应解析失败: %w", err)
+	}
+	if body.ErrCode != 0 {
+		return nil, &WeChatError{Code: body.ErrCode, Message: body.ErrMsg}
+	}
+	if body.OpenID == "" {
+		return nil, fmt.Errorf("code2session 响应缺少 openid")
+	}
+	return &Session{OpenID: body.OpenID, UnionID: body.UnionID, SessionKey: body.SessionKey}, nil
+}
+
+// StubExchanger 本地开发 / 测试替身：不访问微信，同一个 code 总是得到同一个 openid
+// code 以 "openid:" 开头时直接使用其后的值，便于模拟指定用户
+type StubExchanger struct{}
+
+// Code2Session 由 code 推导 openid
+func (StubExchanger) Code2Session(_ context.Context, code string) (*Session, error) {
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+	if openID, ok := strings.CutPrefix(code, "openid:"); ok && openID != "" {
+		return &Session{OpenID: openID}, nil
+	}
+	sum := sha256.Sum256([]byte(code))
+	return &Session{OpenID: "stub_" + hex.EncodeToString(sum[:12])}, nil
+}
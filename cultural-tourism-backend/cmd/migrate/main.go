@@ -0,0 +1,124 @@
+// Command migrate 为新增字段回填历史数据 (数据模型按 model-json 更新后执行，步骤见 PROJECT_CONTEXT.md)
+//
+//	go run ./cmd/migrate          # 预览：只统计需要回填的记录
+//	go run ./cmd/migrate -apply   # 执行回填
+//
+// 读取与服务相同的配置 (环境变量 / .env / config.yaml)。每一步只补全缺失的字段，可重复执行。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/config"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/store/local"
+	"cultural-tourism-backend/tcb"
+)
+
+// step 一个回填步骤：fill 返回记录需要补全的字段，无需修改时返回 nil
+type step struct {
+	name     string
+	resource collection.Resource
+	fill     func(rec map[string]interface{}) map[string]interface{}
+}
+
+var steps = []step{
+	{
+		name:     "products.status (列表只返回上架商品，缺失时按已上架处理)",
+		resource: collection.Products,
+		fill: func(rec map[string]interface{}) map[string]interface{} {
+			if rec["status"] != nil {
+				return nil
+			}
+			return map[string]interface{}{"status": 1}
+		},
+	},
+	{
+		name:     "regions.created_at / updated_at (条件更新以 updated_at 作为版本)",
+		resource: collection.Regions,
+		fill: func(rec map[string]interface{}) map[string]interface{} {
+			data := map[string]interface{}{}
+			if rec["created_at"] == nil || rec["created_at"] == "" {
+				// 优先沿用系统字段 createdAt (毫秒时间戳)
+				created := time.Now()
+				if ms, ok := rec["createdAt"].(float64); ok && ms > 0 {
+					created = time.UnixMilli(int64(ms))
+				}
+				data["created_at"] = created.Format(time.RFC3339)
+			}
+			if rec["updated_at"] == nil || rec["updated_at"] == "" {
+				data["updated_at"] = tcb.Timestamp()
+			}
+			if len(data) == 0 {
+				return nil
+			}
+			return data
+		},
+	},
+}
+
+func main() {
+	apply := flag.Bool("apply", false, "执行回填 (默认只预览)")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+	overrides := make(map[collection.Resource]string, len(cfg.Store.CollectionNames))
+	for r, name := range cfg.Store.CollectionNames {
+		overrides[collection.Resource(r)] = name
+	}
+	if err := collection.Configure(overrides); err != nil {
+		log.Fatalf("❌ 配置错误: COLLECTION_NAMES: %v", err)
+	}
+
+	var ds store.DataStore
+	if cfg.Store.Backend == "local" {
+		s, err := local.Open(cfg.Store.LocalPath)
+		if err != nil {
+			log.Fatalf("❌ 打开本地存储失败: %v", err)
+		}
+		ds = s
+	} else {
+		tcb.Init(cfg.TCB)
+		ds = tcb.Client
+	}
+
+	for _, s := range steps {
+		n, err := backfill(context.Background(), ds, s, *apply)
+		if err != nil {
+			log.Fatalf("❌ %s: %v", s.name, err)
+		}
+		if *apply {
+			fmt.Printf("✅ %s: 回填 %d 条\n", s.name, n)
+		} else {
+			fmt.Printf("🔍 %s: 待回填 %d 条 (加 -apply 执行)\n", s.name, n)
+		}
+	}
+}
+
+// backfill 按 _id 顺序遍历集合，apply 为 true 时逐条写入 fill 返回的字段，返回需要回填的记录数
+func backfill(ctx context.Context, ds store.DataStore, s step, apply bool) (int, error) {
+	model := s.resource.Name()
+	n := 0
+	for rec, err := range tcb.ListAll(ctx, ds, model, nil, tcb.ScanOptions{}) {
+		if err != nil {
+			return n, err
+		}
+		data := s.fill(rec)
+		if data == nil {
+			continue
+		}
+		if apply {
+			id, _ := rec["_id"].(string)
+			if err := ds.UpdateData(ctx, model, id, data); err != nil {
+				return n, fmt.Errorf("记录 %s: %w", id, err)
+			}
+		}
+		n++
+	}
+	return n, nil
+}
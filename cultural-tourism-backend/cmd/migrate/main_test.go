@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/store/local"
+)
+
+func TestBackfillProductStatus(t *testing.T) {
+	ds := local.New()
+	model := collection.Products.Name()
+	ds.Insert(model, map[string]interface{}{"name": "旧商品"})
+	ds.Insert(model, map[string]interface{}{"name": "已下架", "status": 0})
+
+	if n, err := backfill(context.Background(), ds, steps[0], false); err != nil || n != 1 {
+		t.Fatalf("dry run = %d, %v; want 1", n, err)
+	}
+	if rec := ds.Records(model)[0]; rec["status"] != nil {
+		t.Fatalf("dry run wrote status: %v", rec)
+	}
+
+	if n, err := backfill(context.Background(), ds, steps[0], true); err != nil || n != 1 {
+		t.Fatalf("apply = %d, %v; want 1", n, err)
+	}
+	for _, rec := range ds.Records(model) {
+		if want := map[string]float64{"旧商品": 1, "已下架": 0}[rec["name"].(string)]; rec["status"] != want {
+			t.Errorf("%v: status = %v, want %v", rec["name"], rec["status"], want)
+		}
+	}
+	if n, _ := backfill(context.Background(), ds, steps[0], true); n != 0 {
+		t.Errorf("second run = %d, want 0", n)
+	}
+}
+
+func TestBackfillRegionTimestamps(t *testing.T) {
+	ds := local.New()
+	model := collection.Regions.Name()
+	created := time.Date(2024, 5, 1, 8, 0, 0, 0, time.Local)
+	ds.Insert(model, map[string]interface{}{"name": "西湖", "createdAt": created.UnixMilli()})
+	ds.Insert(model, map[string]interface{}{"name": "灵隐", "created_at": "2025-01-01T00:00:00+08:00", "updated_at": "v1"})
+
+	if n, err := backfill(context.Background(), ds, steps[1], true); err != nil || n != 1 {
+		t.Fatalf("apply = %d, %v; want 1", n, err)
+	}
+	for _, rec := range ds.Records(model) {
+		switch rec["name"] {
+		case "西湖":
+			if rec["created_at"] != created.Format(time.RFC3339) || rec["updated_at"] == nil {
+				t.Errorf("backfilled region = %v", rec)
+			}
+		case "灵隐":
+			if rec["updated_at"] != "v1" {
+				t.Errorf("existing version overwritten: %v", rec)
+			}
+		}
+	}
+}
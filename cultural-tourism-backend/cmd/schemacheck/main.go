@@ -0,0 +1,49 @@
+// Command schemacheck 检查 Go 模型及查询代码与 model-json 数据模型定义是否一致
+//
+//	go run ./cmd/schemacheck                       # 在 cultural-tourism-backend 目录下执行
+//	go run ./cmd/schemacheck -src services,controllers -json
+//
+// 发现问题时以状态码 1 退出，可用于 CI。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"cultural-tourism-backend/schemacheck"
+)
+
+func main() {
+	schemaDir := flag.String("schema", "model-json", "数据模型定义目录")
+	src := flag.String("src", "services,controllers", "扫描查询字段的源码目录 (逗号分隔，留空跳过)")
+	asJSON := flag.Bool("json", false, "以 JSON 输出")
+	flag.Parse()
+
+	var dirs []string
+	for _, d := range strings.Split(*src, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			dirs = append(dirs, d)
+		}
+	}
+
+	report, err := schemacheck.Check(schemacheck.Options{Schemas: os.DirFS(*schemaDir), SourceDirs: dirs})
+	if err != nil {
+		log.Fatalf("❌ 检查失败: %v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		report.Print(os.Stdout)
+		fmt.Printf("共 %d 个问题\n", len(report.Issues))
+	}
+	if len(report.Issues) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+// Command tcbfake 启动本地 CloudBase 数据模型替身，供小程序端离线联调
+//
+//	go run ./cmd/tcbfake -addr :9090 -seed seed.json
+//
+// 后端启动时设置 CLOUDBASE_BASE_URL=http://localhost:9090 即可连接替身。
+// seed.json 格式: {"regions": [{"name": "西湖", "status": 1}], "pois": [...]}
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"cultural-tourism-backend/tcbtest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "监听地址")
+	seed := flag.String("seed", "", "初始数据 JSON 文件 (可选)")
+	flag.Parse()
+
+	fake := tcbtest.NewFake()
+	if *seed != "" {
+		data, err := os.ReadFile(*seed)
+		if err != nil {
+			log.Fatalf("❌ 读取种子文件失败: %v", err)
+		}
+		var models map[string][]interface{}
+		if err := json.Unmarshal(data, &models); err != nil {
+			log.Fatalf("❌ 种子文件格式错误: %v", err)
+		}
+		for model, records := range models {
+			fake.Seed(model, records...)
+			log.Printf("🌱 %s: 写入 %d 条记录", model, len(records))
+		}
+	}
+
+	log.Printf("🧪 CloudBase 数据模型替身已启动: http://localhost%s", *addr)
+	log.Printf("   后端请设置 CLOUDBASE_BASE_URL=http://localhost%s", *addr)
+	if err := http.ListenAndServe(*addr, logRequests(fake)); err != nil {
+		log.Fatalf("❌ 启动失败: %v", err)
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,121 @@
+// Package collection 资源 -> CloudBase 数据模型标识 (集合名) 的统一注册表
+//
+// 线上数据模型的命名并不统一 (photo / comment / product 为单数)，
+// 代码中只使用资源常量，实际集合名由注册表给出，可通过配置覆盖 (见 Configure)，
+// 启动时用 Verify 逐个探测，名称写错时直接拒绝启动，而不是静默返回空列表。
+package collection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+)
+
+// Resource 资源标识
+// 约定：常量名转小写即资源标识 (schemacheck 据此解析 collection.POIs 这类引用)
+type Resource string
+
+const (
+	Regions      Resource = "regions"
+	POIs         Resource = "pois"
+	Themes       Resource = "themes"
+	Photos       Resource = "photos"
+	Comments     Resource = "comments"
+	Products     Resource = "products"
+	Favorites    Resource = "favorites"
+	Admins       Resource = "admins"
+	Devices      Resource = "devices"
+	DeviceNonces Resource = "devicenonces"
+	Likes        Resource = "likes"
+)
+
+// defaults 与 model-json 中的模型标识一致
+var defaults = map[Resource]string{
+	Regions:      "regions",
+	POIs:         "pois",
+	Themes:       "themes",
+	Photos:       "photo",
+	Comments:     "comment",
+	Products:     "product",
+	Favorites:    "favorites",
+	Admins:       "admins",
+	Devices:      "devices",
+	DeviceNonces: "device_nonces",
+	Likes:        "likes",
+}
+
+var (
+	mu    sync.RWMutex
+	names = maps.Clone(defaults)
+)
+
+// Name 资源当前对应的集合名
+func (r Resource) Name() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	name, ok := names[r]
+	if !ok {
+		panic(fmt.Sprintf("collection: 未注册的资源 %q", r))
+	}
+	return name
+}
+
+// Default 资源的默认集合名，未注册时 ok=false
+func Default(r Resource) (name string, ok bool) {
+	name, ok = defaults[r]
+	return name, ok
+}
+
+// All 全部资源及当前集合名 (按资源标识排序)
+func All() []Resource {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Resource, 0, len(names))
+	for r := range names {
+		out = append(out, r)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Configure 覆盖集合名，未出现的资源保持默认值；未知资源或空名称返回错误且不做任何修改
+func Configure(overrides map[Resource]string) error {
+	next := maps.Clone(defaults)
+	for r, name := range overrides {
+		if _, ok := defaults[r]; !ok {
+			return fmt.Errorf("未知资源 %q", r)
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("资源 %s 的集合名为空", r)
+		}
+		next[r] = name
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	names = next
+	return nil
+}
+
+// Verify 逐个探测集合是否存在 (list 1 条，只取 _id)，返回全部失败项
+func Verify(ctx context.Context, ds store.DataStore) error {
+	var errs []error
+	probe := map[string]interface{}{"select": map[string]interface{}{"_id": true}}
+	for _, r := range All() {
+		name := r.Name()
+		if _, err := ds.ListData(ctx, name, probe, 1, 1); err != nil {
+			if errors.Is(err, tcb.ErrNotFound) {
+				err = errors.New("数据模型不存在")
+			}
+			errs = append(errs, fmt.Errorf("%s (%s): %w", r, name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package collection_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/tcbtest"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { _ = collection.Configure(nil) })
+
+	overrides := map[collection.Resource]string{collection.Photos: "photo_v2", collection.Comments: "comment_v2"}
+	if err := collection.Configure(overrides); err != nil {
+		t.Fatal(err)
+	}
+	if got := collection.Photos.Name(); got != "photo_v2" {
+		t.Errorf("Photos = %q", got)
+	}
+	if got := collection.POIs.Name(); got != "pois" {
+		t.Errorf("POIs = %q, want default", got)
+	}
+
+	// 非法配置不影响当前注册表
+	if err := collection.Configure(map[collection.Resource]string{"shops": "shop"}); err == nil {
+		t.Error("unknown resource accepted")
+	}
+	if got := collection.Photos.Name(); got != "photo_v2" {
+		t.Errorf("Photos after failed Configure = %q", got)
+	}
+	if err := collection.Configure(map[collection.Resource]string{collection.Photos: ""}); err == nil {
+		t.Error("empty name accepted")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	srv := tcbtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.DefineModels("regions", "pois", "themes", "photo", "comment", "product", "favorites", "admins", "devices", "device_nonces", "likes")
+
+	if err := collection.Verify(context.Background(), srv.Client()); err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+
+	t.Cleanup(func() { _ = collection.Configure(nil) })
+	_ = collection.Configure(map[collection.Resource]string{collection.POIs: "poi", collection.Themes: "theme"})
+	err := collection.Verify(context.Background(), srv.Client())
+	if err == nil {
+		t.Fatal("Verify passed with mistyped collection names")
+	}
+	for _, want := range []string{"pois (poi): 数据模型不存在", "themes (theme): 数据模型不存在"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/config"
+)
+
+// isolate 清空测试涉及的环境变量，并切换到临时目录 (避免读到仓库中的 .env / config.yaml)
+func isolate(t *testing.T) string {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(key, "CLOUDBASE_") || strings.HasPrefix(key, "CACHE_") || strings.HasPrefix(key, "CORS_") {
+			t.Setenv(key, "")
+		}
+	}
+	for _, key := range []string{"APP_PROFILE", "PORT", "DATA_STORE", "SCHEMA_CHECK", "COLLECTION_VERIFY", "PAGE_MAX_SIZE", "JWT_SECRET", "TCB_MODEL_STAGE", "WX_APPID", "WX_SECRET", "WX_LOGIN_STUB"} {
+		t.Setenv(key, "")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	return dir
+}
+
+func TestLoadFileThenEnv(t *testing.T) {
+	dir := isolate(t)
+	path := filepath.Join(dir, "app.yaml")
+	yaml := `
+server:
+  port: 9000
+tcb:
+  profile: staging
+  env_ids:
+    staging: tour-staging-3c4d
+  read_timeout: 3s
+cache:
+  models: [regions, pois]
+pagination:
+  max_size: 50
+auth:
+  jwt_secret: 0123456789abcdef0123456789abcdef
+  wx_login_stub: true
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("PORT", "9100") // 环境变量优先于文件
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("COLLECTION_NAMES", "photos=photo_v2")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr() != ":9100" {
+		t.Errorf("addr = %s", cfg.Server.Addr())
+	}
+	if cfg.TCB.ProfileEnvID("staging") != "tour-staging-3c4d" || cfg.TCB.ReadTimeout != 3*time.Second {
+		t.Errorf("tcb = %+v", cfg.TCB)
+	}
+	if cfg.TCB.WriteTimeout != 10*time.Second {
+		t.Errorf("default write timeout lost: %v", cfg.TCB.WriteTimeout)
+	}
+	if !cfg.Auth.WxLoginStub || cfg.Auth.TokenTTL != 72*time.Hour {
+		t.Errorf("auth = %+v", cfg.Auth)
+	}
+	if len(cfg.Cache.Models) != 2 || cfg.Pagination.MaxSize != 50 {
+		t.Errorf("cache = %+v, pagination = %+v", cfg.Cache, cfg.Pagination)
+	}
+	if len(cfg.CORS.AllowOrigins) != 2 || cfg.CORS.AllowOrigins[1] != "https://b.example.com" {
+		t.Errorf("cors origins = %q", cfg.CORS.AllowOrigins)
+	}
+	if cfg.Store.CollectionNames["photos"] != "photo_v2" || cfg.Store.VerifyModels != "warn" {
+		t.Errorf("store = %+v", cfg.Store)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	isolate(t)
+
+	// tcb 模式缺少环境 ID
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "CLOUDBASE_ENV_ID") {
+		t.Errorf("missing env id: err = %v", err)
+	}
+
+	t.Setenv("CLOUDBASE_ENV_ID", "tour-prod-1a2b")
+	t.Setenv("PAGE_MAX_SIZE", "abc")
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "PAGE_MAX_SIZE") {
+		t.Errorf("bad int: err = %v", err)
+	}
+
+	t.Setenv("PAGE_MAX_SIZE", "")
+	t.Setenv("APP_PROFILE", "prod")
+	t.Setenv("WX_LOGIN_STUB", "true")
+	t.Setenv("SCHEMA_CHECK", "loud")
+	t.Setenv("COLLECTION_VERIFY", "true")
+	t.Setenv("JWT_SECRET", "short")
+	_, err := config.Load()
+	if err == nil || !strings.Contains(err.Error(), "SCHEMA_CHECK") || !strings.Contains(err.Error(), "COLLECTION_VERIFY") || !strings.Contains(err.Error(), "JWT_SECRET") || !strings.Contains(err.Error(), "WX_LOGIN_STUB") {
+		t.Errorf("validation should report every problem: err = %v", err)
+	}
+
+	// YAML 中的未知字段 (拼写错误) 拒绝启动
+	t.Setenv("SCHEMA_CHECK", "")
+	t.Setenv("COLLECTION_VERIFY", "")
+	t.Setenv("JWT_SECRET", "")
+	if err := os.WriteFile("config.yaml", []byte("server:\n  prot: 9000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "prot") {
+		t.Errorf("unknown yaml field: err = %v", err)
+	}
+}
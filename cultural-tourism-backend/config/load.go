@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultFile 未设置 CONFIG_FILE 时尝试读取的配置文件 (不存在则跳过)
+const DefaultFile = "config.yaml"
+
+// profileNames 支持 CLOUDBASE_ENV_ID_<PROFILE> 单独指定环境 ID 的 profile
+var profileNames = []string{"dev", "staging", "prod"}
+
+// Load 加载配置：默认值 -> YAML 文件 -> 环境变量 (含 .env)，并校验
+// CONFIG_FILE 显式指定的文件必须存在；YAML 中出现未知字段视为错误 (防止拼写错误被静默忽略)
+func Load() (*Config, error) {
+	_ = godotenv.Load() // 加载 .env (本地开发用)
+
+	cfg := Default()
+
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = DefaultFile
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			if explicit || !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+	for _, name := range profileNames {
+		if id := os.Getenv("CLOUDBASE_ENV_ID_" + strings.ToUpper(name)); id != "" {
+			if cfg.TCB.EnvIDs == nil {
+				cfg.TCB.EnvIDs = make(map[string]string)
+			}
+			cfg.TCB.EnvIDs[name] = id
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// MustLoad 加载配置，失败直接 Panic
+func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		panic(fmt.Sprintf("配置错误:\n%v", err))
+	}
+	return cfg
+}
+
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	fmt.Printf("📄 已加载配置文件: %s\n", path)
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv 按 env tag 用环境变量覆盖字段 (未设置或为空的变量不覆盖)
+// 支持 string / int / bool / *bool / time.Duration / []string (逗号分隔) / map[string]string (k=v 逗号分隔)
+func applyEnv(v reflect.Value) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, sf := v.Field(i), t.Field(i)
+		key := sf.Tag.Get("env")
+		if key == "" {
+			if field.Kind() == reflect.Struct {
+				if err := applyEnv(field); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		raw := os.Getenv(key)
+		if raw == "" {
+			continue
+		}
+		if err := setField(field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q %v", key, raw, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, raw string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("不是合法的时长")
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("不是合法的整数")
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("不是合法的布尔值")
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("不是合法的布尔值")
+		}
+		field.Set(reflect.ValueOf(&b))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		field.Set(reflect.ValueOf(splitList(raw)))
+	case field.Kind() == reflect.Map && field.Type().Key().Kind() == reflect.String && field.Type().Elem().Kind() == reflect.String:
+		m := make(map[string]string)
+		for _, pair := range splitList(raw) {
+			k, val, ok := strings.Cut(pair, "=")
+			k, val = strings.TrimSpace(k), strings.TrimSpace(val)
+			if !ok || k == "" || val == "" {
+				return fmt.Errorf("格式应为 key=value，逗号分隔")
+			}
+			m[k] = val
+		}
+		field.Set(reflect.ValueOf(m))
+	default:
+		panic(fmt.Sprintf("config: 不支持的字段类型 %s", field.Type()))
+	}
+	return nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAdminProfile 当前用户的角色与权限
+// @Summary      当前管理员信息
+// @Description  返回登录用户的角色、负责区域与权限点，管理端据此渲染菜单 (未分配角色时返回空列表)
+// @Tags         Admin
+// @Produce      json
+// @Success      200  {object}  models.AdminProfile
+// @Failure      401  {object}  map[string]interface{}  "未登录"
+// @Router       /admin/me [get]
+func GetAdminProfile(c *gin.Context) {
+	profile, err := services.CurrentAdmin(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "查询失败")
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// ListAdmins 管理员列表
+// @Summary      管理员列表
+// @Description  已分配角色的用户 (需要 role:manage 权限)
+// @Tags         Admin
+// @Produce      json
+// @Param        page  query     int  false  "页码"
+// @Param        size  query     int  false  "每页数量"
+// @Success      200   {object}  tcb.Page[models.Admin]
+// @Failure      403   {object}  map[string]interface{}  "无权限"
+// @Router       /admin/roles [get]
+func ListAdmins(c *gin.Context) {
+	type Query struct {
+		Page int `form:"page,default=1"`
+		Size int `form:"size,default=20"`
+	}
+	var query Query
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limitPage(&query.Page, &query.Size)
+
+	result, err := services.ListAdmins(c.Request.Context(), query.Page, query.Size)
+	if err != nil {
+		respondError(c, err, "查询失败")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// SetAdminRoles 设置用户角色
+// @Summary      设置用户角色
+// @Description  整体替换指定用户的角色与负责区域 (需要 role:manage 权限)；region_ids 为空表示不限区域；不能移除自己的 super_admin
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        openid  path      string                    true  "用户 openid"
+// @Param        body    body      models.AdminRolesRequest  true  "角色列表"
+// @Success      200     {object}  models.Admin
+// @Failure      400     {object}  map[string]interface{}  "参数错误"
+// @Failure      403     {object}  map[string]interface{}  "无权限"
+// @Router       /admin/roles/{openid} [put]
+func SetAdminRoles(c *gin.Context) {
+	var req models.AdminRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	admin, err := services.SetAdminRoles(c.Request.Context(), c.Param("openid"), req)
+	if err != nil {
+		respondAdminError(c, err, "设置失败")
+		return
+	}
+	c.JSON(http.StatusOK, admin)
+}
+
+// RevokeAdmin 移除用户的全部角色
+// @Summary      移除管理员
+// @Description  移除指定用户的全部角色 (需要 role:manage 权限)；不能移除自己
+// @Tags         Admin
+// @Produce      json
+// @Param        openid  path      string  true  "用户 openid"
+// @Success      200     {object}  map[string]interface{}
+// @Failure      404     {object}  map[string]interface{}  "该用户未分配角色"
+// @Router       /admin/roles/{openid} [delete]
+func RevokeAdmin(c *gin.Context) {
+	openID := c.Param("openid")
+	if err := services.RevokeAdmin(c.Request.Context(), openID); err != nil {
+		if errors.Is(err, services.ErrAdminNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "该用户未分配角色"})
+			return
+		}
+		respondAdminError(c, err, "移除失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "openid": openID})
+}
+
+// respondAdminError 角色管理接口的错误输出 (角色或区域非法 / 移除自己返回 400，其余统一映射)
+func respondAdminError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_ROLE"})
+	case errors.Is(err, services.ErrInvalidRegion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_REGION"})
+	case errors.Is(err, services.ErrSelfDemotion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "SELF_DEMOTION"})
+	default:
+		respondError(c, err, action)
+	}
+}
@@ -0,0 +1,200 @@
+package controllers_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestAdminRoutesRequirePermission(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	grant(srv, "o_merchant", auth.RoleMerchantOperator)
+	grant(srv, "o_moderator", auth.RoleContentModerator)
+	user, merchant, moderator := login(t, r, "o_user"), login(t, r, "o_merchant"), login(t, r, "o_moderator")
+	region := map[string]interface{}{"name": "西湖"}
+	product := map[string]interface{}{"name": "龙井茶"}
+
+	// 旧的公开写路径保留同样的鉴权，并标明弃用
+	expectStatus(t, do(t, r, http.MethodPost, "/api/regions", region), http.StatusUnauthorized)
+	w := doAs(t, r, user, http.MethodPut, "/api/pois/p1", map[string]interface{}{"name": "断桥"})
+	expectStatus(t, w, http.StatusForbidden)
+	if got := w.Header().Get("Link"); got != `</api/admin/pois/p1>; rel="successor-version"` || w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Link = %q, Deprecation = %q", got, w.Header().Get("Deprecation"))
+	}
+	expectStatus(t, doAs(t, r, user, http.MethodDelete, "/api/products/p1", nil), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, merchant, http.MethodPost, "/api/products", product), http.StatusOK)
+
+	expectStatus(t, do(t, r, http.MethodPost, "/api/admin/regions", region), http.StatusUnauthorized)
+	w = doAs(t, r, user, http.MethodPost, "/api/admin/regions", region)
+	expectStatus(t, w, http.StatusForbidden)
+	if got := decode[errorResponse](t, w); got.Code != "FORBIDDEN" {
+		t.Errorf("code = %q, want FORBIDDEN", got.Code)
+	}
+
+	expectStatus(t, doAs(t, r, merchant, http.MethodPost, "/api/admin/regions", region), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, merchant, http.MethodPost, "/api/admin/products", product), http.StatusOK)
+	expectStatus(t, doAs(t, r, moderator, http.MethodPost, "/api/admin/products", product), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, merchant, http.MethodGet, "/api/admin/photos?status=0", nil), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, moderator, http.MethodGet, "/api/admin/photos?status=0", nil), http.StatusOK)
+	expectStatus(t, doAs(t, r, moderator, http.MethodGet, "/api/admin/roles", nil), http.StatusForbidden)
+}
+
+func TestAdminRoleAssignment(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	auth.SuperAdmins = map[string]bool{"o_root": true} // 引导超级管理员，无需 admins 记录
+	root, bob := login(t, r, "o_root"), login(t, r, "o_bob")
+	ids := srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: "t1", ImageURL: "1.jpg", OpenID: "o_alice"})
+
+	w := doAs(t, r, bob, http.MethodGet, "/api/admin/me", nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.AdminProfile](t, w); got.OpenID != "o_bob" || len(got.Roles) != 0 || len(got.Permissions) != 0 {
+		t.Errorf("profile before grant = %+v", got)
+	}
+	expectStatus(t, doAs(t, r, bob, http.MethodPut, "/api/admin/photos/"+ids[0], map[string]interface{}{"status": 1}), http.StatusForbidden)
+
+	expectStatus(t, doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_bob", map[string]interface{}{"roles": []string{"owner"}}), http.StatusBadRequest)
+	w = doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_bob", models.AdminRolesRequest{Roles: []string{"content_moderator"}, Remark: "审核组"})
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Admin](t, w); got.OpenID != "o_bob" || len(got.Roles) != 1 || got.ID == "" {
+		t.Errorf("assigned = %+v", got)
+	}
+
+	w = doAs(t, r, bob, http.MethodGet, "/api/admin/me", nil)
+	if got := decode[models.AdminProfile](t, w); !slices.Contains(got.Permissions, string(auth.PermModerate)) {
+		t.Errorf("profile after grant = %+v", got)
+	}
+	expectStatus(t, doAs(t, r, bob, http.MethodPut, "/api/admin/photos/"+ids[0], map[string]interface{}{"status": 1}), http.StatusOK)
+
+	// 再次设置为整体替换，不产生重复记录
+	expectStatus(t, doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_bob", models.AdminRolesRequest{Roles: []string{"merchant_operator"}}), http.StatusOK)
+	w = doAs(t, r, root, http.MethodGet, "/api/admin/roles", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Admin]](t, w); len(page.Items) != 1 || page.Items[0].Roles[0] != "merchant_operator" || page.Items[0].Remark != "审核组" {
+		t.Fatalf("admins = %+v", page.Items)
+	}
+
+	// 不能移除自己的超级管理员角色
+	w = doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_root", models.AdminRolesRequest{Roles: []string{"content_moderator"}})
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decode[errorResponse](t, w); got.Code != "SELF_DEMOTION" {
+		t.Errorf("code = %q, want SELF_DEMOTION", got.Code)
+	}
+
+	expectStatus(t, doAs(t, r, root, http.MethodDelete, "/api/admin/roles/o_bob", nil), http.StatusOK)
+	expectStatus(t, doAs(t, r, root, http.MethodDelete, "/api/admin/roles/o_bob", nil), http.StatusNotFound)
+	expectStatus(t, doAs(t, r, bob, http.MethodGet, "/api/admin/photos?status=0", nil), http.StatusForbidden)
+}
+
+func TestRegionScopedModerator(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	srv.Seed(collection.Admins.Name(), models.Admin{OpenID: "o_local", Roles: []string{"content_moderator"}, RegionIDs: []string{"r1"}})
+	local := login(t, r, "o_local")
+
+	themes := srv.Seed(collection.Themes.Name(), models.Theme{Name: "本区", RegionID: "r1", Status: 1}, models.Theme{Name: "外区", RegionID: "r2", Status: 1})
+	pois := srv.Seed(collection.POIs.Name(), models.POI{Name: "本区", RegionID: "r1", Status: 1}, models.POI{Name: "外区", RegionID: "r2", Status: 0})
+	photos := srv.Seed(collection.Photos.Name(),
+		models.Photo{ThemeID: themes[0], ImageURL: "in.jpg", OpenID: "o_alice"},
+		models.Photo{ThemeID: themes[1], ImageURL: "out.jpg", OpenID: "o_alice"},
+	)
+	comments := srv.Seed(collection.Comments.Name(),
+		models.Comment{POIID: pois[0], Content: "本区", OpenID: "o_alice"},
+		models.Comment{POIID: pois[1], Content: "外区", OpenID: "o_alice"},
+	)
+
+	// 只能看到负责区域内的待审内容与点位 / 主题
+	w := doAs(t, r, local, http.MethodGet, "/api/admin/photos?status=0", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Photo]](t, w); len(page.Items) != 1 || page.Items[0].ImageURL != "in.jpg" {
+		t.Errorf("photos = %+v", page.Items)
+	}
+	w = doAs(t, r, local, http.MethodGet, "/api/admin/photos?status=0&theme_id="+themes[1], nil)
+	expectStatus(t, w, http.StatusForbidden)
+	if got := decode[errorResponse](t, w); got.Code != "OUT_OF_SCOPE" {
+		t.Errorf("code = %q, want OUT_OF_SCOPE", got.Code)
+	}
+	w = doAs(t, r, local, http.MethodGet, "/api/admin/comments?status=0", nil)
+	if page := decode[tcb.Page[models.Comment]](t, w); len(page.Items) != 1 || page.Items[0].Content != "本区" {
+		t.Errorf("comments = %+v", page.Items)
+	}
+	w = doAs(t, r, local, http.MethodGet, "/api/admin/pois", nil)
+	if page := decode[tcb.Page[models.POI]](t, w); len(page.Items) != 1 || page.Items[0].Name != "本区" {
+		t.Errorf("pois = %+v", page.Items)
+	}
+	w = doAs(t, r, local, http.MethodGet, "/api/admin/themes", nil)
+	if page := decode[tcb.Page[models.Theme]](t, w); len(page.Items) != 1 || page.Items[0].Name != "本区" {
+		t.Errorf("themes = %+v", page.Items)
+	}
+
+	// 只能审核 / 删除负责区域内的内容
+	expectStatus(t, doAs(t, r, local, http.MethodPut, "/api/admin/photos/"+photos[1], map[string]interface{}{"status": 1}), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, local, http.MethodPut, "/api/admin/photos/"+photos[0], map[string]interface{}{"status": 1}), http.StatusOK)
+	expectStatus(t, doAs(t, r, local, http.MethodDelete, "/api/admin/comments/"+comments[1], nil), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, local, http.MethodDelete, "/api/admin/comments/"+comments[0], nil), http.StatusOK)
+
+	// 点位 / 主题只能在负责区域内维护，不能移出；区域本身不能新建
+	expectStatus(t, doAs(t, r, local, http.MethodPost, "/api/admin/themes", map[string]interface{}{"name": "新主题", "region_id": "r2"}), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, local, http.MethodPost, "/api/admin/themes", map[string]interface{}{"name": "新主题", "region_id": "r1"}), http.StatusOK)
+	expectStatus(t, doAs(t, r, local, http.MethodPut, "/api/admin/themes/"+themes[0], map[string]interface{}{"region_id": "r2"}), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, local, http.MethodDelete, "/api/admin/pois/"+pois[1], nil), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, local, http.MethodPost, "/api/admin/regions", map[string]interface{}{"name": "新区"}), http.StatusForbidden)
+}
+
+func TestRegionScopedModeratorWithManyThemes(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	srv.Seed(collection.Admins.Name(), models.Admin{OpenID: "o_local", Roles: []string{"content_moderator"}, RegionIDs: []string{"r1"}})
+	local := login(t, r, "o_local")
+
+	// 区域内主题超过单个 $in 的分组大小：拆分后仍能查到最后一个主题下的照片
+	seedThemes := func(n int) []string {
+		themes := make([]interface{}, n)
+		for i := range themes {
+			themes[i] = models.Theme{Name: "本区", RegionID: "r1", Status: 1}
+		}
+		return srv.Seed(collection.Themes.Name(), themes...)
+	}
+	themes := seedThemes(250)
+	srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: themes[len(themes)-1], ImageURL: "last.jpg", OpenID: "o_alice"})
+	w := doAs(t, r, local, http.MethodGet, "/api/admin/photos?status=0", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Photo]](t, w); len(page.Items) != 1 || page.Items[0].ImageURL != "last.jpg" {
+		t.Errorf("photos = %+v", page.Items)
+	}
+
+	// 超过上限时要求指定主题筛选，而不是向网关下发超长条件
+	seedThemes(1000)
+	w = doAs(t, r, local, http.MethodGet, "/api/admin/photos?status=0", nil)
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decode[errorResponse](t, w); got.Code != "SCOPE_TOO_LARGE" {
+		t.Errorf("code = %q, want SCOPE_TOO_LARGE", got.Code)
+	}
+	expectStatus(t, doAs(t, r, local, http.MethodGet, "/api/admin/photos?status=0&theme_id="+themes[0], nil), http.StatusOK)
+}
+
+func TestAssignRegionScope(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	auth.SuperAdmins = map[string]bool{"o_root": true}
+	root := login(t, r, "o_root")
+	regions := srv.Seed(collection.Regions.Name(), models.Region{Name: "西湖", Status: 1})
+
+	w := doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_local", models.AdminRolesRequest{Roles: []string{"content_moderator"}, RegionIDs: []string{"missing"}})
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decode[errorResponse](t, w); got.Code != "INVALID_REGION" {
+		t.Errorf("code = %q, want INVALID_REGION", got.Code)
+	}
+
+	expectStatus(t, doAs(t, r, root, http.MethodPut, "/api/admin/roles/o_local", models.AdminRolesRequest{Roles: []string{"content_moderator"}, RegionIDs: regions}), http.StatusOK)
+	w = doAs(t, r, login(t, r, "o_local"), http.MethodGet, "/api/admin/me", nil)
+	if got := decode[models.AdminProfile](t, w); !slices.Equal(got.RegionIDs, regions) {
+		t.Errorf("profile = %+v, want region_ids %v", got, regions)
+	}
+}
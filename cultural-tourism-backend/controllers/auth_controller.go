@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WxLogin 小程序登录
+// @Summary      小程序登录
+// @Description  用 wx.login 获取的 code 换取 openid，并签发登录令牌 (后续请求携带 Authorization: Bearer <token>)
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.WxLoginRequest  true  "登录凭证"
+// @Success      200   {object}  models.WxLoginResponse
+// @Failure      401   {object}  map[string]interface{}  "code 无效或已过期"
+// @Router       /auth/wx-login [post]
+func WxLogin(c *gin.Context) {
+	var req models.WxLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+	if auth.Sessions == nil || auth.Tokens == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "登录服务未配置"})
+		return
+	}
+
+	session, err := auth.Sessions.Code2Session(c.Request.Context(), req.Code)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCode) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "登录凭证无效或已过期，请重新登录", "code": "INVALID_CODE"})
+			return
+		}
+		log.Printf("❌ %s %s -> %d: %v", c.Request.Method, c.FullPath(), http.StatusBadGateway, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "登录失败: 微信服务暂时不可用"})
+		return
+	}
+
+	token, exp, err := auth.Tokens.Issue(session.OpenID)
+	if err != nil {
+		respondError(c, err, "登录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WxLoginResponse{
+		Token:     token,
+		ExpiresAt: exp.Format(time.RFC3339),
+		OpenID:    session.OpenID,
+	})
+}
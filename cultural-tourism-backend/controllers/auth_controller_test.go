@@ -0,0 +1,76 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/services"
+	"cultural-tourism-backend/tcbtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupAuth 使用本地替身登录 (code "openid:<id>" 登录为指定用户)，角色取自 admins 集合，测试结束后恢复
+func setupAuth(t *testing.T) {
+	t.Helper()
+	tokens, err := auth.NewIssuer("test-secret-0123456789abcdef0123456789", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevTokens, prevSessions, prevRoles, prevSuper := auth.Tokens, auth.Sessions, auth.RoleStore, auth.SuperAdmins
+	auth.Tokens, auth.Sessions, auth.RoleStore, auth.SuperAdmins = tokens, auth.StubExchanger{}, services.AdminRoleSource{}, nil
+	t.Cleanup(func() {
+		auth.Tokens, auth.Sessions, auth.RoleStore, auth.SuperAdmins = prevTokens, prevSessions, prevRoles, prevSuper
+	})
+}
+
+// login 以指定 openid 登录并返回令牌 (需先调用 setupAuth)
+func login(t *testing.T, r *gin.Engine, openID string) string {
+	t.Helper()
+	w := do(t, r, http.MethodPost, "/api/auth/wx-login", models.WxLoginRequest{Code: "openid:" + openID})
+	expectStatus(t, w, http.StatusOK)
+	return decode[models.WxLoginResponse](t, w).Token
+}
+
+// grant 为 openid 分配管理员角色 (直接写入 admins 集合)
+func grant(srv *tcbtest.Server, openID string, roles ...auth.Role) {
+	admin := models.Admin{OpenID: openID}
+	for _, r := range roles {
+		admin.Roles = append(admin.Roles, string(r))
+	}
+	srv.Seed(collection.Admins.Name(), admin)
+}
+
+// loginAdmin 以拥有 roles 的管理员 (o_admin) 身份登录
+func loginAdmin(t *testing.T, srv *tcbtest.Server, r *gin.Engine, roles ...auth.Role) string {
+	t.Helper()
+	setupAuth(t)
+	grant(srv, "o_admin", roles...)
+	return login(t, r, "o_admin")
+}
+
+func TestWxLogin(t *testing.T) {
+	_, r := setup(t)
+	setupAuth(t)
+
+	expectStatus(t, do(t, r, http.MethodPost, "/api/auth/wx-login", map[string]string{}), http.StatusBadRequest)
+
+	w := do(t, r, http.MethodPost, "/api/auth/wx-login", models.WxLoginRequest{Code: "openid:o_alice"})
+	expectStatus(t, w, http.StatusOK)
+	resp := decode[models.WxLoginResponse](t, w)
+	if resp.OpenID != "o_alice" || resp.Token == "" || resp.ExpiresAt == "" {
+		t.Fatalf("login response = %+v", resp)
+	}
+
+	// 令牌可用于后续请求；伪造的令牌返回 401
+	expectStatus(t, doAs(t, r, resp.Token, http.MethodGet, "/api/regions", nil), http.StatusOK)
+	w = doAs(t, r, resp.Token+"x", http.MethodGet, "/api/regions", nil)
+	expectStatus(t, w, http.StatusUnauthorized)
+	if code := decode[errorResponse](t, w).Code; code != "INVALID_TOKEN" {
+		t.Errorf("code = %q", code)
+	}
+}
@@ -6,14 +6,14 @@ import (
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
 
-
 // CreateComment 发布评论
 // @Summary      发布评论
-// @Description  用户发布评论 (默认待审核 status=0)
+// @Description  登录用户发布评论 (默认待审核 status=0)
 // @Tags         Comments
 // @Accept       json
 // @Produce      json
@@ -27,14 +27,14 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CreateComment(&comment)
+	id, err := services.CreateComment(c.Request.Context(), &comment)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "发布失败: " + err.Error()})
+		respondError(c, err, "发布失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetCommentList 获取评论列表
@@ -42,10 +42,11 @@ func CreateComment(c *gin.Context) {
 // @Description  默认只显示审核通过(status=1)的评论
 // @Tags         Comments
 // @Param        poi_id  query  string  false  "点位ID"
-// @Param        status  query  int     false  "状态 (1:通过, 0:待审)"
+// @Param        status  query  int     false  "状态 (1:通过, 0:待审)，非 1 需审核权限"
 // @Param        page    query  int     false  "页码"
 // @Param        size    query  int     false  "每页数量"
-// @Success      200     {object}  map[string]interface{}
+// @Param        fields  query  string  false  "字段投影，逗号分隔 (如 content,like_count)"
+// @Success      200     {object}  tcb.Page[models.Comment]
 // @Router       /comments [get]
 func GetCommentList(c *gin.Context) {
 	var query models.CommentQuery
@@ -53,22 +54,28 @@ func GetCommentList(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	limitPage(&query.Page, &query.Size)
+	fields, ok := bindFields(c, "comments")
+	if !ok {
+		return
+	}
+	query.Fields = fields
 
-	result, err := services.ListComments(query)
+	result, err := services.ListComments(c.Request.Context(), query)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询失败: " + err.Error()})
+		respondError(c, err, "查询失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, query.Fields)
 }
 
 // GetCommentDetail 获取评论详情
 // @Summary      获取评论详情
 // @Tags         Comments
 // @Param        id   path      string  true  "评论ID"
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  models.Comment
 // @Router       /comments/{id} [get]
 func GetCommentDetail(c *gin.Context) {
 	id := c.Param("id")
@@ -77,24 +84,26 @@ func GetCommentDetail(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetCommentDetail(id)
+	result, err := services.GetCommentDetail(c.Request.Context(), id)
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "评论不存在"})
+		respondDetailError(c, err, "评论不存在")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
-// UpdateComment 更新评论 (审核/点赞)
-// @Summary      更新评论 (审核/点赞)
-// @Description  用于管理员审核 (修改status) 或 用户点赞 (修改like_count)
+// UpdateComment 更新评论 (审核)
+// @Summary      更新评论 (审核)
+// @Description  管理员审核 (修改status)；发布者可修改内容 (修改后重新待审)。点赞请使用 /like 接口
 // @Tags         Comments
 // @Accept       json
 // @Produce      json
 // @Param        id       path      string         true  "评论ID"
-// @Param        comment  body      models.Comment true  "更新内容 (仅status/like_count)"
+// @Param        comment  body      models.Comment true  "更新内容 (status / content)"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200      {object}  map[string]interface{}
 // @Router       /comments/{id} [put]
 func UpdateComment(c *gin.Context) {
@@ -105,27 +114,52 @@ func UpdateComment(c *gin.Context) {
 		return
 	}
 
-	if err := services.UpdateComment(id, comment); err != nil {
+	expected, ok := bindPrecondition(c, services.GetCommentDetail)
+	if !ok {
+		return
+	}
+
+	if err := services.UpdateComment(c.Request.Context(), id, comment, expected); err != nil {
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+		respondError(c, err, "更新失败")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
+// LikeComment 点赞评论
+// @Summary      点赞评论
+// @Description  需要登录；每个用户对同一评论只能点赞一次。点赞数原子加一，返回最新点赞数
+// @Tags         Comments
+// @Param        id   path      string  true  "评论ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{}  "未登录"
+// @Failure      409  {object}  map[string]interface{}  "已点赞"
+// @Router       /comments/{id}/like [post]
+func LikeComment(c *gin.Context) {
+	id := c.Param("id")
+	count, err := services.LikeComment(c.Request.Context(), id)
+	if err != nil {
+		respondLikeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id, "like_count": count})
+}
+
 // DeleteComment 删除评论
 // @Summary      删除评论
-// @Description  管理员可删除违规评论，用户可删除自己的评论(依赖TCB权限)
+// @Description  发布者可删除自己的评论，管理员可删除任意评论
 // @Tags         Comments
 // @Param        id   path      string  true  "评论ID"
 // @Success      200  {object}  map[string]interface{}
 // @Router       /comments/{id} [delete]
 func DeleteComment(c *gin.Context) {
 	id := c.Param("id")
-	if err := services.DeleteComment(id); err != nil {
+	if err := services.DeleteComment(c.Request.Context(), id); err != nil {
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		respondError(c, err, "删除失败")
 		return
 	}
 
@@ -0,0 +1,95 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreateCommentIsPendingReview(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+
+	w := doAs(t, r, login(t, r, "o_alice"), http.MethodPost, "/api/comments", map[string]interface{}{"poi_id": "p1", "content": "好看", "status": 1})
+	expectStatus(t, w, http.StatusOK)
+
+	rec := srv.Records(collection.Comments.Name())[0]
+	if rec["status"] != float64(0) || rec["content"] != "好看" || rec["_openid"] != "o_alice" {
+		t.Errorf("record = %v, want pending comment", rec)
+	}
+}
+
+func TestGetCommentListByPOI(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.Comments.Name(),
+		models.Comment{POIID: "p1", Content: "已过审", Status: 1},
+		models.Comment{POIID: "p1", Content: "待审", Status: 0},
+		models.Comment{POIID: "p2", Content: "别处", Status: 1},
+	)
+
+	w := do(t, r, http.MethodGet, "/api/comments?poi_id=p1", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Comment]](t, w)
+	if len(page.Items) != 1 || page.Items[0].Content != "已过审" {
+		t.Fatalf("items = %+v", page.Items)
+	}
+
+	// 待审评论仅审核员可查
+	expectStatus(t, do(t, r, http.MethodGet, "/api/comments?poi_id=p1&status=0", nil), http.StatusUnauthorized)
+	moderator := loginAdmin(t, srv, r, auth.RoleContentModerator)
+	w = doAs(t, r, moderator, http.MethodGet, "/api/admin/comments?poi_id=p1&status=0", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Comment]](t, w); len(page.Items) != 1 || page.Items[0].Content != "待审" {
+		t.Fatalf("pending items = %+v", page.Items)
+	}
+}
+
+func TestCommentDetailUpdateDelete(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleContentModerator)
+	ids := srv.Seed(collection.Comments.Name(), models.Comment{POIID: "p1", Content: "好看", OpenID: "o_alice"})
+
+	w := do(t, r, http.MethodGet, "/api/comments/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Comment](t, w); got.Content != "好看" {
+		t.Errorf("comment = %+v", got)
+	}
+
+	w = doAs(t, r, admin, http.MethodPut, "/api/comments/"+ids[0], map[string]interface{}{"status": 2})
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Comments.Name())[0]; rec["status"] != float64(2) {
+		t.Errorf("status after review = %v", rec["status"])
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/comments/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = do(t, r, http.MethodGet, "/api/comments/"+ids[0], nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if got := decode[errorResponse](t, w); got.Error != "评论不存在" {
+		t.Errorf("error = %q", got.Error)
+	}
+}
+
+func TestCommentOwnership(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	alice, bob := login(t, r, "o_alice"), login(t, r, "o_bob")
+	ids := srv.Seed(collection.Comments.Name(), models.Comment{POIID: "p1", Content: "好看", Status: 1, OpenID: "o_alice"})
+
+	expectStatus(t, doAs(t, r, bob, http.MethodDelete, "/api/comments/"+ids[0], nil), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, bob, http.MethodPut, "/api/comments/"+ids[0], map[string]interface{}{"content": "改"}), http.StatusForbidden)
+	expectStatus(t, doAs(t, r, alice, http.MethodPut, "/api/comments/"+ids[0], map[string]interface{}{"status": 1}), http.StatusForbidden)
+
+	// 发布者修改内容后重新进入待审
+	expectStatus(t, doAs(t, r, alice, http.MethodPut, "/api/comments/"+ids[0], map[string]interface{}{"content": "很好看"}), http.StatusOK)
+	if rec := srv.Records(collection.Comments.Name())[0]; rec["content"] != "很好看" || rec["status"] != float64(0) {
+		t.Errorf("record after edit = %v, want pending", rec)
+	}
+
+	expectStatus(t, doAs(t, r, alice, http.MethodDelete, "/api/comments/"+ids[0], nil), http.StatusOK)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDevices 旅拍机设备列表
+// @Summary      旅拍机设备列表
+// @Description  已登记的旅拍机设备，不返回签名密钥 (需要 device:manage 权限)
+// @Tags         Devices
+// @Produce      json
+// @Param        poi_id  query     string  false  "旅拍机点位ID"
+// @Param        page    query     int     false  "页码"
+// @Param        size    query     int     false  "每页数量"
+// @Success      200     {object}  tcb.Page[models.Device]
+// @Failure      403     {object}  map[string]interface{}  "无权限"
+// @Router       /admin/devices [get]
+func ListDevices(c *gin.Context) {
+	var q models.DeviceQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limitPage(&q.Page, &q.Size)
+
+	result, err := services.ListDevices(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err, "查询失败")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// IssueDevice 登记旅拍机设备
+// @Summary      登记旅拍机设备
+// @Description  为旅拍机点位 (type=booth) 签发设备 ID 与签名密钥 (需要 device:manage 权限)；密钥只返回这一次
+// @Tags         Devices
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.DeviceRequest  true  "所属点位"
+// @Success      200   {object}  models.DeviceCredential
+// @Failure      400   {object}  map[string]interface{}  "参数错误或点位不是旅拍机"
+// @Router       /admin/devices [post]
+func IssueDevice(c *gin.Context) {
+	var req models.DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	credential, err := services.IssueDevice(c.Request.Context(), req)
+	if err != nil {
+		respondDeviceError(c, err, "登记失败")
+		return
+	}
+	c.JSON(http.StatusOK, credential)
+}
+
+// RotateDeviceSecret 轮换设备密钥
+// @Summary      轮换设备密钥
+// @Description  重新生成签名密钥并启用设备，旧密钥立即失效 (需要 device:manage 权限)；新密钥只返回这一次
+// @Tags         Devices
+// @Produce      json
+// @Param        id   path      string  true  "设备ID"
+// @Success      200  {object}  models.DeviceCredential
+// @Failure      404  {object}  map[string]interface{}  "设备不存在"
+// @Router       /admin/devices/{id}/rotate [post]
+func RotateDeviceSecret(c *gin.Context) {
+	credential, err := services.RotateDeviceSecret(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDeviceError(c, err, "轮换失败")
+		return
+	}
+	c.JSON(http.StatusOK, credential)
+}
+
+// RevokeDevice 吊销设备
+// @Summary      吊销设备
+// @Description  删除设备凭证，该设备后续请求返回 401 (需要 device:manage 权限)
+// @Tags         Devices
+// @Produce      json
+// @Param        id   path      string  true  "设备ID"
+// @Success      200  {object}  map[string]interface{}
+// @Router       /admin/devices/{id} [delete]
+func RevokeDevice(c *gin.Context) {
+	id := c.Param("id")
+	if err := services.RevokeDevice(c.Request.Context(), id); err != nil {
+		respondDeviceError(c, err, "吊销失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
+}
+
+// GetBoothProfile 当前旅拍机点位
+// @Summary      当前旅拍机点位
+// @Description  设备签名请求：返回调用方设备所属的旅拍机点位。请求头 X-Device-Id / X-Timestamp / X-Nonce / X-Signature，
+// @Description  签名为 HMAC-SHA256(密钥, "METHOD\n路径(含查询串)\n时间戳\nnonce\n请求体SHA256") 的十六进制编码
+// @Tags         Devices
+// @Produce      json
+// @Success      200  {object}  models.POI
+// @Failure      401  {object}  map[string]interface{}  "签名无效 / 过期 / 重放"
+// @Router       /device/booth [get]
+func GetBoothProfile(c *gin.Context) {
+	booth, err := services.CurrentBooth(c.Request.Context())
+	if err != nil {
+		respondDetailError(c, err, "旅拍机点位不存在")
+		return
+	}
+	c.JSON(http.StatusOK, booth)
+}
+
+// respondDeviceError 设备管理接口的错误输出 (点位不是旅拍机返回 400，其余统一映射)
+func respondDeviceError(c *gin.Context, err error, action string) {
+	if errors.Is(err, services.ErrNotBooth) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "NOT_BOOTH"})
+		return
+	}
+	respondError(c, err, action)
+}
@@ -0,0 +1,183 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/services"
+	"cultural-tourism-backend/tcb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupDevices 设备签名校验指向 devices 集合，测试结束后恢复
+func setupDevices(t *testing.T) {
+	t.Helper()
+	verifier, err := auth.NewDeviceVerifier(services.BoothDeviceSource{}, &services.DeviceNonceStore{}, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := auth.Devices
+	auth.Devices = verifier
+	t.Cleanup(func() { auth.Devices = prev })
+}
+
+// doSigned 以设备凭证签名发起请求
+func doSigned(t *testing.T, r *gin.Engine, cred models.DeviceCredential, nonce, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(method, path, bytes.NewReader(buf.Bytes()))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(auth.HeaderDeviceID, cred.DeviceID)
+	req.Header.Set(auth.HeaderTimestamp, ts)
+	req.Header.Set(auth.HeaderNonce, nonce)
+	req.Header.Set(auth.HeaderSignature, auth.Sign(cred.Secret, method, path, ts, nonce, buf.Bytes()))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeviceCredentials(t *testing.T) {
+	srv, r := setup(t)
+	setupDevices(t)
+	token := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	pois := srv.Seed(collection.POIs.Name(),
+		models.POI{Name: "西湖旅拍机", Type: models.POITypeBooth, Status: 1},
+		models.POI{Name: "断桥", Type: models.POITypeScenic, Status: 1},
+	)
+
+	// 只能登记到旅拍机点位；内容审核员无设备管理权限
+	w := doAs(t, r, token, http.MethodPost, "/api/admin/devices", models.DeviceRequest{POIID: pois[1]})
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decode[errorResponse](t, w); got.Code != "NOT_BOOTH" {
+		t.Errorf("code = %q, want NOT_BOOTH", got.Code)
+	}
+	grant(srv, "o_mod", auth.RoleContentModerator)
+	expectStatus(t, doAs(t, r, login(t, r, "o_mod"), http.MethodPost, "/api/admin/devices", models.DeviceRequest{POIID: pois[0]}), http.StatusForbidden)
+
+	w = doAs(t, r, token, http.MethodPost, "/api/admin/devices", models.DeviceRequest{POIID: pois[0], Remark: "1 号机"})
+	expectStatus(t, w, http.StatusOK)
+	cred := decode[models.DeviceCredential](t, w)
+	if cred.DeviceID == "" || len(cred.Secret) != 64 || cred.POIID != pois[0] {
+		t.Fatalf("credential = %+v", cred)
+	}
+
+	// 列表不返回密钥
+	w = doAs(t, r, token, http.MethodGet, "/api/admin/devices?poi_id="+pois[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Device]](t, w); len(page.Items) != 1 || page.Items[0].Secret != "" || page.Items[0].Remark != "1 号机" {
+		t.Errorf("devices = %+v", page.Items)
+	}
+
+	// 签名请求解析出所属旅拍机点位；重放被拒绝
+	w = doSigned(t, r, cred, "nonce-0001", http.MethodGet, "/api/device/booth", nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.POI](t, w); got.ID != pois[0] {
+		t.Errorf("booth = %+v", got)
+	}
+	w = doSigned(t, r, cred, "nonce-0001", http.MethodGet, "/api/device/booth", nil)
+	expectStatus(t, w, http.StatusUnauthorized)
+	if got := decode[errorResponse](t, w); got.Code != "NONCE_REPLAYED" {
+		t.Errorf("code = %q, want NONCE_REPLAYED", got.Code)
+	}
+	expectStatus(t, do(t, r, http.MethodGet, "/api/device/booth", nil), http.StatusUnauthorized)
+
+	// nonce 登记在共享集合中：另一个实例 (独立的校验器) 同样拒绝重放
+	setupDevices(t)
+	w = doSigned(t, r, cred, "nonce-0001", http.MethodGet, "/api/device/booth", nil)
+	expectStatus(t, w, http.StatusUnauthorized)
+	if got := decode[errorResponse](t, w); got.Code != "NONCE_REPLAYED" {
+		t.Errorf("replay on another instance: code = %q, want NONCE_REPLAYED", got.Code)
+	}
+
+	// 轮换后旧密钥立即失效
+	w = doAs(t, r, token, http.MethodPost, "/api/admin/devices/"+cred.DeviceID+"/rotate", nil)
+	expectStatus(t, w, http.StatusOK)
+	rotated := decode[models.DeviceCredential](t, w)
+	if rotated.DeviceID != cred.DeviceID || rotated.Secret == cred.Secret {
+		t.Fatalf("rotated = %+v", rotated)
+	}
+	w = doSigned(t, r, cred, "nonce-0002", http.MethodGet, "/api/device/booth", nil)
+	expectStatus(t, w, http.StatusUnauthorized)
+	if got := decode[errorResponse](t, w); got.Code != "INVALID_SIGNATURE" {
+		t.Errorf("code = %q, want INVALID_SIGNATURE", got.Code)
+	}
+	expectStatus(t, doSigned(t, r, rotated, "nonce-0003", http.MethodGet, "/api/device/booth", nil), http.StatusOK)
+
+	// 吊销后设备不可用，响应与签名错误一致，不暴露设备是否存在
+	expectStatus(t, doAs(t, r, token, http.MethodDelete, "/api/admin/devices/"+cred.DeviceID, nil), http.StatusOK)
+	w = doSigned(t, r, rotated, "nonce-0004", http.MethodGet, "/api/device/booth", nil)
+	expectStatus(t, w, http.StatusUnauthorized)
+	if got := decode[errorResponse](t, w); got.Code != "INVALID_SIGNATURE" {
+		t.Errorf("revoked: code = %q, want INVALID_SIGNATURE", got.Code)
+	}
+}
+
+// 去重只依赖 nonce_key 的唯一约束：假服务器对唯一字段冲突返回 400 而非 409
+func TestDeviceNonceStore(t *testing.T) {
+	srv, _ := setup(t)
+	ctx := context.Background()
+	nonces := &services.DeviceNonceStore{}
+	future := time.Now().Add(10 * time.Minute)
+
+	if err := nonces.UseNonce(ctx, "d1", "nonce-0001", future); err != nil {
+		t.Fatal(err)
+	}
+	if err := nonces.UseNonce(ctx, "d1", "nonce-0001", future); !errors.Is(err, auth.ErrNonceReplayed) {
+		t.Fatalf("replay: err = %v, want ErrNonceReplayed", err)
+	}
+	if err := nonces.UseNonce(ctx, "d2", "nonce-0001", future); err != nil {
+		t.Errorf("same nonce on another device: %v", err)
+	}
+
+	// 并发登记同一 nonce 只有一次成功
+	var (
+		wg sync.WaitGroup
+		ok atomic.Int32
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if nonces.UseNonce(ctx, "d1", "nonce-0002", future) == nil {
+				ok.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 1 {
+		t.Errorf("concurrent registrations succeeded %d times, want 1", ok.Load())
+	}
+
+	// 已过期未清理的记录被替换，之后重新进入有效期
+	if err := nonces.UseNonce(ctx, "d1", "nonce-0003", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nonces.UseNonce(ctx, "d1", "nonce-0003", future); err != nil {
+		t.Errorf("reuse after expiry: %v", err)
+	}
+	if err := nonces.UseNonce(ctx, "d1", "nonce-0003", future); !errors.Is(err, auth.ErrNonceReplayed) {
+		t.Errorf("replay after reuse: err = %v, want ErrNonceReplayed", err)
+	}
+	if got := len(srv.Records(collection.DeviceNonces.Name())); got != 4 {
+		t.Errorf("nonce records = %d, want 4", got)
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/services"
+	"cultural-tourism-backend/tcb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError 将底层错误映射为 HTTP 状态码并输出脱敏后的错误信息
+// action 为面向前端的操作描述 (如 "创建失败")，原始错误只记录在服务端日志中
+func respondError(c *gin.Context, err error, action string) {
+	status := http.StatusInternalServerError
+	message, code := action, ""
+
+	if current, ok := preconditionFailed(err); ok {
+		respondPreconditionFailed(c, current)
+		return
+	}
+
+	var apiErr *tcb.APIError
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		status, message, code = http.StatusUnauthorized, "请先登录", "UNAUTHENTICATED"
+	case errors.Is(err, auth.ErrOutOfScope):
+		status, message, code = http.StatusForbidden, "不在负责区域内", "OUT_OF_SCOPE"
+	case errors.Is(err, auth.ErrForbidden):
+		status, message, code = http.StatusForbidden, "无权执行该操作", "FORBIDDEN"
+	case errors.Is(err, services.ErrScopeTooLarge):
+		status, message, code = http.StatusBadRequest, "负责区域内内容过多，请指定主题或点位筛选", "SCOPE_TOO_LARGE"
+	case errors.Is(err, tcb.ErrNotFound):
+		status, message = http.StatusNotFound, "资源不存在"
+	case errors.Is(err, tcb.ErrConflict):
+		status, message = http.StatusConflict, "数据冲突，请刷新后重试"
+	case errors.Is(err, tcb.ErrCircuitOpen):
+		status, message = http.StatusServiceUnavailable, "服务繁忙，请稍后再试"
+	case errors.Is(err, tcb.ErrRateLimited):
+		status, message = http.StatusTooManyRequests, "请求过于频繁，请稍后再试"
+	case errors.Is(err, context.DeadlineExceeded):
+		status, message = http.StatusGatewayTimeout, action+": 服务响应超时"
+	case errors.Is(err, tcb.ErrUnexpectedResponse), errors.As(err, &apiErr):
+		status, message = http.StatusBadGateway, action+": 服务暂时不可用"
+	}
+
+	log.Printf("❌ %s %s -> %d: %v", c.Request.Method, c.FullPath(), status, err)
+
+	body := gin.H{"error": message}
+	if code != "" {
+		body["code"] = code
+	}
+	if apiErr != nil && apiErr.RequestID != "" {
+		body["request_id"] = apiErr.RequestID
+	}
+	c.JSON(status, body)
+}
+
+// respondDetailError 详情接口专用：记录不存在时返回资源相关的提示
+func respondDetailError(c *gin.Context, err error, notFoundMsg string) {
+	if errors.Is(err, tcb.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFoundMsg})
+		return
+	}
+	respondError(c, err, "查询失败")
+}
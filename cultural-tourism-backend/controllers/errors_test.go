@@ -0,0 +1,56 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestRespondErrorStatusMapping(t *testing.T) {
+	cases := []struct {
+		name       string
+		gateway    int // 注入的网关状态码 (读请求会重试 3 次)
+		want       int
+		wantReqID  bool
+		wantDetail string
+	}{
+		{"not found", http.StatusNotFound, http.StatusNotFound, false, "资源不存在"},
+		{"conflict", http.StatusConflict, http.StatusConflict, false, "数据冲突，请刷新后重试"},
+		{"rate limited", http.StatusTooManyRequests, http.StatusTooManyRequests, false, "请求过于频繁，请稍后再试"},
+		{"gateway 5xx", http.StatusServiceUnavailable, http.StatusBadGateway, true, "查询失败: 服务暂时不可用"},
+		{"gateway 4xx", http.StatusBadRequest, http.StatusBadGateway, true, "查询失败: 服务暂时不可用"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, r := setup(t)
+			srv.FailNext(collection.Regions.Name(), tc.gateway, 3)
+
+			w := do(t, r, http.MethodGet, "/api/regions", nil)
+			expectStatus(t, w, tc.want)
+			body := decode[map[string]interface{}](t, w)
+			if body["error"] != tc.wantDetail {
+				t.Errorf("error = %v, want %q", body["error"], tc.wantDetail)
+			}
+			if _, ok := body["request_id"]; ok != tc.wantReqID {
+				t.Errorf("request_id present = %v, want %v (body %v)", ok, tc.wantReqID, body)
+			}
+		})
+	}
+}
+
+func TestRespondErrorClientSideFailures(t *testing.T) {
+	// 熔断打开：503
+	_, r := setup(t)
+	tcb.Client.Breaker = tcb.NewCircuitBreaker(1, time.Hour)
+	tcb.Client.Breaker.Failure()
+	expectStatus(t, do(t, r, http.MethodGet, "/api/regions", nil), http.StatusServiceUnavailable)
+
+	// 网关超时：504
+	_, r = setup(t)
+	tcb.Client.Retry = tcb.RetryPolicy{}
+	tcb.Client.Timeouts.Read = time.Nanosecond
+	expectStatus(t, do(t, r, http.MethodGet, "/api/regions", nil), http.StatusGatewayTimeout)
+}
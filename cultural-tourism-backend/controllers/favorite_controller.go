@@ -3,6 +3,8 @@ package controllers
 import (
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -18,7 +20,7 @@ import (
 // @Success 200 {object} map[string]interface{} "收藏成功"
 // @Failure 400 {object} map[string]interface{} "参数错误或已收藏"
 // @Failure 500 {object} map[string]interface{} "服务器错误"
-// @Router /api/favorites [post]
+// @Router /favorites [post]
 func CreateFavorite(c *gin.Context) {
 	var req models.FavoriteCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,18 +33,18 @@ func CreateFavorite(c *gin.Context) {
 		ResourceID:   req.ResourceID,
 	}
 
-	result, err := services.CreateFavorite(favorite)
+	id, err := services.CreateFavorite(c.Request.Context(), favorite)
 	if err != nil {
 		// 已收藏错误返回 400
-		if err.Error() == "already favorited" {
+		if errors.Is(err, services.ErrAlreadyFavorited) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "already favorited", "code": "ALREADY_FAVORITED"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFavoriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // DeleteFavorite 取消收藏
@@ -66,13 +68,13 @@ func DeleteFavorite(c *gin.Context) {
 		return
 	}
 
-	err := services.DeleteFavorite(resourceType, resourceID)
+	err := services.DeleteFavorite(c.Request.Context(), resourceType, resourceID)
 	if err != nil {
-		if err.Error() == "favorite not found" {
+		if errors.Is(err, services.ErrFavoriteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "favorite not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFavoriteError(c, err)
 		return
 	}
 
@@ -86,21 +88,22 @@ func DeleteFavorite(c *gin.Context) {
 // @Produce json
 // @Param resource_type query string false "资源类型筛选 (theme/poi/product)"
 // @Param page query int false "页码 (默认1)" default(1)
-// @Param size query int false "每页数量 (默认20, 最大100)" default(20)
-// @Success 200 {object} map[string]interface{} "收藏列表"
+// @Param size query int false "每页数量 (默认20, 最大 PAGE_MAX_SIZE)" default(20)
+// @Success 200 {object} tcb.Page[models.Favorite] "收藏列表"
 // @Failure 400 {object} map[string]interface{} "参数错误"
 // @Failure 500 {object} map[string]interface{} "服务器错误"
-// @Router /api/favorites [get]
+// @Router /favorites [get]
 func ListFavorites(c *gin.Context) {
 	var req models.FavoriteListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	limitPage(&req.Page, &req.Size)
 
-	result, err := services.ListFavorites(req.ResourceType, req.Page, req.Size)
+	result, err := services.ListFavorites(c.Request.Context(), req.ResourceType, req.Page, req.Size)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFavoriteError(c, err)
 		return
 	}
 
@@ -127,11 +130,20 @@ func CheckFavoriteStatus(c *gin.Context) {
 		return
 	}
 
-	isFavorited, err := services.CheckFavoriteStatus(resourceType, resourceID)
+	isFavorited, err := services.CheckFavoriteStatus(c.Request.Context(), resourceType, resourceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondFavoriteError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"is_favorited": isFavorited})
 }
+
+// respondFavoriteError 收藏接口的错误输出 (参数错误返回 400，其余统一映射)
+func respondFavoriteError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidResourceType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondError(c, err, "操作失败")
+}
@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestFavoriteLifecycle(t *testing.T) {
+	_, r := setup(t)
+	setupAuth(t)
+	token := login(t, r, "o_alice")
+	body := map[string]interface{}{"resource_type": "poi", "resource_id": "p1"}
+
+	expectStatus(t, do(t, r, http.MethodPost, "/api/favorites", body), http.StatusUnauthorized)
+
+	w := doAs(t, r, token, http.MethodPost, "/api/favorites", body)
+	expectStatus(t, w, http.StatusOK)
+
+	w = doAs(t, r, token, http.MethodPost, "/api/favorites", body)
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decode[errorResponse](t, w); got.Code != "ALREADY_FAVORITED" {
+		t.Errorf("code = %q, want ALREADY_FAVORITED", got.Code)
+	}
+
+	w = doAs(t, r, token, http.MethodGet, "/api/favorites/poi/p1", nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]bool](t, w); !got["is_favorited"] {
+		t.Error("expected is_favorited = true")
+	}
+
+	w = doAs(t, r, token, http.MethodGet, "/api/favorites?resource_type=poi", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Favorite]](t, w); len(page.Items) != 1 || page.Items[0].ResourceID != "p1" {
+		t.Fatalf("items = %+v", page.Items)
+	}
+
+	w = doAs(t, r, token, http.MethodDelete, "/api/favorites/poi/p1", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = doAs(t, r, token, http.MethodDelete, "/api/favorites/poi/p1", nil)
+	expectStatus(t, w, http.StatusNotFound)
+
+	w = doAs(t, r, token, http.MethodGet, "/api/favorites/poi/p1", nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]bool](t, w); got["is_favorited"] {
+		t.Error("expected is_favorited = false after delete")
+	}
+}
+
+func TestFavoritesAreScopedToUser(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	alice, bob := login(t, r, "o_alice"), login(t, r, "o_bob")
+	body := map[string]interface{}{"resource_type": "poi", "resource_id": "p1"}
+
+	// 不同用户收藏同一资源互不冲突
+	expectStatus(t, doAs(t, r, alice, http.MethodPost, "/api/favorites", body), http.StatusOK)
+	expectStatus(t, doAs(t, r, bob, http.MethodPost, "/api/favorites", body), http.StatusOK)
+	for _, rec := range srv.Records(collection.Favorites.Name()) {
+		if rec["_openid"] != "o_alice" && rec["_openid"] != "o_bob" {
+			t.Errorf("record not stamped with owner: %v", rec)
+		}
+	}
+
+	expectStatus(t, doAs(t, r, alice, http.MethodPost, "/api/favorites",
+		map[string]interface{}{"resource_type": "theme", "resource_id": "t1"}), http.StatusOK)
+
+	w := doAs(t, r, bob, http.MethodGet, "/api/favorites", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Favorite]](t, w); len(page.Items) != 1 || page.Items[0].OpenID != "o_bob" {
+		t.Fatalf("bob sees %+v", page.Items)
+	}
+
+	// 取消收藏只影响自己的记录
+	expectStatus(t, doAs(t, r, bob, http.MethodDelete, "/api/favorites/theme/t1", nil), http.StatusNotFound)
+	expectStatus(t, doAs(t, r, bob, http.MethodDelete, "/api/favorites/poi/p1", nil), http.StatusOK)
+	w = doAs(t, r, alice, http.MethodGet, "/api/favorites/poi/p1", nil)
+	if got := decode[map[string]bool](t, w); !got["is_favorited"] {
+		t.Error("alice's favorite removed by bob")
+	}
+}
+
+func TestFavoriteRejectsInvalidResourceType(t *testing.T) {
+	_, r := setup(t)
+	setupAuth(t)
+	token := login(t, r, "o_alice")
+
+	w := doAs(t, r, token, http.MethodPost, "/api/favorites", map[string]interface{}{"resource_type": "user", "resource_id": "u1"})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	w = doAs(t, r, token, http.MethodGet, "/api/favorites/user/u1", nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
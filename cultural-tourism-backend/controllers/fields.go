@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"cultural-tourism-backend/tcb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldWhitelist 各资源列表接口 ?fields= 允许投影的字段
+// _openid 等内部字段不对外开放；_id 总是返回，无需声明
+var fieldWhitelist = map[string][]string{
+	"regions":  {"name", "status", "sort", "created_at", "updated_at"},
+	"pois":     {"name", "type", "region_id", "latitude", "longitude", "images", "desc", "address", "phone", "open_time", "status", "created_at", "updated_at", "_distance"},
+	"themes":   {"name", "cover", "desc", "region_id", "sort", "status", "created_at", "updated_at"},
+	"photos":   {"theme_id", "image_url", "status", "like_count", "created_at", "updated_at"},
+	"comments": {"poi_id", "parent_id", "content", "status", "like_count", "created_at", "updated_at"},
+	"products": {"name", "image", "price", "jump_app_id", "jump_path", "status", "created_at", "updated_at"},
+}
+
+// bindFields 解析 ?fields=name,latitude 并按白名单校验
+// 未传时返回 nil (返回完整记录)；校验失败时已写入 400 响应并返回 ok=false
+func bindFields(c *gin.Context, resource string) (fields []string, ok bool) {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil, true
+	}
+
+	allowed := fieldWhitelist[resource]
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || f == "_id" || slices.Contains(fields, f) {
+			continue
+		}
+		if !slices.Contains(allowed, f) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   fmt.Sprintf("不支持的字段: %s", f),
+				"allowed": allowed,
+			})
+			return nil, false
+		}
+		fields = append(fields, f)
+	}
+	return fields, true
+}
+
+// respondPage 输出分页结果；指定了 fields 时只输出这些字段 (及 _id)，
+// 避免类型化结构体把未查询的字段以零值形式返回给前端
+func respondPage[T any](c *gin.Context, page *tcb.Page[T], fields []string) {
+	if fields == nil {
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	items := make([]map[string]interface{}, 0, len(page.Items))
+	for _, item := range page.Items {
+		projected, err := project(item, fields)
+		if err != nil {
+			respondError(c, err, "查询失败")
+			return
+		}
+		items = append(items, projected)
+	}
+	c.JSON(http.StatusOK, tcb.Page[map[string]interface{}]{
+		Items: items,
+		Total: page.Total,
+		Page:  page.Page,
+		Size:  page.Size,
+	})
+}
+
+// project 把类型化记录转换为只含指定字段的 map
+func project(item interface{}, fields []string) (map[string]interface{}, error) {
+	full, err := toMap(item)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(fields)+1)
+	if id, ok := full["_id"]; ok {
+		out["_id"] = id
+	}
+	for _, f := range fields {
+		if v, ok := full[f]; ok {
+			out[f] = v
+		}
+	}
+	return out, nil
+}
+
+// toMap 把类型化记录转换为按 json tag 展开的 map
+func toMap(item interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
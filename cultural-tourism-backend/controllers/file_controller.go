@@ -0,0 +1,164 @@
+// File: controllers/file_controller.go
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/store/local"
+	"cultural-tourism-backend/tcb/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadSize 单个文件上限
+const maxUploadSize = 10 << 20
+
+// uploadDirs 允许上传的目录 (按资源划分)
+var uploadDirs = []string{"photos", "pois", "themes", "products"}
+
+// uploadPermissions 目录 -> 上传所需权限；未列出的目录 (photos) 登录即可上传
+var uploadPermissions = map[string]auth.Permission{
+	"pois":     auth.PermManageContent,
+	"themes":   auth.PermManageContent,
+	"products": auth.PermManageProducts,
+}
+
+// UploadFile 上传图片
+// @Summary      上传图片
+// @Description  上传到云存储，返回 fileID (写入 image_url / images / cover / image 字段) 及临时链接。
+// @Description  需要登录；pois / themes 目录需要 content:manage 权限，products 目录需要 product:manage 权限
+// @Tags         Files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file    true  "图片 (不超过 10MB)"
+// @Param        dir   formData  string  true  "目录: photos / pois / themes / products"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      401   {object}  map[string]interface{}  "未登录"
+// @Failure      403   {object}  map[string]interface{}  "无权上传到该目录"
+// @Router       /files [post]
+func UploadFile(c *gin.Context) {
+	if storage.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "未配置云存储"})
+		return
+	}
+
+	dir := c.PostForm("dir")
+	if !slices.Contains(uploadDirs, dir) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的目录: " + dir, "allowed": uploadDirs})
+		return
+	}
+	if perm, ok := uploadPermissions[dir]; ok {
+		if err := auth.Authorize(c.Request.Context(), perm); err != nil {
+			respondError(c, err, "上传失败")
+			return
+		}
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少文件"})
+		return
+	}
+	if header.Size > maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "文件不能超过 10MB"})
+		return
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		respondError(c, err, "上传失败")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadSize))
+	if err != nil {
+		respondError(c, err, "上传失败")
+		return
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "仅支持图片文件"})
+		return
+	}
+
+	// 对象名随机生成，不使用客户端文件名 (仅保留扩展名)
+	objectPath := fmt.Sprintf("%s/%s/%s%s", dir, time.Now().Format("200601"), local.NewID(), strings.ToLower(path.Ext(header.Filename)))
+	fileID, err := storage.Default.Upload(c.Request.Context(), objectPath, data, contentType)
+	if err != nil {
+		respondError(c, err, "上传失败")
+		return
+	}
+
+	body := gin.H{"success": true, "file_id": fileID}
+	if urls, err := storage.Default.TempURLs(c.Request.Context(), []string{fileID}); err == nil {
+		body["url"] = urls[fileID]
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// ResolveFileURLs 响应中间件：GET 请求的 JSON 响应中 cloud:// fileID 替换为临时链接
+// 管理端编辑时需要拿到原始 fileID 回写，可带请求头 X-Raw-File-IDs: true 跳过替换。
+// 未配置云存储 (storage.Default 为 nil) 时不做处理。
+func ResolveFileURLs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if storage.Default == nil || c.Request.Method != http.MethodGet || c.GetHeader("X-Raw-File-IDs") == "true" {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.buf.Bytes()
+		if w.Status() == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") &&
+			bytes.Contains(body, []byte(storage.Scheme)) {
+			body = rewriteFileIDs(c, body)
+		}
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// rewriteFileIDs 替换失败时记录日志并输出原响应 (fileID 对前端只是链接失效，不影响其他数据)
+func rewriteFileIDs(c *gin.Context, body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // 保持数字原样
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	v, err := storage.Rewrite(c.Request.Context(), storage.Default, v)
+	if err != nil {
+		log.Printf("⚠️ %s %s 换取临时链接失败: %v", c.Request.Method, c.FullPath(), err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// bufferedWriter 缓存响应体，待改写后统一输出
+type bufferedWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.buf.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
@@ -0,0 +1,100 @@
+package controllers_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb/storage"
+)
+
+// setupStorage 使用临时目录作为云存储，测试结束后恢复
+func setupStorage(t *testing.T) *storage.Local {
+	t.Helper()
+	s, err := storage.NewLocal(t.TempDir(), "http://files.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage.Default = s
+	t.Cleanup(func() { storage.Default = nil })
+	return s
+}
+
+// upload 携带登录令牌上传文件 (token 为空时匿名上传)
+func upload(t *testing.T, h http.Handler, token, dir, filename string, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("dir", dir)
+	fw, _ := mw.CreateFormFile("file", filename)
+	_, _ = fw.Write(data)
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/files", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestUploadAndResolveFileURLs(t *testing.T) {
+	srv, r := setup(t)
+	setupStorage(t)
+
+	setupAuth(t)
+	alice := login(t, r, "o_alice")
+
+	// 匿名上传返回 401；资源目录需要对应的管理权限
+	expectStatus(t, upload(t, r, "", "photos", "a.png", pngHeader), http.StatusUnauthorized)
+	expectStatus(t, upload(t, r, alice, "pois", "a.png", pngHeader), http.StatusForbidden)
+	expectStatus(t, upload(t, r, alice, "products", "a.png", pngHeader), http.StatusForbidden)
+	grant(srv, "o_editor", auth.RoleContentModerator)
+	expectStatus(t, upload(t, r, login(t, r, "o_editor"), "themes", "a.png", pngHeader), http.StatusOK)
+
+	expectStatus(t, upload(t, r, alice, "secrets", "a.png", pngHeader), http.StatusBadRequest)
+	expectStatus(t, upload(t, r, alice, "photos", "a.png", []byte("not an image")), http.StatusBadRequest)
+
+	w := upload(t, r, alice, "photos", "a.PNG", pngHeader)
+	expectStatus(t, w, http.StatusOK)
+	uploaded := decode[struct {
+		FileID string `json:"file_id"`
+		URL    string `json:"url"`
+	}](t, w)
+	if !strings.HasPrefix(uploaded.FileID, "cloud://local/photos/") || !strings.HasSuffix(uploaded.FileID, ".png") {
+		t.Fatalf("file_id = %q", uploaded.FileID)
+	}
+
+	ids := srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: "t1", ImageURL: uploaded.FileID, Status: 1, OpenID: "o_alice"})
+
+	w = do(t, r, http.MethodGet, "/api/photos/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Photo](t, w).ImageURL; got != uploaded.URL || !strings.HasPrefix(got, "http://files.test/photos/") {
+		t.Errorf("image_url = %q, want temp URL %q", got, uploaded.URL)
+	}
+
+	// 管理端编辑需要原始 fileID
+	req := httptest.NewRequest(http.MethodGet, "/api/photos/"+ids[0], nil)
+	req.Header.Set("X-Raw-File-IDs", "true")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := decode[models.Photo](t, w).ImageURL; got != uploaded.FileID {
+		t.Errorf("raw image_url = %q, want %q", got, uploaded.FileID)
+	}
+
+	// 删除照片时清理文件
+	expectStatus(t, doAs(t, r, alice, http.MethodDelete, "/api/photos/"+ids[0], nil), http.StatusOK)
+	if results, _ := storage.Default.Delete(t.Context(), uploaded.FileID); results[0].OK {
+		t.Error("file still exists after photo was deleted")
+	}
+}
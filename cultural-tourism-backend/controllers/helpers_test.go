@@ -0,0 +1,87 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"cultural-tourism-backend/routes"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcbtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setup 启动假 CloudBase 网关并把全局数据存储指向它
+func setup(t *testing.T) (*tcbtest.Server, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	srv := tcbtest.NewServer()
+	prevClient, prevStore := tcb.Client, store.Default
+	tcb.Client = srv.Client()
+	store.Default = tcb.Client
+	t.Cleanup(func() {
+		tcb.Client, store.Default = prevClient, prevStore
+		srv.Close()
+	})
+
+	r := gin.New()
+	routes.RegisterRoutes(r)
+	return srv, r
+}
+
+// do 发起请求，body 非 nil 时按 JSON 编码
+func do(t *testing.T, r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return doAs(t, r, "", method, path, body)
+}
+
+// doAs 携带登录令牌发起请求 (token 为空时等同 do)
+func doAs(t *testing.T, r *gin.Engine, token, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decode 解析响应 JSON
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+// expectStatus 断言状态码
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, status, w.Body.String())
+	}
+}
+
+type createResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
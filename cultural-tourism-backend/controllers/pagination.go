@@ -0,0 +1,13 @@
+package controllers
+
+import "cultural-tourism-backend/config"
+
+// limitPage 规范分页参数：page 至少为 1，size 不超过 pagination.max_size (PAGE_MAX_SIZE)
+func limitPage(page, size *int) {
+	if *page < 1 {
+		*page = 1
+	}
+	if max := config.AppConfig.Pagination.MaxSize; *size > max {
+		*size = max
+	}
+}
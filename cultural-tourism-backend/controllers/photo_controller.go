@@ -2,18 +2,19 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
 
-
 // CreatePhoto 上传照片
 // @Summary      上传照片
-// @Description  用户上传旅拍照片 (上传后默认为待审核状态 status=0)
+// @Description  登录用户上传旅拍照片 (上传后默认为待审核状态 status=0)
 // @Tags         Photos
 // @Accept       json
 // @Produce      json
@@ -27,13 +28,13 @@ func CreatePhoto(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CreatePhoto(&photo)
+	id, err := services.CreatePhoto(c.Request.Context(), &photo)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "上传失败: " + err.Error()})
+		respondError(c, err, "上传失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetPhotoList 获取照片列表 (瀑布流)
@@ -41,10 +42,11 @@ func CreatePhoto(c *gin.Context) {
 // @Description  支持按主题ID筛选，默认只显示审核通过(status=1)的照片
 // @Tags         Photos
 // @Param        theme_id  query  string  false  "主题ID"
-// @Param        status    query  int     false  "状态 (1:通过, 0:待审)"
+// @Param        status    query  int     false  "状态 (1:通过, 0:待审)，非 1 需审核权限"
 // @Param        page      query  int     false  "页码"
 // @Param        size      query  int     false  "每页数量"
-// @Success      200       {object}  map[string]interface{}
+// @Param        fields    query  string  false  "字段投影，逗号分隔 (如 image_url,like_count)"
+// @Success      200       {object}  tcb.Page[models.Photo]
 // @Router       /photos [get]
 func GetPhotoList(c *gin.Context) {
 	var query models.PhotoQuery
@@ -52,21 +54,27 @@ func GetPhotoList(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	limitPage(&query.Page, &query.Size)
+	fields, ok := bindFields(c, "photos")
+	if !ok {
+		return
+	}
+	query.Fields = fields
 
-	result, err := services.ListPhotos(query)
+	result, err := services.ListPhotos(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询失败: " + err.Error()})
+		respondError(c, err, "查询失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, query.Fields)
 }
 
 // GetPhotoDetail 获取照片详情
 // @Summary      获取照片详情
 // @Tags         Photos
 // @Param        id   path      string  true  "照片ID"
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  models.Photo
 // @Router       /photos/{id} [get]
 func GetPhotoDetail(c *gin.Context) {
 	id := c.Param("id")
@@ -75,23 +83,25 @@ func GetPhotoDetail(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetPhotoDetail(id)
+	result, err := services.GetPhotoDetail(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "照片不存在"})
+		respondDetailError(c, err, "照片不存在")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
-// UpdatePhoto 更新照片状态 (审核/点赞)
-// @Summary      更新照片 (审核/点赞)
-// @Description  用于管理员审核 (修改status) 或 用户点赞 (修改like_count)
+// UpdatePhoto 更新照片状态 (审核)
+// @Summary      更新照片 (审核)
+// @Description  管理员审核 (修改status)；发布者可修改自己的照片。点赞请使用 /like 接口
 // @Tags         Photos
 // @Accept       json
 // @Produce      json
 // @Param        id     path      string        true  "照片ID"
-// @Param        photo  body      models.Photo  true  "更新内容 (仅status/like_count)"
+// @Param        photo  body      models.Photo  true  "更新内容 (仅status)"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200    {object}  map[string]interface{}
 // @Router       /photos/{id} [put]
 func UpdatePhoto(c *gin.Context) {
@@ -102,27 +112,61 @@ func UpdatePhoto(c *gin.Context) {
 		return
 	}
 
-	err := services.UpdatePhoto(id, photo)
+	expected, ok := bindPrecondition(c, services.GetPhotoDetail)
+	if !ok {
+		return
+	}
+
+	err := services.UpdatePhoto(c.Request.Context(), id, photo, expected)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+		respondError(c, err, "更新失败")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
+// LikePhoto 点赞照片
+// @Summary      点赞照片
+// @Description  需要登录；每个用户对同一照片只能点赞一次。点赞数原子加一，返回最新点赞数
+// @Tags         Photos
+// @Param        id   path      string  true  "照片ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{}  "未登录"
+// @Failure      409  {object}  map[string]interface{}  "已点赞"
+// @Router       /photos/{id}/like [post]
+func LikePhoto(c *gin.Context) {
+	id := c.Param("id")
+	count, err := services.LikePhoto(c.Request.Context(), id)
+	if err != nil {
+		respondLikeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id, "like_count": count})
+}
+
+// respondLikeError 点赞接口的错误输出 (重复点赞返回 409，其余统一映射)
+func respondLikeError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrAlreadyLiked) {
+		c.JSON(http.StatusConflict, gin.H{"error": "已经点过赞了", "code": "ALREADY_LIKED"})
+		return
+	}
+	respondError(c, err, "点赞失败")
+}
+
 // DeletePhoto 删除照片
 // @Summary      删除照片
-// @Description  管理员可删除违规照片，用户可删除自己的照片(依赖TCB权限)
+// @Description  发布者可删除自己的照片，管理员可删除任意照片
 // @Tags         Photos
 // @Param        id   path      string  true  "照片ID"
 // @Success      200  {object}  map[string]interface{}
 // @Router       /photos/{id} [delete]
 func DeletePhoto(c *gin.Context) {
 	id := c.Param("id")
-	err := services.DeletePhoto(id)
+	err := services.DeletePhoto(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		respondError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
@@ -0,0 +1,156 @@
+package controllers_test
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreatePhotoIsPendingReview(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+
+	w := do(t, r, http.MethodPost, "/api/photos", map[string]interface{}{"theme_id": "t1", "image_url": "cloud://a.jpg"})
+	expectStatus(t, w, http.StatusUnauthorized)
+
+	w = doAs(t, r, login(t, r, "o_alice"), http.MethodPost, "/api/photos", map[string]interface{}{
+		"theme_id": "t1", "image_url": "cloud://a.jpg", "status": 1, "like_count": 999,
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	rec := srv.Records(collection.Photos.Name())[0]
+	if rec["status"] != float64(0) || rec["like_count"] != float64(0) || rec["_openid"] != "o_alice" {
+		t.Errorf("record = %v, want status=0 like_count=0 _openid=o_alice", rec)
+	}
+
+	// 默认瀑布流只展示已过审照片
+	w = do(t, r, http.MethodGet, "/api/photos?theme_id=t1", nil)
+	expectStatus(t, w, http.StatusOK)
+	if page := decode[tcb.Page[models.Photo]](t, w); len(page.Items) != 0 {
+		t.Errorf("pending photo listed: %+v", page.Items)
+	}
+}
+
+func TestGetPhotoListByTheme(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.Photos.Name(),
+		models.Photo{ThemeID: "t1", ImageURL: "1.jpg", Status: 1},
+		models.Photo{ThemeID: "t2", ImageURL: "2.jpg", Status: 1},
+		models.Photo{ThemeID: "t1", ImageURL: "3.jpg", Status: 0},
+	)
+
+	w := do(t, r, http.MethodGet, "/api/photos?theme_id=t1", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Photo]](t, w)
+	if len(page.Items) != 1 || page.Items[0].ImageURL != "1.jpg" {
+		t.Fatalf("items = %+v, want only 1.jpg", page.Items)
+	}
+}
+
+func TestPhotoDetailUpdateDelete(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleContentModerator)
+	ids := srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: "t1", ImageURL: "1.jpg", OpenID: "o_alice"})
+
+	w := do(t, r, http.MethodGet, "/api/photos/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = doAs(t, r, admin, http.MethodPut, "/api/photos/"+ids[0], map[string]interface{}{"status": 1})
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Photos.Name())[0]; rec["status"] != float64(1) {
+		t.Errorf("status after review = %v", rec["status"])
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/photos/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = do(t, r, http.MethodGet, "/api/photos/"+ids[0], nil)
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestPhotoOwnership(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	alice, bob := login(t, r, "o_alice"), login(t, r, "o_bob")
+	ids := srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: "t1", ImageURL: "1.jpg", OpenID: "o_alice"})
+
+	// 他人不能修改或删除
+	w := doAs(t, r, bob, http.MethodDelete, "/api/photos/"+ids[0], nil)
+	expectStatus(t, w, http.StatusForbidden)
+	if got := decode[errorResponse](t, w); got.Code != "FORBIDDEN" {
+		t.Errorf("code = %q, want FORBIDDEN", got.Code)
+	}
+	expectStatus(t, doAs(t, r, bob, http.MethodPut, "/api/photos/"+ids[0], map[string]interface{}{"theme_id": "t2"}), http.StatusForbidden)
+
+	// 发布者不能自行审核通过
+	expectStatus(t, doAs(t, r, alice, http.MethodPut, "/api/photos/"+ids[0], map[string]interface{}{"status": 1}), http.StatusForbidden)
+
+	expectStatus(t, doAs(t, r, alice, http.MethodDelete, "/api/photos/"+ids[0], nil), http.StatusOK)
+	expectStatus(t, do(t, r, http.MethodGet, "/api/photos/"+ids[0], nil), http.StatusNotFound)
+}
+
+func TestLikePhoto(t *testing.T) {
+	srv, r := setup(t)
+	setupAuth(t)
+	ids := srv.Seed(collection.Photos.Name(), models.Photo{ThemeID: "t1", ImageURL: "1.jpg", Status: 1, LikeCount: 2, OpenID: "o_alice"})
+	alice, bob := login(t, r, "o_alice"), login(t, r, "o_bob")
+
+	expectStatus(t, do(t, r, http.MethodPost, "/api/photos/"+ids[0]+"/like", nil), http.StatusUnauthorized)
+
+	w := doAs(t, r, bob, http.MethodPost, "/api/photos/"+ids[0]+"/like", nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]interface{}](t, w); got["like_count"] != float64(3) {
+		t.Errorf("like response = %v, want like_count=3", got)
+	}
+
+	// 同一用户重复点赞不再计数 (假服务器对唯一字段冲突返回 400 而非 409，去重不依赖错误码)
+	w = doAs(t, r, bob, http.MethodPost, "/api/photos/"+ids[0]+"/like", nil)
+	expectStatus(t, w, http.StatusConflict)
+	if got := decode[errorResponse](t, w); got.Code != "ALREADY_LIKED" {
+		t.Errorf("code = %q, want ALREADY_LIKED", got.Code)
+	}
+
+	// 更新接口不再接受客户端提交的点赞数
+	w = doAs(t, r, alice, http.MethodPut, "/api/photos/"+ids[0], map[string]interface{}{"like_count": 999})
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Photos.Name())[0]; rec["like_count"] != float64(3) {
+		t.Errorf("like_count after PUT = %v, want 3", rec["like_count"])
+	}
+
+	// 照片不存在时不留下点赞记录
+	w = doAs(t, r, bob, http.MethodPost, "/api/photos/missing/like", nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if likes := srv.Records(collection.Likes.Name()); len(likes) != 1 || likes[0]["target_id"] != ids[0] || likes[0]["_openid"] != "o_bob" {
+		t.Errorf("likes = %v", likes)
+	}
+
+	// 点赞照片与点赞评论互不影响
+	comments := srv.Seed(collection.Comments.Name(), models.Comment{POIID: "p1", Content: "好看", Status: 1})
+	expectStatus(t, doAs(t, r, bob, http.MethodPost, "/api/comments/"+comments[0]+"/like", nil), http.StatusOK)
+	expectStatus(t, doAs(t, r, bob, http.MethodPost, "/api/comments/"+comments[0]+"/like", nil), http.StatusConflict)
+
+	// 并发的重复点赞只计一次
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = doAs(t, r, alice, http.MethodPost, "/api/photos/"+ids[0]+"/like", nil).Code
+		}()
+	}
+	wg.Wait()
+	slices.Sort(codes)
+	if !slices.Equal(codes, []int{http.StatusOK, http.StatusConflict, http.StatusConflict, http.StatusConflict, http.StatusConflict}) {
+		t.Errorf("concurrent likes = %v, want one 200", codes)
+	}
+	if rec := srv.Records(collection.Photos.Name())[0]; rec["like_count"] != float64(4) {
+		t.Errorf("like_count after concurrent likes = %v, want 4", rec["like_count"])
+	}
+}
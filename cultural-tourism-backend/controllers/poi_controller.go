@@ -2,33 +2,15 @@
 package controllers
 
 import (
-	"math"
 	"net/http"
-	"time"
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
-	"cultural-tourism-backend/tcb"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
 
-const CollectionPOI = "pois"
-
-// ... (calculateDistance 函数保持不变，此处省略以节省篇幅，请保留之前的实现) ...
-func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
-	const R = 6371000 // 地球半径 (米)
-	dLat := (lat2 - lat1) * (math.Pi / 180.0)
-	dLon := (lon2 - lon1) * (math.Pi / 180.0)
-
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-		math.Cos(lat1*(math.Pi/180.0))*math.Cos(lat2*(math.Pi/180.0))*
-			math.Sin(dLon/2)*math.Sin(dLon/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	return R * c
-}
-
 // CreatePOI 创建点位
 // @Summary      创建点位
 // @Description  创建新的 POI 点位 (景点/饭店/酒店/旅拍机)
@@ -37,7 +19,7 @@ func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 // @Produce      json
 // @Param        poi  body      models.POI  true  "POI信息"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /pois [post]
+// @Router       /admin/pois [post]
 func CreatePOI(c *gin.Context) {
 	var poi models.POI
 	if err := c.ShouldBindJSON(&poi); err != nil {
@@ -45,22 +27,13 @@ func CreatePOI(c *gin.Context) {
 		return
 	}
 
-	// [Security] 强制覆盖系统字段
-	poi.ID = ""
-	poi.CreatedAt = time.Now().Format(time.RFC3339)
-	poi.UpdatedAt = time.Now().Format(time.RFC3339)
-	poi.Distance = 0
-	if poi.Status == 0 {
-		poi.Status = 1
-	}
-
-	result, err := tcb.Client.CreateData(CollectionPOI, poi)
+	id, err := services.CreatePOI(c.Request.Context(), &poi)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create POI: " + err.Error()})
+		respondError(c, err, "Failed to create POI")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetPOIList 获取点位列表
@@ -73,59 +46,63 @@ func CreatePOI(c *gin.Context) {
 // @Param        lng        query  float64  false  "用户经度 (用于计算距离)"
 // @Param        page       query  int      false  "页码"
 // @Param        size       query  int      false  "每页数量"
-// @Success      200        {object} map[string]interface{}
+// @Param        fields     query  string   false  "字段投影，逗号分隔 (如 name,latitude,longitude)"
+// @Success      200        {object} tcb.Page[models.POI]
 // @Router       /pois [get]
 func GetPOIList(c *gin.Context) {
-	var query models.POIQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
+	var q models.POIQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// 1. 构造 TCB 标准 Filter
-	where := make(map[string]interface{})
-	where["status"] = map[string]interface{}{"$eq": 1}
-
-	if query.RegionID != "" {
-		where["region_id"] = map[string]interface{}{"$eq": query.RegionID}
-	}
-	if query.Type != "" {
-		where["type"] = map[string]interface{}{"$eq": query.Type}
+	limitPage(&q.Page, &q.Size)
+	fields, ok := bindFields(c, "pois")
+	if !ok {
+		return
 	}
+	q.Fields = fields
 
-	filter := map[string]interface{}{
-		"where": where,
+	result, err := services.ListPOIs(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err, "Failed to fetch POIs")
+		return
 	}
 
-	// 2. 调用 SDK
-	result, err := tcb.Client.ListData(CollectionPOI, filter, query.Page, query.Size)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch POIs: " + err.Error()})
+	respondPage(c, result, fields)
+}
+
+// GetManagedPOIList 管理端点位列表
+// @Summary      管理端点位列表
+// @Description  包含未上线点位 (需要 content:manage 权限)，受区域限制的管理员只能看到负责区域内的点位
+// @Tags         POI
+// @Param        region_id  query  string  false  "区域ID"
+// @Param        type       query  string  false  "点位类型 (scenic/food/hotel/booth)"
+// @Param        page       query  int     false  "页码"
+// @Param        size       query  int     false  "每页数量"
+// @Param        fields     query  string  false  "字段投影，逗号分隔"
+// @Success      200        {object}  tcb.Page[models.POI]
+// @Failure      403        {object}  map[string]interface{}  "无权限或区域不在负责范围内"
+// @Router       /admin/pois [get]
+func GetManagedPOIList(c *gin.Context) {
+	var q models.POIQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limitPage(&q.Page, &q.Size)
+	fields, ok := bindFields(c, "pois")
+	if !ok {
 		return
 	}
+	q.Fields = fields
 
-	// 3. [LBS Feature] 距离计算
-	if query.UserLat != 0 && query.UserLng != 0 {
-		if dataMap, ok := result["data"].(map[string]interface{}); ok {
-			if records, ok := dataMap["records"].([]interface{}); ok {
-				for i, r := range records {
-					if rec, ok := r.(map[string]interface{}); ok {
-						lat, _ := rec["latitude"].(float64)
-						lng, _ := rec["longitude"].(float64)
-
-						if lat != 0 && lng != 0 {
-							dist := calculateDistance(query.UserLat, query.UserLng, lat, lng)
-							rec["_distance"] = math.Round(dist)
-							records[i] = rec
-						}
-					}
-				}
-				dataMap["records"] = records
-			}
-		}
+	result, err := services.ListManagedPOIs(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err, "查询失败")
+		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, fields)
 }
 
 // GetPOI 获取单个点位详情
@@ -141,12 +118,13 @@ func GetPOI(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetPOIDetail(id)
+	result, err := services.GetPOIDetail(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "POI not found"})
+		respondDetailError(c, err, "POI not found")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
@@ -158,8 +136,9 @@ func GetPOI(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      string      true  "POI ID"
 // @Param        poi  body      models.POI  true  "更新信息"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /pois/{id} [put]
+// @Router       /admin/pois/{id} [put]
 func UpdatePOI(c *gin.Context) {
 	id := c.Param("id")
 	var poi models.POI
@@ -168,48 +147,14 @@ func UpdatePOI(c *gin.Context) {
 		return
 	}
 
-	// [Security Fix] Partial Update Map
-	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
-	}
-	// ... (白名单字段赋值逻辑同前文，此处省略以节省篇幅，请保留) ...
-	if poi.Name != "" {
-		updateData["name"] = poi.Name
-	}
-	if poi.Type != "" {
-		updateData["type"] = poi.Type
-	}
-	if poi.RegionID != "" {
-		updateData["region_id"] = poi.RegionID
-	}
-	if poi.Latitude != 0 {
-		updateData["latitude"] = poi.Latitude
-	}
-	if poi.Longitude != 0 {
-		updateData["longitude"] = poi.Longitude
-	}
-	if len(poi.Images) > 0 {
-		updateData["images"] = poi.Images
-	}
-	if poi.Desc != "" {
-		updateData["desc"] = poi.Desc
-	}
-	if poi.Address != "" {
-		updateData["address"] = poi.Address
-	}
-	if poi.Phone != "" {
-		updateData["phone"] = poi.Phone
-	}
-	if poi.OpenTime != "" {
-		updateData["open_time"] = poi.OpenTime
-	}
-	if poi.Status != 0 {
-		updateData["status"] = poi.Status
+	expected, ok := bindPrecondition(c, services.GetPOIDetail)
+	if !ok {
+		return
 	}
 
-	err := tcb.Client.UpdateData(CollectionPOI, id, updateData)
+	err := services.UpdatePOI(c.Request.Context(), id, &poi, expected)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update POI: " + err.Error()})
+		respondError(c, err, "Failed to update POI")
 		return
 	}
 
@@ -221,12 +166,12 @@ func UpdatePOI(c *gin.Context) {
 // @Tags         POI
 // @Param        id   path      string  true  "POI ID"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /pois/{id} [delete]
+// @Router       /admin/pois/{id} [delete]
 func DeletePOI(c *gin.Context) {
 	id := c.Param("id")
-	err := tcb.Client.DeleteData(CollectionPOI, id)
+	err := services.DeletePOI(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete POI"})
+		respondError(c, err, "Failed to delete POI")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreatePOIAndListByRegion(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	srv.Seed(collection.POIs.Name(), models.POI{Name: "断桥", Type: models.POITypeScenic, RegionID: "r2", Status: 1})
+
+	w := doAs(t, r, admin, http.MethodPost, "/api/admin/pois", map[string]interface{}{
+		"name": "雷峰塔", "type": "scenic", "region_id": "r1", "latitude": 30.231, "longitude": 120.148,
+	})
+	expectStatus(t, w, http.StatusOK)
+	if decode[createResponse](t, w).ID == "" {
+		t.Fatal("expected id in create response")
+	}
+
+	w = do(t, r, http.MethodGet, "/api/pois?region_id=r1", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.POI]](t, w)
+	if len(page.Items) != 1 || page.Items[0].Name != "雷峰塔" || page.Items[0].Status != 1 {
+		t.Fatalf("items = %+v, want only 雷峰塔 (status=1)", page.Items)
+	}
+}
+
+func TestGetPOIListComputesDistance(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.POIs.Name(), models.POI{Name: "雷峰塔", Latitude: 30.231, Longitude: 120.148, Status: 1})
+
+	w := do(t, r, http.MethodGet, "/api/pois?lat=30.241&lng=120.148", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.POI]](t, w)
+	if len(page.Items) != 1 {
+		t.Fatalf("items = %+v", page.Items)
+	}
+	// 纬度相差 0.01 度约 1112 米
+	if d := page.Items[0].Distance; d < 1100 || d > 1125 {
+		t.Errorf("distance = %v, want ~1112", d)
+	}
+}
+
+func TestGetPOIListFieldProjection(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.POIs.Name(), models.POI{
+		Name: "雷峰塔", Latitude: 30.231, Longitude: 120.148, Desc: "很长的简介", Images: []string{"a.jpg"}, Status: 1,
+	})
+
+	w := do(t, r, http.MethodGet, "/api/pois?fields=name,_distance&lat=30.241&lng=120.148", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[map[string]interface{}]](t, w)
+	if len(page.Items) != 1 {
+		t.Fatalf("items = %+v", page.Items)
+	}
+	item := page.Items[0]
+	if item["name"] != "雷峰塔" || item["_id"] == nil || item["_distance"] == nil {
+		t.Errorf("item = %v, want _id, name and _distance", item)
+	}
+	for _, hidden := range []string{"desc", "images", "latitude"} {
+		if _, ok := item[hidden]; ok {
+			t.Errorf("item contains unrequested field %q: %v", hidden, item)
+		}
+	}
+
+	w = do(t, r, http.MethodGet, "/api/pois?fields=name,_openid", nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestGetPOIDetail(t *testing.T) {
+	srv, r := setup(t)
+	ids := srv.Seed(collection.POIs.Name(), models.POI{Name: "灵隐寺", Type: models.POITypeScenic, Images: []string{"a.jpg"}})
+
+	w := do(t, r, http.MethodGet, "/api/pois/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.POI](t, w); got.Name != "灵隐寺" || len(got.Images) != 1 {
+		t.Errorf("poi = %+v", got)
+	}
+
+	w = do(t, r, http.MethodGet, "/api/pois/missing", nil)
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestUpdateAndDeletePOI(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	ids := srv.Seed(collection.POIs.Name(), models.POI{Name: "旧名", Phone: "1", Status: 1})
+
+	w := doAs(t, r, admin, http.MethodPut, "/api/admin/pois/"+ids[0], map[string]interface{}{"name": "新名", "_id": "hacked"})
+	expectStatus(t, w, http.StatusOK)
+	rec := srv.Records(collection.POIs.Name())[0]
+	if rec["name"] != "新名" || rec["phone"] != "1" || rec["_id"] != ids[0] {
+		t.Errorf("record after update = %v", rec)
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/admin/pois/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if n := len(srv.Records(collection.POIs.Name())); n != 0 {
+		t.Errorf("records after delete = %d, want 0", n)
+	}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"cultural-tourism-backend/tcb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 乐观并发控制：详情接口以 updated_at 作为 ETag 返回，
+// 更新接口通过 If-Match / If-Unmodified-Since 声明"基于哪个版本修改"，
+// 版本不一致时返回 412 与当前记录，由前端合并后重试。
+// updated_at 由 tcb.Timestamp 生成 (纳秒精度)，同一秒内的两次修改也是不同版本。
+
+// setETag 输出记录版本 (updated_at)
+func setETag(c *gin.Context, updatedAt string) {
+	if updatedAt != "" {
+		c.Header("ETag", `"`+updatedAt+`"`)
+	}
+}
+
+// bindPrecondition 解析条件更新请求头，返回期望的 updated_at ("" 表示无条件更新)
+// If-Match: 详情接口返回的 ETag；"*" 视为无条件；按强比较处理，弱 ETag (W/"...") 总是不匹配
+// If-Unmodified-Since: HTTP 日期；读取当前记录，未在该时间之后修改时以其 updated_at 作为条件
+// 前置条件已不满足或读取失败时已写入响应并返回 ok=false
+func bindPrecondition[T any](c *gin.Context, get func(ctx context.Context, id string) (*T, error)) (expected string, ok bool) {
+	if raw := strings.TrimSpace(c.GetHeader("If-Match")); raw != "" {
+		tag, _, _ := strings.Cut(raw, ",") // 只支持单个 ETag
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return "", true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if current, ok := currentRecord(c, get); ok {
+				respondPreconditionFailed(c, current)
+			}
+			return "", false
+		}
+		return strings.Trim(tag, `"`), true
+	}
+
+	raw := c.GetHeader("If-Unmodified-Since")
+	if raw == "" {
+		return "", true
+	}
+	since, err := http.ParseTime(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Unmodified-Since 格式错误"})
+		return "", false
+	}
+
+	current, ok := currentRecord(c, get)
+	if !ok {
+		return "", false
+	}
+
+	updatedAt, _ := current["updated_at"].(string)
+	modified, err := time.Parse(time.RFC3339, updatedAt)
+	if err != nil || modified.Truncate(time.Second).After(since) {
+		// 无法确认修改时间的记录同样视为已修改
+		respondPreconditionFailed(c, current)
+		return "", false
+	}
+	return updatedAt, true
+}
+
+// currentRecord 读取当前记录用于前置条件判断，失败时已写入响应
+func currentRecord[T any](c *gin.Context, get func(ctx context.Context, id string) (*T, error)) (map[string]interface{}, bool) {
+	record, err := get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return nil, false
+	}
+	current, err := toMap(record)
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return nil, false
+	}
+	return current, true
+}
+
+// respondPreconditionFailed 412：附带当前记录及其 ETag
+func respondPreconditionFailed(c *gin.Context, current map[string]interface{}) {
+	updatedAt, _ := current["updated_at"].(string)
+	setETag(c, updatedAt)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "资源已被修改，请刷新后重试",
+		"code":    "PRECONDITION_FAILED",
+		"current": current,
+	})
+}
+
+// preconditionFailed 从错误中取出当前记录
+func preconditionFailed(err error) (map[string]interface{}, bool) {
+	var pe *tcb.PreconditionError
+	if errors.As(err, &pe) {
+		return pe.Current, true
+	}
+	return nil, false
+}
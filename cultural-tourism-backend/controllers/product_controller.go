@@ -6,6 +6,7 @@ import (
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,7 +19,7 @@ import (
 // @Produce      json
 // @Param        product  body      models.Product  true  "商品信息"
 // @Success      200      {object}  map[string]interface{}
-// @Router       /products [post]
+// @Router       /admin/products [post]
 func CreateProduct(c *gin.Context) {
 	var product models.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
@@ -26,13 +27,13 @@ func CreateProduct(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CreateProduct(&product)
+	id, err := services.CreateProduct(c.Request.Context(), &product)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建失败: " + err.Error()})
+		respondError(c, err, "创建失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetProductList 获取商品列表
@@ -41,7 +42,8 @@ func CreateProduct(c *gin.Context) {
 // @Tags         Products
 // @Param        page  query  int  false  "页码"
 // @Param        size  query  int  false  "每页数量"
-// @Success      200   {object}  map[string]interface{}
+// @Param        fields  query  string  false  "字段投影，逗号分隔 (如 name,image,price)"
+// @Success      200   {object}  tcb.Page[models.Product]
 // @Router       /products [get]
 func GetProductList(c *gin.Context) {
 	var query models.ProductQuery
@@ -49,21 +51,27 @@ func GetProductList(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	limitPage(&query.Page, &query.Size)
+	fields, ok := bindFields(c, "products")
+	if !ok {
+		return
+	}
+	query.Fields = fields
 
-	result, err := services.ListProducts(query)
+	result, err := services.ListProducts(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询失败: " + err.Error()})
+		respondError(c, err, "查询失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, query.Fields)
 }
 
 // GetProductDetail 获取商品详情
 // @Summary      获取商品详情
 // @Tags         Products
 // @Param        id   path      string  true  "商品ID"
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  models.Product
 // @Router       /products/{id} [get]
 func GetProductDetail(c *gin.Context) {
 	id := c.Param("id")
@@ -72,12 +80,13 @@ func GetProductDetail(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetProductDetail(id)
+	result, err := services.GetProductDetail(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "商品不存在"})
+		respondDetailError(c, err, "商品不存在")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
@@ -89,8 +98,9 @@ func GetProductDetail(c *gin.Context) {
 // @Produce      json
 // @Param        id       path      string         true  "商品ID"
 // @Param        product  body      models.Product true  "更新内容"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200      {object}  map[string]interface{}
-// @Router       /products/{id} [put]
+// @Router       /admin/products/{id} [put]
 func UpdateProduct(c *gin.Context) {
 	id := c.Param("id")
 	var product models.Product
@@ -99,9 +109,14 @@ func UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	err := services.UpdateProduct(id, &product)
+	expected, ok := bindPrecondition(c, services.GetProductDetail)
+	if !ok {
+		return
+	}
+
+	err := services.UpdateProduct(c.Request.Context(), id, &product, expected)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+		respondError(c, err, "更新失败")
 		return
 	}
 
@@ -113,13 +128,13 @@ func UpdateProduct(c *gin.Context) {
 // @Tags         Products
 // @Param        id   path      string  true  "商品ID"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /products/{id} [delete]
+// @Router       /admin/products/{id} [delete]
 func DeleteProduct(c *gin.Context) {
 	id := c.Param("id")
-	err := services.DeleteProduct(id)
+	err := services.DeleteProduct(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		respondError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
-}
\ No newline at end of file
+}
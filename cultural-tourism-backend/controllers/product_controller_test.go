@@ -0,0 +1,62 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreateProduct(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleMerchantOperator)
+
+	w := doAs(t, r, admin, http.MethodPost, "/api/admin/products", map[string]interface{}{
+		"name": "龙井茶", "image": "tea.jpg", "price": -1, "jump_app_id": "wx123", "jump_path": "/pages/tea",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	rec := srv.Records(collection.Products.Name())[0]
+	if rec["name"] != "龙井茶" || rec["price"] != float64(0) || rec["status"] != float64(1) {
+		t.Errorf("record = %v, want negative price clamped to 0 and status defaulted to 1", rec)
+	}
+}
+
+func TestGetProductList(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.Products.Name(),
+		map[string]interface{}{"name": "上架", "status": 1},
+		map[string]interface{}{"name": "下架", "status": 0},
+	)
+
+	w := do(t, r, http.MethodGet, "/api/products", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Product]](t, w)
+	if len(page.Items) != 1 || page.Items[0].Name != "上架" {
+		t.Fatalf("items = %+v", page.Items)
+	}
+}
+
+func TestProductDetailUpdateDelete(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleMerchantOperator)
+	ids := srv.Seed(collection.Products.Name(), models.Product{Name: "龙井茶", Price: 10})
+
+	w := do(t, r, http.MethodGet, "/api/products/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = doAs(t, r, admin, http.MethodPut, "/api/admin/products/"+ids[0], map[string]interface{}{"price": 20})
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Products.Name())[0]; rec["price"] != float64(20) || rec["name"] != "龙井茶" {
+		t.Errorf("record after update = %v", rec)
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/admin/products/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+
+	w = do(t, r, http.MethodGet, "/api/products/"+ids[0], nil)
+	expectStatus(t, w, http.StatusNotFound)
+}
@@ -6,12 +6,11 @@ import (
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
 
-
-
 // CreateRegion 创建新区域
 // @Summary      创建区域
 // @Description  创建一个新的景区区域 (Name必填)
@@ -20,7 +19,7 @@ import (
 // @Produce      json
 // @Param        region  body      models.Region  true  "区域信息"
 // @Success      200     {object}  map[string]interface{}
-// @Router       /regions [post]
+// @Router       /admin/regions [post]
 func CreateRegion(c *gin.Context) {
 	var region models.Region
 	if err := c.ShouldBindJSON(&region); err != nil {
@@ -28,13 +27,13 @@ func CreateRegion(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CreateRegion(region)
+	id, err := services.CreateRegion(c.Request.Context(), region)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建失败: " + err.Error()})
+		respondError(c, err, "创建失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetRegions 获取区域列表
@@ -46,7 +45,8 @@ func CreateRegion(c *gin.Context) {
 // @Param        page    query     int     false  "页码 (默认1)"
 // @Param        size    query     int     false  "每页数量 (默认100)"
 // @Param        status  query     int     false  "状态 (1:启用, 0:禁用)"
-// @Success      200     {object}  map[string]interface{}
+// @Param        fields  query     string  false  "字段投影，逗号分隔 (如 name,sort)"
+// @Success      200     {object}  tcb.Page[models.Region]
 // @Router       /regions [get]
 func GetRegions(c *gin.Context) {
 	// 获取分页参数
@@ -60,14 +60,19 @@ func GetRegions(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	limitPage(&query.Page, &query.Size)
+	fields, ok := bindFields(c, "regions")
+	if !ok {
+		return
+	}
 
-	result, err := services.ListRegions(query.Page, query.Size, query.Status)
+	result, err := services.ListRegions(c.Request.Context(), query.Page, query.Size, query.Status, fields...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询失败: " + err.Error()})
+		respondError(c, err, "查询失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, fields)
 }
 
 // GetRegionDetail 获取单条区域详情
@@ -77,7 +82,7 @@ func GetRegions(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "区域ID"
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  models.Region
 // @Router       /regions/{id} [get]
 func GetRegionDetail(c *gin.Context) {
 	id := c.Param("id")
@@ -86,12 +91,13 @@ func GetRegionDetail(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetRegionDetail(id)
+	result, err := services.GetRegionDetail(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "区域不存在"})
+		respondDetailError(c, err, "区域不存在")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
@@ -103,8 +109,9 @@ func GetRegionDetail(c *gin.Context) {
 // @Produce      json
 // @Param        id    path      string         true  "区域ID"
 // @Param        data  body      models.Region  true  "更新内容 (仅需传修改字段)"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200   {object}  map[string]interface{}
-// @Router       /regions/{id} [put]
+// @Router       /admin/regions/{id} [put]
 func UpdateRegion(c *gin.Context) {
 	id := c.Param("id")
 	var region models.Region
@@ -113,9 +120,14 @@ func UpdateRegion(c *gin.Context) {
 		return
 	}
 
-	err := services.UpdateRegion(id, region)
+	expected, ok := bindPrecondition(c, services.GetRegionDetail)
+	if !ok {
+		return
+	}
+
+	err := services.UpdateRegion(c.Request.Context(), id, region, expected)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+		respondError(c, err, "更新失败")
 		return
 	}
 
@@ -130,12 +142,12 @@ func UpdateRegion(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      string  true  "区域ID"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /regions/{id} [delete]
+// @Router       /admin/regions/{id} [delete]
 func DeleteRegion(c *gin.Context) {
 	id := c.Param("id")
-	err := services.DeleteRegion(id)
+	err := services.DeleteRegion(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		respondError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreateRegionAndList(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+
+	w := doAs(t, r, admin, http.MethodPost, "/api/admin/regions", map[string]interface{}{"name": "西湖"})
+	expectStatus(t, w, http.StatusOK)
+	created := decode[createResponse](t, w)
+	if created.ID == "" {
+		t.Fatal("expected id in create response")
+	}
+
+	w = do(t, r, http.MethodGet, "/api/regions", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Region]](t, w)
+	if page.Total != 1 || len(page.Items) != 1 {
+		t.Fatalf("page = %+v, want 1 item", page)
+	}
+	got := page.Items[0]
+	if got.Name != "西湖" || got.Status != 1 || got.Sort != 100 {
+		t.Errorf("region = %+v, want defaults status=1 sort=100", got)
+	}
+}
+
+func TestGetRegionsFiltersByStatus(t *testing.T) {
+	srv, r := setup(t)
+	srv.Seed(collection.Regions.Name(),
+		models.Region{Name: "启用", Status: 1},
+		models.Region{Name: "禁用", Status: 0},
+	)
+
+	w := do(t, r, http.MethodGet, "/api/regions?status=0", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Region]](t, w)
+	if len(page.Items) != 1 || page.Items[0].Name != "禁用" {
+		t.Fatalf("items = %+v, want only disabled region", page.Items)
+	}
+}
+
+func TestGetRegionDetail(t *testing.T) {
+	srv, r := setup(t)
+	ids := srv.Seed(collection.Regions.Name(), models.Region{Name: "灵隐", Status: 1})
+
+	w := do(t, r, http.MethodGet, "/api/regions/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Region](t, w); got.ID != ids[0] || got.Name != "灵隐" {
+		t.Errorf("region = %+v", got)
+	}
+
+	w = do(t, r, http.MethodGet, "/api/regions/missing", nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if got := decode[errorResponse](t, w); got.Error != "区域不存在" {
+		t.Errorf("error = %q", got.Error)
+	}
+}
+
+func TestUpdateAndDeleteRegion(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	ids := srv.Seed(collection.Regions.Name(), models.Region{Name: "旧名", Status: 1, Sort: 1})
+
+	w := doAs(t, r, admin, http.MethodPut, "/api/admin/regions/"+ids[0], map[string]interface{}{"name": "新名", "sort": 5})
+	expectStatus(t, w, http.StatusOK)
+	rec := srv.Records(collection.Regions.Name())[0]
+	if rec["name"] != "新名" || rec["sort"] != float64(5) {
+		t.Errorf("record after update = %v", rec)
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/admin/regions/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if n := len(srv.Records(collection.Regions.Name())); n != 0 {
+		t.Errorf("records after delete = %d, want 0", n)
+	}
+}
+
+func TestGetRegionsUpstreamFailureIsMasked(t *testing.T) {
+	srv, r := setup(t)
+	srv.FailNext(collection.Regions.Name(), http.StatusInternalServerError, 3)
+
+	w := do(t, r, http.MethodGet, "/api/regions", nil)
+	expectStatus(t, w, http.StatusBadGateway)
+	if body := w.Body.String(); strings.Contains(body, "INJECTED") {
+		t.Errorf("upstream details leaked: %s", body)
+	}
+}
@@ -3,17 +3,14 @@ package controllers
 
 import (
 	"net/http"
-	"time"
 
 	"cultural-tourism-backend/models"
 	"cultural-tourism-backend/services"
-	"cultural-tourism-backend/tcb"
+	_ "cultural-tourism-backend/tcb" // swag 注释中的 tcb.Page
 
 	"github.com/gin-gonic/gin"
 )
 
-const CollectionTheme = "theme" // 对应 model-json 中的 name
-
 // CreateTheme 创建旅拍主题
 // @Summary      创建旅拍主题
 // @Description  创建新的旅拍活动主题 (仅管理员)
@@ -22,7 +19,7 @@ const CollectionTheme = "theme" // 对应 model-json 中的 name
 // @Produce      json
 // @Param        theme  body      models.Theme  true  "主题信息"
 // @Success      200    {object}  map[string]interface{}
-// @Router       /themes [post]
+// @Router       /admin/themes [post]
 func CreateTheme(c *gin.Context) {
 	var theme models.Theme
 	if err := c.ShouldBindJSON(&theme); err != nil {
@@ -30,24 +27,13 @@ func CreateTheme(c *gin.Context) {
 		return
 	}
 
-	// 补全默认值
-	theme.ID = ""
-	theme.CreatedAt = time.Now().Format(time.RFC3339)
-	theme.UpdatedAt = time.Now().Format(time.RFC3339)
-	if theme.Sort == 0 {
-		theme.Sort = 100
-	}
-	if theme.Status == 0 {
-		theme.Status = 1 // 默认上架
-	}
-
-	result, err := tcb.Client.CreateData(CollectionTheme, theme)
+	id, err := services.CreateTheme(c.Request.Context(), &theme)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建失败: " + err.Error()})
+		respondError(c, err, "创建失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
 }
 
 // GetThemeList 获取主题列表
@@ -55,47 +41,73 @@ func CreateTheme(c *gin.Context) {
 // @Description  支持按区域筛选。实现PRD“区域优先推荐”：前端应先传region_id查询，若为空则不传region_id查全局。
 // @Tags         Themes
 // @Param        region_id  query  string  false  "区域ID"
-// @Param        status     query  int     false  "状态 (1:启用)"
+// @Param        status     query  int     false  "状态 (1:启用)，非 1 需内容管理权限"
 // @Param        page       query  int     false  "页码"
 // @Param        size       query  int     false  "每页数量"
-// @Success      200        {object}  map[string]interface{}
+// @Param        fields     query  string  false  "字段投影，逗号分隔 (如 name,cover)"
+// @Success      200        {object}  tcb.Page[models.Theme]
 // @Router       /themes [get]
 func GetThemeList(c *gin.Context) {
-	var query models.ThemeQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
+	var q models.ThemeQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// 1. 构造筛选条件
-	where := map[string]interface{}{
-		"status": map[string]interface{}{"$eq": query.Status},
+	limitPage(&q.Page, &q.Size)
+	fields, ok := bindFields(c, "themes")
+	if !ok {
+		return
 	}
+	q.Fields = fields
 
-	if query.RegionID != "" {
-		where["region_id"] = map[string]interface{}{"$eq": query.RegionID}
+	result, err := services.ListThemes(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err, "查询失败")
+		return
 	}
 
-	filter := map[string]interface{}{
-		"where": where,
-		// TODO: 待 SDK 支持 orderBy 后，此处应增加 "orderBy": [{"field": "sort", "direction": "desc"}]
+	respondPage(c, result, fields)
+}
+
+// GetManagedThemeList 管理端主题列表
+// @Summary      管理端主题列表
+// @Description  按状态筛选主题 (需要 content:manage 权限)，受区域限制的管理员只能看到负责区域内的主题
+// @Tags         Themes
+// @Param        region_id  query  string  false  "区域ID"
+// @Param        status     query  int     false  "状态 (1:启用, 0:停用)"
+// @Param        page       query  int     false  "页码"
+// @Param        size       query  int     false  "每页数量"
+// @Param        fields     query  string  false  "字段投影，逗号分隔"
+// @Success      200        {object}  tcb.Page[models.Theme]
+// @Failure      403        {object}  map[string]interface{}  "无权限或区域不在负责范围内"
+// @Router       /admin/themes [get]
+func GetManagedThemeList(c *gin.Context) {
+	var q models.ThemeQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limitPage(&q.Page, &q.Size)
+	fields, ok := bindFields(c, "themes")
+	if !ok {
+		return
 	}
+	q.Fields = fields
 
-	// 2. 调用 SDK
-	result, err := tcb.Client.ListData(CollectionTheme, filter, query.Page, query.Size)
+	result, err := services.ListManagedThemes(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询失败: " + err.Error()})
+		respondError(c, err, "查询失败")
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondPage(c, result, fields)
 }
 
 // GetThemeDetail 获取主题详情
 // @Summary      获取主题详情
 // @Tags         Themes
 // @Param        id   path      string  true  "主题ID"
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  models.Theme
 // @Router       /themes/{id} [get]
 func GetThemeDetail(c *gin.Context) {
 	id := c.Param("id")
@@ -104,12 +116,13 @@ func GetThemeDetail(c *gin.Context) {
 		return
 	}
 
-	result, err := services.GetThemeDetail(id)
+	result, err := services.GetThemeDetail(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "主题不存在"})
+		respondDetailError(c, err, "主题不存在")
 		return
 	}
 
+	setETag(c, result.UpdatedAt)
 	c.JSON(http.StatusOK, result)
 }
 
@@ -121,8 +134,9 @@ func GetThemeDetail(c *gin.Context) {
 // @Produce      json
 // @Param        id     path      string        true  "主题ID"
 // @Param        theme  body      models.Theme  true  "更新内容"
+// @Param        If-Match  header  string  false  "详情接口返回的 ETag，记录已被修改时返回 412"
 // @Success      200    {object}  map[string]interface{}
-// @Router       /themes/{id} [put]
+// @Router       /admin/themes/{id} [put]
 func UpdateTheme(c *gin.Context) {
 	id := c.Param("id")
 	var theme models.Theme
@@ -131,33 +145,14 @@ func UpdateTheme(c *gin.Context) {
 		return
 	}
 
-	// [Security] Partial Update Map 构造
-	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
-	}
-
-	if theme.Name != "" {
-		updateData["name"] = theme.Name
-	}
-	if theme.Cover != "" {
-		updateData["cover"] = theme.Cover
-	}
-	if theme.Desc != "" {
-		updateData["desc"] = theme.Desc
-	}
-	if theme.RegionID != "" {
-		updateData["region_id"] = theme.RegionID
-	}
-	if theme.Sort > 0 {
-		updateData["sort"] = theme.Sort
-	}
-	if theme.Status != 0 {
-		updateData["status"] = theme.Status
+	expected, ok := bindPrecondition(c, services.GetThemeDetail)
+	if !ok {
+		return
 	}
 
-	err := tcb.Client.UpdateData(CollectionTheme, id, updateData)
+	err := services.UpdateTheme(c.Request.Context(), id, &theme, expected)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+		respondError(c, err, "更新失败")
 		return
 	}
 
@@ -169,12 +164,12 @@ func UpdateTheme(c *gin.Context) {
 // @Tags         Themes
 // @Param        id   path      string  true  "主题ID"
 // @Success      200  {object}  map[string]interface{}
-// @Router       /themes/{id} [delete]
+// @Router       /admin/themes/{id} [delete]
 func DeleteTheme(c *gin.Context) {
 	id := c.Param("id")
-	err := tcb.Client.DeleteData(CollectionTheme, id)
+	err := services.DeleteTheme(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+		respondError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
@@ -0,0 +1,128 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/tcb"
+)
+
+func TestCreateThemeAndListByRegion(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	srv.Seed(collection.Themes.Name(), models.Theme{Name: "其他区域", RegionID: "r2", Status: 1})
+
+	w := doAs(t, r, admin, http.MethodPost, "/api/admin/themes", map[string]interface{}{"name": "汉服打卡", "cover": "c.jpg", "region_id": "r1"})
+	expectStatus(t, w, http.StatusOK)
+
+	w = do(t, r, http.MethodGet, "/api/themes?region_id=r1", nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[tcb.Page[models.Theme]](t, w)
+	if len(page.Items) != 1 {
+		t.Fatalf("items = %+v, want 1", page.Items)
+	}
+	if got := page.Items[0]; got.Name != "汉服打卡" || got.Sort != 100 || got.Status != 1 {
+		t.Errorf("theme = %+v, want defaults sort=100 status=1", got)
+	}
+}
+
+func TestGetThemeDetail(t *testing.T) {
+	srv, r := setup(t)
+	ids := srv.Seed(collection.Themes.Name(), models.Theme{Name: "古风", Status: 1})
+
+	w := do(t, r, http.MethodGet, "/api/themes/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Theme](t, w); got.Name != "古风" {
+		t.Errorf("theme = %+v", got)
+	}
+
+	w = do(t, r, http.MethodGet, "/api/themes/missing", nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if got := decode[errorResponse](t, w); got.Error != "主题不存在" {
+		t.Errorf("error = %q", got.Error)
+	}
+}
+
+func TestUpdateAndDeleteTheme(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	ids := srv.Seed(collection.Themes.Name(), models.Theme{Name: "古风", Desc: "旧简介", Status: 1})
+
+	w := doAs(t, r, admin, http.MethodPut, "/api/admin/themes/"+ids[0], map[string]interface{}{"desc": "新简介"})
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Themes.Name())[0]; rec["desc"] != "新简介" || rec["name"] != "古风" {
+		t.Errorf("record after update = %v", rec)
+	}
+
+	w = doAs(t, r, admin, http.MethodDelete, "/api/admin/themes/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	if n := len(srv.Records(collection.Themes.Name())); n != 0 {
+		t.Errorf("records after delete = %d, want 0", n)
+	}
+}
+
+func TestUpdateThemePreconditions(t *testing.T) {
+	srv, r := setup(t)
+	admin := loginAdmin(t, srv, r, auth.RoleSuperAdmin)
+	ids := srv.Seed(collection.Themes.Name(), models.Theme{Name: "古风", Status: 1, UpdatedAt: "2026-01-01T08:00:00Z"})
+
+	put := func(header, value, desc string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/admin/themes/"+ids[0], bytes.NewBufferString(`{"desc":"`+desc+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+admin)
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(t, r, http.MethodGet, "/api/themes/"+ids[0], nil)
+	expectStatus(t, w, http.StatusOK)
+	etag := w.Header().Get("ETag")
+	if etag != `"2026-01-01T08:00:00Z"` {
+		t.Fatalf("ETag = %q", etag)
+	}
+
+	// 基于旧版本的修改
+	w = put("If-Match", `"2025-12-31T08:00:00Z"`, "过期")
+	expectStatus(t, w, http.StatusPreconditionFailed)
+	body := decode[struct {
+		Code    string       `json:"code"`
+		Current models.Theme `json:"current"`
+	}](t, w)
+	if body.Code != "PRECONDITION_FAILED" || body.Current.Name != "古风" || w.Header().Get("ETag") != etag {
+		t.Errorf("412 body = %+v, ETag = %q", body, w.Header().Get("ETag"))
+	}
+
+	w = put("If-Unmodified-Since", "Wed, 31 Dec 2025 08:00:00 GMT", "过期")
+	expectStatus(t, w, http.StatusPreconditionFailed)
+
+	// If-Match 使用强比较：弱 ETag 即使值相同也不匹配
+	expectStatus(t, put("If-Match", "W/"+etag, "弱校验"), http.StatusPreconditionFailed)
+
+	w = put("If-Match", etag, "新简介")
+	expectStatus(t, w, http.StatusOK)
+	if rec := srv.Records(collection.Themes.Name())[0]; rec["desc"] != "新简介" || rec["updated_at"] == "2026-01-01T08:00:00Z" {
+		t.Errorf("record after update = %v", rec)
+	}
+
+	// 已被上一次更新改变版本
+	w = put("If-Match", etag, "覆盖")
+	expectStatus(t, w, http.StatusPreconditionFailed)
+	if rec := srv.Records(collection.Themes.Name())[0]; rec["desc"] != "新简介" {
+		t.Errorf("stale update applied: %v", rec)
+	}
+
+	// 同一秒内的连续修改同样产生新版本
+	latest := do(t, r, http.MethodGet, "/api/themes/"+ids[0], nil).Header().Get("ETag")
+	expectStatus(t, put("If-Match", latest, "再次修改"), http.StatusOK)
+	expectStatus(t, put("If-Match", latest, "覆盖"), http.StatusPreconditionFailed)
+
+	w = put("If-Unmodified-Since", "Fri, 01 Jan 2100 00:00:00 GMT", "无冲突")
+	expectStatus(t, w, http.StatusOK)
+}
@@ -15,55 +15,45 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/favorites": {
+        "/admin/devices": {
             "get": {
-                "description": "分页获取当前用户的收藏列表 (支持按资源类型筛选)",
+                "description": "已登记的旅拍机设备，不返回签名密钥 (需要 device:manage 权限)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Favorites"
+                    "Devices"
                 ],
-                "summary": "获取用户收藏列表",
+                "summary": "旅拍机设备列表",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "资源类型筛选 (theme/poi/product)",
-                        "name": "resource_type",
+                        "description": "旅拍机点位ID",
+                        "name": "poi_id",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "页码 (默认1)",
+                        "description": "页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "每页数量 (默认20, 最大100)",
+                        "description": "每页数量",
                         "name": "size",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "收藏列表",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "参数错误",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/tcb.Page-models_Device"
                         }
                     },
-                    "500": {
-                        "description": "服务器错误",
+                    "403": {
+                        "description": "无权限",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -72,7 +62,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "用户收藏旅拍主题/景点POI/商品 (幂等：重复收藏返回错误)",
+                "description": "为旅拍机点位 (type=booth) 签发设备 ID 与签名密钥 (需要 device:manage 权限)；密钥只返回这一次",
                 "consumes": [
                     "application/json"
                 ],
@@ -80,37 +70,122 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Favorites"
+                    "Devices"
                 ],
-                "summary": "收藏资源",
+                "summary": "登记旅拍机设备",
                 "parameters": [
                     {
-                        "description": "收藏请求",
+                        "description": "所属点位",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FavoriteCreateRequest"
+                            "$ref": "#/definitions/models.DeviceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "收藏成功",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceCredential"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或点位不是旅拍机",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/devices/{id}": {
+            "delete": {
+                "description": "删除设备凭证，该设备后续请求返回 401 (需要 device:manage 权限)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Devices"
+                ],
+                "summary": "吊销设备",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "设备ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/admin/devices/{id}/rotate": {
+            "post": {
+                "description": "重新生成签名密钥并启用设备，旧密钥立即失效 (需要 device:manage 权限)；新密钥只返回这一次",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Devices"
+                ],
+                "summary": "轮换设备密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "设备ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceCredential"
+                        }
                     },
-                    "400": {
-                        "description": "参数错误或已收藏",
+                    "404": {
+                        "description": "设备不存在",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/admin/me": {
+            "get": {
+                "description": "返回登录用户的角色、负责区域与权限点，管理端据此渲染菜单 (未分配角色时返回空列表)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "当前管理员信息",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdminProfile"
+                        }
                     },
-                    "500": {
-                        "description": "服务器错误",
+                    "401": {
+                        "description": "未登录",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -119,24 +194,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/comments": {
+        "/admin/pois": {
             "get": {
-                "description": "默认只显示审核通过(status=1)的评论",
+                "description": "包含未上线点位 (需要 content:manage 权限)，受区域限制的管理员只能看到负责区域内的点位",
                 "tags": [
-                    "Comments"
+                    "POI"
                 ],
-                "summary": "获取评论列表",
+                "summary": "管理端点位列表",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "点位ID",
-                        "name": "poi_id",
+                        "description": "区域ID",
+                        "name": "region_id",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "状态 (1:通过, 0:待审)",
-                        "name": "status",
+                        "type": "string",
+                        "description": "点位类型 (scenic/food/hotel/booth)",
+                        "name": "type",
                         "in": "query"
                     },
                     {
@@ -150,11 +225,23 @@ const docTemplate = `{
                         "description": "每页数量",
                         "name": "size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_POI"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限或区域不在负责范围内",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -163,7 +250,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "用户发布评论 (默认待审核 status=0)",
+                "description": "创建新的 POI 点位 (景点/饭店/酒店/旅拍机)",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,17 +258,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "POI"
                 ],
-                "summary": "发布评论",
+                "summary": "创建点位",
                 "parameters": [
                     {
-                        "description": "评论信息",
-                        "name": "comment",
+                        "description": "POI信息",
+                        "name": "poi",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Comment"
+                            "$ref": "#/definitions/models.POI"
                         }
                     }
                 ],
@@ -196,33 +283,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comments/{id}": {
-            "get": {
-                "tags": [
-                    "Comments"
-                ],
-                "summary": "获取评论详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "评论ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            },
+        "/admin/pois/{id}": {
             "put": {
-                "description": "用于管理员审核 (修改status) 或 用户点赞 (修改like_count)",
+                "description": "更新点位信息 (安全更新，忽略系统字段)",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,25 +293,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "POI"
                 ],
-                "summary": "更新评论 (审核/点赞)",
+                "summary": "更新点位",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "评论ID",
+                        "description": "POI ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新内容 (仅status/like_count)",
-                        "name": "comment",
+                        "description": "更新信息",
+                        "name": "poi",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Comment"
+                            "$ref": "#/definitions/models.POI"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -262,15 +331,14 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "管理员可删除违规评论，用户可删除自己的评论(依赖TCB权限)",
                 "tags": [
-                    "Comments"
+                    "POI"
                 ],
-                "summary": "删除评论",
+                "summary": "删除点位",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "评论ID",
+                        "description": "POI ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -287,105 +355,140 @@ const docTemplate = `{
                 }
             }
         },
-        "/favorites/{resource_type}/{resource_id}": {
-            "get": {
-                "description": "检查指定资源是否已被当前用户收藏 (RESTful路径参数)",
+        "/admin/products": {
+            "post": {
+                "description": "创建商品导流信息 (无支付，仅跳转)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Favorites"
+                    "Products"
                 ],
-                "summary": "检查是否已收藏",
+                "summary": "创建商品导流",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "资源类型 (theme/poi/product)",
-                        "name": "resource_type",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "资源ID",
-                        "name": "resource_id",
-                        "in": "path",
-                        "required": true
+                        "description": "商品信息",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "返回 {is_favorited: true/false}",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "参数错误",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "服务器错误",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "通过资源类型和资源ID取消收藏 (RESTful路径参数)",
+            }
+        },
+        "/admin/products/{id}": {
+            "put": {
+                "description": "更新商品导流信息 (支持增量更新)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Favorites"
+                    "Products"
                 ],
-                "summary": "取消收藏",
+                "summary": "更新商品导流",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "资源类型 (theme/poi/product)",
-                        "name": "resource_type",
+                        "description": "商品ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "更新内容",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "资源ID",
-                        "name": "resource_id",
-                        "in": "path",
-                        "required": true
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "取消成功",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "400": {
-                        "description": "参数错误",
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Products"
+                ],
+                "summary": "删除商品导流",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "404": {
-                        "description": "收藏记录不存在",
+                    }
+                }
+            }
+        },
+        "/admin/regions": {
+            "post": {
+                "description": "创建一个新的景区区域 (Name必填)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Regions"
+                ],
+                "summary": "创建区域",
+                "parameters": [
+                    {
+                        "description": "区域信息",
+                        "name": "region",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Region"
                         }
-                    },
-                    "500": {
-                        "description": "服务器错误",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -394,37 +497,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/photos": {
-            "get": {
-                "description": "支持按主题ID筛选，默认只显示审核通过(status=1)的照片",
+        "/admin/regions/{id}": {
+            "put": {
+                "description": "根据 ID 更新区域信息 (支持 name, sort, status)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Photos"
+                    "Regions"
                 ],
-                "summary": "获取照片列表",
+                "summary": "更新区域",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "主题ID",
-                        "name": "theme_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "状态 (1:通过, 0:待审)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "区域ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
+                        "description": "更新内容 (仅需传修改字段)",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Region"
+                        }
                     },
                     {
-                        "type": "integer",
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -437,8 +544,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "用户上传旅拍照片 (上传后默认为待审核状态 status=0)",
+            "delete": {
+                "description": "根据 ID 删除指定区域",
                 "consumes": [
                     "application/json"
                 ],
@@ -446,18 +553,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Photos"
+                    "Regions"
                 ],
-                "summary": "上传照片",
+                "summary": "删除区域",
                 "parameters": [
                     {
-                        "description": "照片信息",
-                        "name": "photo",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Photo"
-                        }
+                        "type": "string",
+                        "description": "区域ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -471,33 +576,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/photos/{id}": {
+        "/admin/roles": {
             "get": {
+                "description": "已分配角色的用户 (需要 role:manage 权限)",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Photos"
+                    "Admin"
                 ],
-                "summary": "获取照片详情",
+                "summary": "管理员列表",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "照片ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Admin"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/roles/{openid}": {
             "put": {
-                "description": "用于管理员审核 (修改status) 或 用户点赞 (修改like_count)",
+                "description": "整体替换指定用户的角色与负责区域 (需要 role:manage 权限)；region_ids 为空表示不限区域；不能移除自己的 super_admin",
                 "consumes": [
                     "application/json"
                 ],
@@ -505,30 +627,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Photos"
+                    "Admin"
                 ],
-                "summary": "更新照片 (审核/点赞)",
+                "summary": "设置用户角色",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "照片ID",
-                        "name": "id",
+                        "description": "用户 openid",
+                        "name": "openid",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新内容 (仅status/like_count)",
-                        "name": "photo",
+                        "description": "角色列表",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Photo"
+                            "$ref": "#/definitions/models.AdminRolesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Admin"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -537,16 +672,19 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "管理员可删除违规照片，用户可删除自己的照片(依赖TCB权限)",
+                "description": "移除指定用户的全部角色 (需要 role:manage 权限)；不能移除自己",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Photos"
+                    "Admin"
                 ],
-                "summary": "删除照片",
+                "summary": "移除管理员",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "照片ID",
-                        "name": "id",
+                        "description": "用户 openid",
+                        "name": "openid",
                         "in": "path",
                         "required": true
                     }
@@ -558,17 +696,24 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "该用户未分配角色",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/pois": {
+        "/admin/themes": {
             "get": {
-                "description": "查询点位列表，支持按区域、类型筛选。若传入 lat/lng，结果将包含距离信息(_distance)。",
+                "description": "按状态筛选主题 (需要 content:manage 权限)，受区域限制的管理员只能看到负责区域内的主题",
                 "tags": [
-                    "POI"
+                    "Themes"
                 ],
-                "summary": "获取点位列表 (支持LBS)",
+                "summary": "管理端主题列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -577,23 +722,9 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "点位类型 (scenic/food/hotel/booth)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "format": "float64",
-                        "description": "用户纬度 (用于计算距离)",
-                        "name": "lat",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "format": "float64",
-                        "description": "用户经度 (用于计算距离)",
-                        "name": "lng",
+                        "type": "integer",
+                        "description": "状态 (1:启用, 0:停用)",
+                        "name": "status",
                         "in": "query"
                     },
                     {
@@ -607,11 +738,23 @@ const docTemplate = `{
                         "description": "每页数量",
                         "name": "size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Theme"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限或区域不在负责范围内",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -620,7 +763,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "创建新的 POI 点位 (景点/饭店/酒店/旅拍机)",
+                "description": "创建新的旅拍活动主题 (仅管理员)",
                 "consumes": [
                     "application/json"
                 ],
@@ -628,17 +771,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "POI"
+                    "Themes"
                 ],
-                "summary": "创建点位",
+                "summary": "创建旅拍主题",
                 "parameters": [
                     {
-                        "description": "POI信息",
-                        "name": "poi",
+                        "description": "主题信息",
+                        "name": "theme",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.POI"
+                            "$ref": "#/definitions/models.Theme"
                         }
                     }
                 ],
@@ -653,58 +796,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/pois/{id}": {
-            "get": {
+        "/admin/themes/{id}": {
+            "put": {
+                "description": "更新主题信息 (支持增量更新)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "POI"
+                    "Themes"
                 ],
-                "summary": "获取点位详情",
+                "summary": "更新主题",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "POI ID",
+                        "description": "主题ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "更新内容",
+                        "name": "theme",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Theme"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.POI"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             },
-            "put": {
-                "description": "更新点位信息 (安全更新，忽略系统字段)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            "delete": {
                 "tags": [
-                    "POI"
+                    "Themes"
                 ],
-                "summary": "更新点位",
+                "summary": "删除主题",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "POI ID",
+                        "description": "主题ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "poi",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.POI"
-                        }
                     }
                 ],
                 "responses": {
@@ -716,24 +866,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/wx-login": {
+            "post": {
+                "description": "用 wx.login 获取的 code 换取 openid，并签发登录令牌 (后续请求携带 Authorization: Bearer \u003ctoken\u003e)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "POI"
+                    "Auth"
                 ],
-                "summary": "删除点位",
+                "summary": "小程序登录",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "POI ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "登录凭证",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WxLoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WxLoginResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "code 无效或已过期",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -742,14 +909,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
+        "/comments": {
             "get": {
-                "description": "商品导流列表（无支付，点击跳转）",
+                "description": "默认只显示审核通过(status=1)的评论",
                 "tags": [
-                    "Products"
+                    "Comments"
                 ],
-                "summary": "获取商品列表",
+                "summary": "获取评论列表",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "点位ID",
+                        "name": "poi_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态 (1:通过, 0:待审)，非 1 需审核权限",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "页码",
@@ -761,20 +940,25 @@ const docTemplate = `{
                         "description": "每页数量",
                         "name": "size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 content,like_count)",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/tcb.Page-models_Comment"
                         }
                     }
                 }
             },
             "post": {
-                "description": "创建商品导流信息 (无支付，仅跳转)",
+                "description": "登录用户发布评论 (默认待审核 status=0)",
                 "consumes": [
                     "application/json"
                 ],
@@ -782,17 +966,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Comments"
                 ],
-                "summary": "创建商品导流",
+                "summary": "发布评论",
                 "parameters": [
                     {
-                        "description": "商品信息",
-                        "name": "product",
+                        "description": "评论信息",
+                        "name": "comment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Comment"
                         }
                     }
                 ],
@@ -807,16 +991,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
+        "/comments/{id}": {
             "get": {
                 "tags": [
-                    "Products"
+                    "Comments"
                 ],
-                "summary": "获取商品详情",
+                "summary": "获取评论详情",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "商品ID",
+                        "description": "评论ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -826,14 +1010,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Comment"
                         }
                     }
                 }
             },
             "put": {
-                "description": "更新商品导流信息 (支持增量更新)",
+                "description": "管理员审核 (修改status)；发布者可修改内容 (修改后重新待审)。点赞请使用 /like 接口",
                 "consumes": [
                     "application/json"
                 ],
@@ -841,25 +1024,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Comments"
                 ],
-                "summary": "更新商品导流",
+                "summary": "更新评论 (审核)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "商品ID",
+                        "description": "评论ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新内容",
-                        "name": "product",
+                        "description": "更新内容 (status / content)",
+                        "name": "comment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Comment"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -873,14 +1062,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "发布者可删除自己的评论，管理员可删除任意评论",
                 "tags": [
-                    "Products"
+                    "Comments"
                 ],
-                "summary": "删除商品导流",
+                "summary": "删除评论",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "商品ID",
+                        "description": "评论ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -897,37 +1087,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/regions": {
-            "get": {
-                "description": "查询区域列表，支持分页",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/comments/{id}/like": {
+            "post": {
+                "description": "需要登录；每个用户对同一评论只能点赞一次。点赞数原子加一，返回最新点赞数",
                 "tags": [
-                    "Regions"
+                    "Comments"
                 ],
-                "summary": "获取所有区域",
+                "summary": "点赞评论",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "页码 (默认1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "每页数量 (默认100)",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "状态 (1:启用, 0:禁用)",
-                        "name": "status",
-                        "in": "query"
+                        "type": "string",
+                        "description": "评论ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -937,35 +1110,43 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "401": {
+                        "description": "未登录",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "已点赞",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "创建一个新的景区区域 (Name必填)",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/device/booth": {
+            "get": {
+                "description": "设备签名请求：返回调用方设备所属的旅拍机点位。请求头 X-Device-Id / X-Timestamp / X-Nonce / X-Signature，\n签名为 HMAC-SHA256(密钥, \"METHOD\\n路径(含查询串)\\n时间戳\\nnonce\\n请求体SHA256\") 的十六进制编码",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Regions"
-                ],
-                "summary": "创建区域",
-                "parameters": [
-                    {
-                        "description": "区域信息",
-                        "name": "region",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Region"
-                        }
-                    }
+                    "Devices"
                 ],
+                "summary": "当前旅拍机点位",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.POI"
+                        }
+                    },
+                    "401": {
+                        "description": "签名无效 / 过期 / 重放",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -974,31 +1155,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/regions/{id}": {
+        "/favorites": {
             "get": {
-                "description": "根据 ID 获取单个区域的详细信息",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "分页获取当前用户的收藏列表 (支持按资源类型筛选)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Regions"
+                    "Favorites"
                 ],
-                "summary": "获取区域详情",
+                "summary": "获取用户收藏列表",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "区域ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "资源类型筛选 (theme/poi/product)",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码 (默认1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量 (默认20, 最大 PAGE_MAX_SIZE)",
+                        "name": "size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "收藏列表",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Favorite"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1006,8 +1210,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "根据 ID 更新区域信息 (支持 name, sort, status)",
+            "post": {
+                "description": "用户收藏旅拍主题/景点POI/商品 (幂等：重复收藏返回错误)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1015,58 +1219,181 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Regions"
+                    "Favorites"
                 ],
-                "summary": "更新区域",
+                "summary": "收藏资源",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "区域ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新内容 (仅需传修改字段)",
-                        "name": "data",
+                        "description": "收藏请求",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Region"
+                            "$ref": "#/definitions/models.FavoriteCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "收藏成功",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            },
-            "delete": {
-                "description": "根据 ID 删除指定区域",
-                "consumes": [
+                    },
+                    "400": {
+                        "description": "参数错误或已收藏",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/favorites/{resource_type}/{resource_id}": {
+            "get": {
+                "description": "检查指定资源是否已被当前用户收藏 (RESTful路径参数)",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "检查是否已收藏",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "资源类型 (theme/poi/product)",
+                        "name": "resource_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "资源ID",
+                        "name": "resource_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "返回 {is_favorited: true/false}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "通过资源类型和资源ID取消收藏 (RESTful路径参数)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Regions"
+                    "Favorites"
                 ],
-                "summary": "删除区域",
+                "summary": "取消收藏",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "区域ID",
-                        "name": "id",
+                        "description": "资源类型 (theme/poi/product)",
+                        "name": "resource_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "资源ID",
+                        "name": "resource_id",
                         "in": "path",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "取消成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "收藏记录不存在",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files": {
+            "post": {
+                "description": "上传到云存储，返回 fileID (写入 image_url / images / cover / image 字段) 及临时链接。\n需要登录；pois / themes 目录需要 content:manage 权限，products 目录需要 product:manage 权限",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "上传图片",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "图片 (不超过 10MB)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "目录: photos / pois / themes / products",
+                        "name": "dir",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1074,27 +1401,41 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "401": {
+                        "description": "未登录",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "无权上传到该目录",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/themes": {
+        "/photos": {
             "get": {
-                "description": "支持按区域筛选。实现PRD“区域优先推荐”：前端应先传region_id查询，若为空则不传region_id查全局。",
+                "description": "支持按主题ID筛选，默认只显示审核通过(status=1)的照片",
                 "tags": [
-                    "Themes"
+                    "Photos"
                 ],
-                "summary": "获取主题列表",
+                "summary": "获取照片列表",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "区域ID",
-                        "name": "region_id",
+                        "description": "主题ID",
+                        "name": "theme_id",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "状态 (1:启用)",
+                        "description": "状态 (1:通过, 0:待审)，非 1 需审核权限",
                         "name": "status",
                         "in": "query"
                     },
@@ -1109,20 +1450,25 @@ const docTemplate = `{
                         "description": "每页数量",
                         "name": "size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 image_url,like_count)",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/tcb.Page-models_Photo"
                         }
                     }
                 }
             },
             "post": {
-                "description": "创建新的旅拍活动主题 (仅管理员)",
+                "description": "登录用户上传旅拍照片 (上传后默认为待审核状态 status=0)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1130,17 +1476,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Themes"
+                    "Photos"
                 ],
-                "summary": "创建旅拍主题",
+                "summary": "上传照片",
                 "parameters": [
                     {
-                        "description": "主题信息",
-                        "name": "theme",
+                        "description": "照片信息",
+                        "name": "photo",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Theme"
+                            "$ref": "#/definitions/models.Photo"
                         }
                     }
                 ],
@@ -1155,16 +1501,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/themes/{id}": {
+        "/photos/{id}": {
             "get": {
                 "tags": [
-                    "Themes"
+                    "Photos"
                 ],
-                "summary": "获取主题详情",
+                "summary": "获取照片详情",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "主题ID",
+                        "description": "照片ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1174,14 +1520,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Photo"
                         }
                     }
                 }
             },
             "put": {
-                "description": "更新主题信息 (支持增量更新)",
+                "description": "管理员审核 (修改status)；发布者可修改自己的照片。点赞请使用 /like 接口",
                 "consumes": [
                     "application/json"
                 ],
@@ -1189,25 +1534,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Themes"
+                    "Photos"
                 ],
-                "summary": "更新主题",
+                "summary": "更新照片 (审核)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "主题ID",
+                        "description": "照片ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新内容",
-                        "name": "theme",
+                        "description": "更新内容 (仅status)",
+                        "name": "photo",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Theme"
+                            "$ref": "#/definitions/models.Photo"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，记录已被修改时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1221,32 +1572,470 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "发布者可删除自己的照片，管理员可删除任意照片",
                 "tags": [
-                    "Themes"
+                    "Photos"
                 ],
-                "summary": "删除主题",
+                "summary": "删除照片",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "主题ID",
+                        "description": "照片ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/like": {
+            "post": {
+                "description": "需要登录；每个用户对同一照片只能点赞一次。点赞数原子加一，返回最新点赞数",
+                "tags": [
+                    "Photos"
+                ],
+                "summary": "点赞照片",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "照片ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未登录",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "已点赞",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/pois": {
+            "get": {
+                "description": "查询点位列表，支持按区域、类型筛选。若传入 lat/lng，结果将包含距离信息(_distance)。",
+                "tags": [
+                    "POI"
+                ],
+                "summary": "获取点位列表 (支持LBS)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "区域ID",
+                        "name": "region_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "点位类型 (scenic/food/hotel/booth)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "format": "float64",
+                        "description": "用户纬度 (用于计算距离)",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "format": "float64",
+                        "description": "用户经度 (用于计算距离)",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 name,latitude,longitude)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_POI"
+                        }
+                    }
+                }
+            }
+        },
+        "/pois/{id}": {
+            "get": {
+                "tags": [
+                    "POI"
+                ],
+                "summary": "获取点位详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "POI ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.POI"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "商品导流列表（无支付，点击跳转）",
+                "tags": [
+                    "Products"
+                ],
+                "summary": "获取商品列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 name,image,price)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "tags": [
+                    "Products"
+                ],
+                "summary": "获取商品详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions": {
+            "get": {
+                "description": "查询区域列表，支持分页",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Regions"
+                ],
+                "summary": "获取所有区域",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码 (默认1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量 (默认100)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态 (1:启用, 0:禁用)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 name,sort)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Region"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{id}": {
+            "get": {
+                "description": "根据 ID 获取单个区域的详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Regions"
+                ],
+                "summary": "获取区域详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "区域ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Region"
+                        }
+                    }
+                }
+            }
+        },
+        "/themes": {
+            "get": {
+                "description": "支持按区域筛选。实现PRD“区域优先推荐”：前端应先传region_id查询，若为空则不传region_id查全局。",
+                "tags": [
+                    "Themes"
+                ],
+                "summary": "获取主题列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "区域ID",
+                        "name": "region_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态 (1:启用)，非 1 需内容管理权限",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "字段投影，逗号分隔 (如 name,cover)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tcb.Page-models_Theme"
+                        }
+                    }
+                }
+            }
+        },
+        "/themes/{id}": {
+            "get": {
+                "tags": [
+                    "Themes"
+                ],
+                "summary": "获取主题详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "主题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Theme"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.Admin": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "description": "TCB 自动生成的 ID",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "openid": {
+                    "description": "被授权用户的 openid",
+                    "type": "string"
+                },
+                "region_ids": {
+                    "description": "负责区域，为空表示不限区域 (对超级管理员不生效)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remark": {
+                    "description": "备注 (姓名、所属商户等)",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "角色: super_admin / content_moderator / merchant_operator",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdminProfile": {
+            "type": "object",
+            "properties": {
+                "openid": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "region_ids": {
+                    "description": "负责区域，为空表示不限区域",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "models.AdminRolesRequest": {
+            "type": "object",
+            "required": [
+                "roles"
+            ],
+            "properties": {
+                "region_ids": {
+                    "description": "负责区域 (整体替换)，为空表示不限区域",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remark": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.Comment": {
             "type": "object",
             "properties": {
@@ -1288,6 +2077,101 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Device": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "description": "TCB 自动生成的 ID，作为设备 ID",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "poi_id": {
+                    "description": "所属旅拍机点位 (type=booth)",
+                    "type": "string"
+                },
+                "remark": {
+                    "description": "备注 (设备编号、安装位置等)",
+                    "type": "string"
+                },
+                "rotated_at": {
+                    "description": "最近一次签发密钥的时间",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "签名密钥，仅签发 / 轮换时返回一次",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态 1:启用 0:停用",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceCredential": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "poi_id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceRequest": {
+            "type": "object",
+            "required": [
+                "poi_id"
+            ],
+            "properties": {
+                "poi_id": {
+                    "type": "string"
+                },
+                "remark": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Favorite": {
+            "type": "object",
+            "required": [
+                "resource_id",
+                "resource_type"
+            ],
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "_openid": {
+                    "description": "系统字段：用户标识",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "description": "资源ID",
+                    "type": "string"
+                },
+                "resource_type": {
+                    "description": "资源类型: theme/poi/product",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.FavoriteCreateRequest": {
             "type": "object",
             "required": [
@@ -1453,6 +2337,10 @@ const docTemplate = `{
                     "description": "商品价格 (仅展示，无支付)",
                     "type": "number"
                 },
+                "status": {
+                    "description": "1: 上架, 0: 下架",
+                    "type": "integer"
+                },
                 "updated_at": {
                     "description": "业务更新时间",
                     "type": "string"
@@ -1536,6 +2424,212 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.WxLoginRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WxLoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "openid": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "tcb.Page-models_Admin": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Admin"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Comment": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Comment"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Device": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Device"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Favorite": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Favorite"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_POI": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.POI"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Photo": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Photo"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Product": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Region": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Region"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tcb.Page-models_Theme": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Theme"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
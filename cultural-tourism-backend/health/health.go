@@ -0,0 +1,170 @@
+// Package health 存活 (/healthz) 与就绪 (/readyz) 探针
+//
+// 存活探针只说明进程还能处理请求；就绪探针逐项探测依赖 (云开发网关、数据模型等)，
+// 关键依赖失败或实例正在停机时返回 503，让 CloudRun 把流量切到其他实例。
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Status 整体或单项依赖状态
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded" // 非关键依赖异常，仍可对外服务
+	StatusDown     Status = "down"     // 关键依赖异常，不应接收流量
+	StatusDraining Status = "draining" // 收到停机信号，等待在途请求结束
+)
+
+// Check 单项依赖检查
+type Check struct {
+	Name     string
+	Critical bool                            // 失败时整体为 down；否则只标记 degraded
+	Probe    func(ctx context.Context) error // 返回 nil 表示正常
+	Details  func() interface{}              // 可选：附带的统计信息 (如调用统计、熔断器状态)
+}
+
+// Result 单项检查结果
+type Result struct {
+	Status    Status      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	LatencyMS int64       `json:"latency_ms"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status    Status            `json:"status"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    map[string]Result `json:"checks"`
+}
+
+// 默认参数
+const (
+	DefaultTimeout  = 3 * time.Second // 单次就绪检查的总超时
+	DefaultCacheTTL = 5 * time.Second // 报告复用时间，避免探针频繁打到网关
+)
+
+// Checker 依赖检查集合，零值不可用，请使用 New
+type Checker struct {
+	Timeout  time.Duration
+	CacheTTL time.Duration
+
+	started  time.Time
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	checks []Check
+	last   *Report
+}
+
+// New 创建检查器
+func New() *Checker {
+	return &Checker{Timeout: DefaultTimeout, CacheTTL: DefaultCacheTTL, started: time.Now()}
+}
+
+// Add 注册一项依赖检查
+func (h *Checker) Add(c Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, c)
+}
+
+// Drain 标记实例正在停机，之后就绪探针一律返回 503
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Ready 执行全部检查 (并发，受 Timeout 约束)；CacheTTL 内重复调用复用上次结果
+// 请求方中途取消 (如探针客户端断开) 时的结果不代表依赖状态，不缓存
+func (h *Checker) Ready(ctx context.Context) Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.last != nil && time.Since(h.last.CheckedAt) < h.CacheTTL {
+		return h.withDraining(*h.last)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	results := make([]Result, len(h.checks))
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(probeCtx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, CheckedAt: time.Now(), Checks: make(map[string]Result, len(h.checks))}
+	for i, c := range h.checks {
+		r := results[i]
+		report.Checks[c.Name] = r
+		switch {
+		case r.Status == StatusDown:
+			report.Status = StatusDown
+		case r.Status == StatusDegraded && report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	if ctx.Err() == nil {
+		h.last = &report
+	}
+	return h.withDraining(report)
+}
+
+func (h *Checker) withDraining(r Report) Report {
+	if h.draining.Load() {
+		r.Status = StatusDraining
+	}
+	return r
+}
+
+func run(ctx context.Context, c Check) Result {
+	start := time.Now()
+	var err error
+	if c.Probe != nil {
+		err = c.Probe(ctx)
+	}
+	r := Result{Status: StatusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if c.Details != nil {
+		r.Details = c.Details()
+	}
+	if err != nil {
+		r.Error = err.Error()
+		r.Status = StatusDegraded
+		if c.Critical {
+			r.Status = StatusDown
+		}
+	}
+	return r
+}
+
+// Healthz 存活探针：进程能响应即返回 200
+func (h *Checker) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": StatusOK,
+		"uptime": time.Since(h.started).Round(time.Second).String(),
+	})
+}
+
+// Readyz 就绪探针：ok / degraded 返回 200，down / draining 返回 503
+func (h *Checker) Readyz(c *gin.Context) {
+	report := h.Ready(c.Request.Context())
+	code := http.StatusOK
+	if report.Status == StatusDown || report.Status == StatusDraining {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
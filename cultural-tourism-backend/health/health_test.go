@@ -0,0 +1,107 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"cultural-tourism-backend/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+func readyz(t *testing.T, h *health.Checker) (int, health.Report) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", h.Readyz)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report health.Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid body %s: %v", w.Body, err)
+	}
+	return w.Code, report
+}
+
+func TestReadyz(t *testing.T) {
+	var gatewayErr, breakerErr error // 探测在 Ready 返回前完成，无需加锁
+	probe := func(err *error) func(context.Context) error {
+		return func(context.Context) error { return *err }
+	}
+
+	h := health.New()
+	h.CacheTTL = 0
+	h.Add(health.Check{Name: "collections", Critical: true, Probe: probe(&gatewayErr)})
+	h.Add(health.Check{Name: "tcb_gateway", Probe: probe(&breakerErr), Details: func() interface{} { return map[string]int{"requests": 3} }})
+
+	if code, report := readyz(t, h); code != http.StatusOK || report.Status != health.StatusOK {
+		t.Fatalf("healthy: %d %+v", code, report)
+	}
+
+	// 非关键依赖异常：仍然就绪，但标记 degraded
+	breakerErr = errors.New("熔断器状态: open")
+	code, report := readyz(t, h)
+	if code != http.StatusOK || report.Status != health.StatusDegraded {
+		t.Fatalf("degraded: %d %+v", code, report)
+	}
+	if r := report.Checks["tcb_gateway"]; r.Error == "" || r.Details == nil {
+		t.Errorf("tcb_gateway result = %+v", r)
+	}
+
+	// 关键依赖异常：503
+	gatewayErr = errors.New("pois (pois): 数据模型不存在")
+	if code, report := readyz(t, h); code != http.StatusServiceUnavailable || report.Status != health.StatusDown {
+		t.Fatalf("down: %d %+v", code, report)
+	}
+
+	// 停机中：即使依赖恢复也返回 503
+	gatewayErr, breakerErr = nil, nil
+	h.Drain()
+	if code, report := readyz(t, h); code != http.StatusServiceUnavailable || report.Status != health.StatusDraining {
+		t.Fatalf("draining: %d %+v", code, report)
+	}
+}
+
+func TestReadyzCachesReport(t *testing.T) {
+	var calls atomic.Int32
+	h := health.New()
+	h.Add(health.Check{Name: "collections", Critical: true, Probe: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+
+	for range 3 {
+		readyz(t, h)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("probe ran %d times within CacheTTL, want 1", n)
+	}
+}
+
+func TestReadyDoesNotCacheCancelledRequests(t *testing.T) {
+	var calls atomic.Int32
+	h := health.New()
+	h.Add(health.Check{Name: "collections", Critical: true, Probe: func(ctx context.Context) error {
+		calls.Add(1)
+		return ctx.Err()
+	}})
+
+	// 请求方已取消：探测失败，但结果不缓存
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := h.Ready(ctx); report.Status != health.StatusDown {
+		t.Fatalf("cancelled: %+v", report)
+	}
+	if code, report := readyz(t, h); code != http.StatusOK || report.Status != health.StatusOK {
+		t.Fatalf("after cancelled request: %d %+v", code, report)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("probe ran %d times, want 2", n)
+	}
+}
@@ -2,8 +2,28 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/config"
+	"cultural-tourism-backend/health"
+	modeljson "cultural-tourism-backend/model-json"
 	"cultural-tourism-backend/routes"
+	"cultural-tourism-backend/schemacheck"
+	"cultural-tourism-backend/services"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/store/cache"
+	"cultural-tourism-backend/store/local"
 	"cultural-tourism-backend/tcb" // 引入 tcb
+	"cultural-tourism-backend/tcb/storage"
 
 	_ "cultural-tourism-backend/docs"
 
@@ -16,15 +36,256 @@ import (
 // @BasePath        /api
 // @schemes         https http
 func main() {
-	// 1. 初始化云开发 HTTP 客户端
-	tcb.Init()
+	// 0. 加载并校验配置 (环境变量 / .env / 可选 YAML 文件)，缺失必填项直接 Panic
+	cfg := config.MustLoad()
+	config.AppConfig = cfg
+
+	// 1. 初始化数据存储 (默认云开发 HTTP 客户端)，读操作默认经过缓存
+	backend := openStore(cfg)
+	verifyCollections(cfg.Store, backend)
+	declareUniqueFields(backend)
+	ds := backend
+	if !cfg.Cache.Disabled {
+		// 管理员角色与设备凭证不缓存：吊销 / 轮换需要在所有实例上立即生效
+		opts := cache.OptionsFromConfig(cfg.Cache)
+		opts.Exclude = map[string]bool{collection.Admins.Name(): true, collection.Devices.Name(): true, collection.DeviceNonces.Name(): true}
+		ds = cache.Wrap(ds, opts)
+	}
+	store.Default = ds
+
+	checkSchema(cfg.Features.SchemaCheck)
+	initAuth(cfg.Auth)
 
 	// 2. 初始化 Gin
 	r := gin.Default()
 
+	// 云存储：本地模式下由本服务提供文件下载
+	storage.Default = openStorage(cfg)
+	if fs, ok := storage.Default.(*storage.Local); ok {
+		r.GET("/files/*path", gin.WrapH(http.StripPrefix("/files", fs)))
+	}
+
+	// 存活 / 就绪探针 (CloudRun 健康检查，不在 /api 下)
+	checker := newHealthChecker(cfg.Store, backend, ds)
+	r.GET("/healthz", checker.Healthz)
+	r.GET("/readyz", checker.Readyz)
+
 	// 3. 注册路由
 	routes.RegisterRoutes(r)
 
-	// 4. 启动
-	r.Run(":8080")
+	// 4. 启动，收到 SIGTERM / Ctrl+C 后优雅停机
+	serve(cfg.Server, r, checker)
+}
+
+// serve 启动 HTTP 服务并阻塞到停机完成
+// 停机顺序：就绪探针返回 503 -> 等待 DrainDelay 让 CloudRun 切走流量 -> 停止接收新连接并等待在途请求 (最长 ShutdownTimeout)
+func serve(cfg config.Server, handler http.Handler, checker *health.Checker) {
+	srv := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Printf("🚀 服务已启动: %s\n", cfg.Addr())
+		errCh <- srv.ListenAndServe()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	select {
+	case err := <-errCh:
+		log.Fatalf("❌ 服务启动失败: %v", err)
+	case <-ctx.Done():
+	}
+	stop() // 再次收到信号时直接退出
+
+	fmt.Printf("🛑 收到停机信号，%s 后停止接收新请求\n", cfg.DrainDelay)
+	checker.Drain()
+	time.Sleep(cfg.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ 等待在途请求超时 (%s)，强制退出: %v", cfg.ShutdownTimeout, err)
+		return
+	}
+	fmt.Println("✅ 服务已优雅停止")
+}
+
+// newHealthChecker 注册就绪检查项
+// backend 为未经缓存的数据存储，确保探测真实到达云开发网关；
+// store.verify_models=warn 时数据模型缺失只标记 degraded，不摘除实例流量
+func newHealthChecker(cfg config.Store, backend, ds store.DataStore) *health.Checker {
+	checker := health.New()
+	checker.Add(health.Check{
+		Name:     "collections",
+		Critical: cfg.VerifyModels != "warn",
+		Probe: func(ctx context.Context) error {
+			return collection.Verify(ctx, backend)
+		},
+	})
+	if client, ok := backend.(*tcb.CloudBaseClient); ok {
+		// 熔断器未闭合说明网关近期连续失败，实例仍可服务缓存命中的读请求
+		checker.Add(health.Check{
+			Name: "tcb_gateway",
+			Probe: func(ctx context.Context) error {
+				if state := client.Stats().BreakerState; state != tcb.BreakerClosed.String() {
+					return fmt.Errorf("熔断器状态: %s", state)
+				}
+				return nil
+			},
+			Details: func() interface{} { return client.Stats() },
+		})
+	}
+	if c, ok := ds.(*cache.Store); ok {
+		checker.Add(health.Check{
+			Name:    "cache",
+			Details: func() interface{} { return c.Stats() },
+		})
+	}
+	return checker
+}
+
+// openStore 按 store.backend (DATA_STORE) 选择数据存储后端
+// tcb (默认): CloudBase 数据模型 HTTP API
+// local: 单文件嵌入式存储，演示 / 本地 QA 完全离线运行
+func openStore(cfg *config.Config) store.DataStore {
+	switch cfg.Store.Backend {
+	case "local":
+		s, err := local.Open(cfg.Store.LocalPath)
+		if err != nil {
+			log.Fatalf("❌ 打开本地存储失败: %v", err)
+		}
+		fmt.Printf("💾 使用本地嵌入式存储 (离线模式): %s\n", cfg.Store.LocalPath)
+		return s
+	default:
+		tcb.Init(cfg.TCB)
+		return tcb.Client
+	}
+}
+
+// openStorage 按 storage.backend (STORAGE_BACKEND，默认与数据存储一致) 选择云存储
+// tcb: CloudBase 云存储，临时链接有效期 storage.url_max_age
+// local: 本地目录 storage.local_dir，通过 storage.local_url 下载
+func openStorage(cfg *config.Config) storage.Storage {
+	switch cfg.StorageBackend() {
+	case "local":
+		s, err := storage.NewLocal(cfg.Storage.LocalDir, cfg.Storage.LocalURL)
+		if err != nil {
+			log.Fatalf("❌ 打开本地文件存储失败: %v", err)
+		}
+		fmt.Printf("🗂️ 使用本地文件存储 (离线模式): %s\n", cfg.Storage.LocalDir)
+		return s
+	default:
+		if tcb.Client == nil {
+			tcb.Init(cfg.TCB)
+		}
+		s := storage.New(tcb.Client)
+		s.MaxAge = cfg.Storage.URLMaxAge
+		return storage.WithURLCache(s, s.MaxAge/2)
+	}
+}
+
+// verifyCollections 应用集合名配置 (store.collection_names，如 photos=photo_v2)
+// 并逐个探测数据模型 (store.verify_models)，避免集合名写错时接口静默返回空列表
+// warn (默认): 打印缺失的模型继续启动，便于新模型上线前先发布代码；strict: 任一不存在则拒绝启动；off: 跳过
+func verifyCollections(cfg config.Store, ds store.DataStore) {
+	overrides := make(map[collection.Resource]string, len(cfg.CollectionNames))
+	for r, name := range cfg.CollectionNames {
+		overrides[collection.Resource(r)] = name
+	}
+	if err := collection.Configure(overrides); err != nil {
+		panic(fmt.Sprintf("配置错误: COLLECTION_NAMES: %v", err))
+	}
+	if cfg.VerifyModels == "off" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := collection.Verify(ctx, ds); err != nil {
+		if cfg.VerifyModels == "strict" {
+			log.Fatalf("❌ 数据模型探测失败，拒绝启动:\n%v", err)
+		}
+		log.Printf("⚠️ 数据模型探测失败，依赖这些模型的接口将不可用 (部署步骤见 PROJECT_CONTEXT.md):\n%v", err)
+		return
+	}
+	fmt.Printf("✅ 数据模型探测通过 (%d 个集合)\n", len(collection.All()))
+}
+
+// declareUniqueFields 本地存储按 model-json 的 x-unique 声明施加唯一约束 (如 likes.like_key)，
+// 与线上数据模型一致；CloudBase 由数据模型自身保证
+func declareUniqueFields(ds store.DataStore) {
+	s, ok := ds.(*local.Store)
+	if !ok {
+		return
+	}
+	uniques, err := schemacheck.UniqueFields(modeljson.FS)
+	if err != nil {
+		log.Fatalf("❌ 读取数据模型定义失败: %v", err)
+	}
+	for _, r := range collection.All() {
+		if name, ok := collection.Default(r); ok {
+			s.Unique(r.Name(), uniques[name]...)
+		}
+	}
+}
+
+// initAuth 初始化登录令牌签发器、code2session 实现、管理员角色来源与旅拍机签名校验
+func initAuth(cfg config.Auth) {
+	tokens, err := auth.NewIssuer(cfg.JWTSecret, cfg.TokenTTL)
+	if err != nil {
+		panic(fmt.Sprintf("配置错误: %v", err))
+	}
+	auth.Tokens = tokens
+	auth.RoleStore = services.AdminRoleSource{}
+	auth.SuperAdmins = make(map[string]bool, len(cfg.AdminOpenIDs))
+	for _, openID := range cfg.AdminOpenIDs {
+		auth.SuperAdmins[openID] = true
+	}
+	auth.Devices, err = auth.NewDeviceVerifier(services.BoothDeviceSource{}, &services.DeviceNonceStore{}, cfg.DeviceSignatureWindow)
+	if err != nil {
+		panic(fmt.Sprintf("配置错误: %v", err))
+	}
+
+	if cfg.WxLoginStub {
+		fmt.Println("🧪 小程序登录使用本地替身 (WX_LOGIN_STUB)，不调用微信 code2session")
+		auth.Sessions = auth.StubExchanger{}
+		return
+	}
+	auth.Sessions = auth.NewWeChatClient(cfg.WxAppID, cfg.WxAppSecret)
+}
+
+// checkSchema 启动时检查模型与 model-json 是否一致 (features.schema_check)
+// warn (默认): 打印问题继续启动；strict: 有问题拒绝启动；off: 跳过
+// 源码目录存在时 (本地开发) 额外检查查询字段索引，容器镜像中只检查模型结构
+func checkSchema(mode string) {
+	if mode == "off" {
+		return
+	}
+
+	var dirs []string
+	for _, d := range []string{"services", "controllers"} {
+		if info, err := os.Stat(d); err == nil && info.IsDir() {
+			dirs = append(dirs, d)
+		}
+	}
+	report, err := schemacheck.Check(schemacheck.Options{Schemas: modeljson.FS, SourceDirs: dirs})
+	if err != nil {
+		log.Fatalf("❌ 数据模型检查失败: %v", err)
+	}
+	if len(report.Issues) == 0 {
+		fmt.Println("✅ 数据模型检查通过")
+		return
+	}
+
+	fmt.Printf("⚠️ 数据模型检查发现 %d 个问题 (详见 go run ./cmd/schemacheck):\n", len(report.Issues))
+	report.Print(os.Stdout)
+	if mode == "strict" {
+		log.Fatal("❌ SCHEMA_CHECK=strict，拒绝启动")
+	}
 }
@@ -0,0 +1,10 @@
+// Package modeljson 内嵌 CloudBase 数据模型定义 (控制台导出的 *_model.json)
+// 供 schemacheck 在没有源码目录的运行环境 (如容器镜像) 中使用。
+package modeljson
+
+import "embed"
+
+// FS 全部模型定义文件
+//
+//go:embed *.json
+var FS embed.FS
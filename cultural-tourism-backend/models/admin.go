@@ -0,0 +1,28 @@
+// File: models/admin.go
+package models
+
+// Admin 管理员角色分配 (一个用户一条记录)
+type Admin struct {
+	ID        string   `json:"_id,omitempty"` // TCB 自动生成的 ID
+	OpenID    string   `json:"openid"`        // 被授权用户的 openid
+	Roles     []string `json:"roles"`         // 角色: super_admin / content_moderator / merchant_operator
+	RegionIDs []string `json:"region_ids"`    // 负责区域，为空表示不限区域 (对超级管理员不生效)
+	Remark    string   `json:"remark"`        // 备注 (姓名、所属商户等)
+	CreatedAt string   `json:"created_at"`    // 创建时间
+	UpdatedAt string   `json:"updated_at"`    // 更新时间
+}
+
+// AdminRolesRequest 设置用户角色 (整体替换)
+type AdminRolesRequest struct {
+	Roles     []string `json:"roles" binding:"required,min=1,dive,oneof=super_admin content_moderator merchant_operator"`
+	RegionIDs []string `json:"region_ids"` // 负责区域 (整体替换)，为空表示不限区域
+	Remark    string   `json:"remark"`
+}
+
+// AdminProfile 当前登录用户的角色与权限 (管理端据此渲染菜单)
+type AdminProfile struct {
+	OpenID      string   `json:"openid"`
+	Roles       []string `json:"roles"`
+	RegionIDs   []string `json:"region_ids"` // 负责区域，为空表示不限区域
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// WxLoginRequest 小程序登录请求 (wx.login 返回的 code)
+type WxLoginRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// WxLoginResponse 登录结果，后续请求携带 Authorization: Bearer <token>
+type WxLoginResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"` // RFC3339
+	OpenID    string `json:"openid"`
+}
@@ -20,8 +20,9 @@ type Comment struct {
 
 // CommentQuery 评论筛选
 type CommentQuery struct {
-	POIID  string `form:"poi_id"`           // 查某个景点的评论
-	Status int    `form:"status,default=1"` // 默认只查过审的
-	Page   int    `form:"page,default=1"`
-	Size   int    `form:"size,default=20"`
+	POIID  string   `form:"poi_id"`           // 查某个景点的评论
+	Status int      `form:"status,default=1"` // 默认只查过审的
+	Page   int      `form:"page,default=1"`
+	Size   int      `form:"size,default=20"`
+	Fields []string `form:"-"` // 字段投影，由控制器按白名单解析 ?fields=
 }
@@ -0,0 +1,42 @@
+// File: models/device.go
+package models
+
+// Device 旅拍机设备凭证 (一台设备一条记录，_id 即设备 ID)
+type Device struct {
+	ID        string `json:"_id,omitempty"`    // TCB 自动生成的 ID，作为设备 ID
+	POIID     string `json:"poi_id"`           // 所属旅拍机点位 (type=booth)
+	Secret    string `json:"secret,omitempty"` // 签名密钥，仅签发 / 轮换时返回一次
+	Status    int    `json:"status"`           // 状态 1:启用 0:停用
+	Remark    string `json:"remark"`           // 备注 (设备编号、安装位置等)
+	RotatedAt string `json:"rotated_at"`       // 最近一次签发密钥的时间
+	CreatedAt string `json:"created_at"`       // 创建时间
+	UpdatedAt string `json:"updated_at"`       // 更新时间
+}
+
+// DeviceRequest 登记旅拍机设备
+type DeviceRequest struct {
+	POIID  string `json:"poi_id" binding:"required"`
+	Remark string `json:"remark"`
+}
+
+// DeviceQuery 设备列表筛选参数
+type DeviceQuery struct {
+	POIID string `form:"poi_id"`
+	Page  int    `form:"page,default=1"`
+	Size  int    `form:"size,default=20"`
+}
+
+// DeviceCredential 设备凭证，密钥只在签发 / 轮换时返回，需写入设备本地配置
+type DeviceCredential struct {
+	DeviceID string `json:"device_id"`
+	POIID    string `json:"poi_id"`
+	Secret   string `json:"secret"`
+}
+
+// DeviceNonce 已使用的签名 nonce (nonce_key 由设备 ID 与 nonce 派生，模型中声明为唯一字段，重复写入即为重放)
+type DeviceNonce struct {
+	ID        string `json:"_id,omitempty"`
+	DeviceID  string `json:"device_id"`  // 设备 ID
+	Key       string `json:"nonce_key"`  // sha256(设备ID + nonce) 十六进制
+	ExpiresAt int64  `json:"expires_at"` // 过期时间 (Unix 秒)，过期后可清理
+}
@@ -0,0 +1,17 @@
+package models
+
+// 点赞对象类型
+const (
+	LikeTargetPhoto   = "photo"
+	LikeTargetComment = "comment"
+)
+
+// Like 点赞记录 (like_key 由用户、对象类型与对象 ID 派生，模型中声明为唯一字段，同一用户对同一对象只能写入一次)
+type Like struct {
+	ID         string `json:"_id,omitempty"`
+	OpenID     string `json:"_openid,omitempty"` // 点赞用户
+	TargetType string `json:"target_type"`       // photo / comment
+	TargetID   string `json:"target_id"`         // 照片或评论 ID
+	Key        string `json:"like_key"`          // sha256(openid + 类型 + 对象ID) 十六进制
+	CreatedAt  string `json:"created_at"`
+}
@@ -16,8 +16,9 @@ type Photo struct {
 
 // PhotoQuery 照片列表筛选参数
 type PhotoQuery struct {
-	ThemeID string `form:"theme_id"`         // 场景：查看某主题下的瀑布流
-	Status  int    `form:"status,default=1"` // 场景：前端默认只展示已过审(1)的照片
-	Page    int    `form:"page,default=1"`
-	Size    int    `form:"size,default=20"` // 瀑布流通常每页多一点
+	ThemeID string   `form:"theme_id"`         // 场景：查看某主题下的瀑布流
+	Status  int      `form:"status,default=1"` // 场景：前端默认只展示已过审(1)的照片
+	Page    int      `form:"page,default=1"`
+	Size    int      `form:"size,default=20"` // 瀑布流通常每页多一点
+	Fields  []string `form:"-"`               // 字段投影，由控制器按白名单解析 ?fields=
 }
@@ -28,7 +28,7 @@ type POI struct {
 	UpdatedAt string   `json:"updated_at"`        // 业务更新时间
 
 	// [Audit Fix] 扩展字段：仅用于返回给前端，不存库
-	Distance float64 `json:"_distance,omitempty"` // 距离(米)
+	Distance float64 `json:"_distance,omitempty" schema:"-"` // 距离(米)
 }
 
 // POIQuery 列表筛选参数
@@ -40,6 +40,7 @@ type POIQuery struct {
 	UserLat float64 `form:"lat"` // 用户纬度
 	UserLng float64 `form:"lng"` // 用户经度
 
-	Page int `form:"page,default=1"`
-	Size int `form:"size,default=10"`
+	Page   int      `form:"page,default=1"`
+	Size   int      `form:"size,default=10"`
+	Fields []string `form:"-"` // 字段投影，由控制器按白名单解析 ?fields=
 }
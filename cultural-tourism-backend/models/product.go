@@ -11,12 +11,14 @@ type Product struct {
 	Price     float64 `json:"price"`             // 商品价格 (仅展示，无支付)
 	JumpAppID string  `json:"jump_app_id"`       // 跳转小程序 AppID
 	JumpPath  string  `json:"jump_path"`         // 跳转路径
+	Status    int     `json:"status"`            // 1: 上架, 0: 下架
 	CreatedAt string  `json:"created_at"`        // 业务创建时间
 	UpdatedAt string  `json:"updated_at"`        // 业务更新时间
 }
 
 // ProductQuery 商品列表筛选参数
 type ProductQuery struct {
-	Page int `form:"page,default=1"`
-	Size int `form:"size,default=10"`
+	Page   int      `form:"page,default=1"`
+	Size   int      `form:"size,default=10"`
+	Fields []string `form:"-"` // 字段投影，由控制器按白名单解析 ?fields=
 }
@@ -18,8 +18,9 @@ type Theme struct {
 
 // ThemeQuery 主题列表筛选参数
 type ThemeQuery struct {
-	RegionID string `form:"region_id"`        // 核心筛选：按区域
-	Status   int    `form:"status,default=1"` // 默认只查启用
-	Page     int    `form:"page,default=1"`
-	Size     int    `form:"size,default=10"`
+	RegionID string   `form:"region_id"`        // 核心筛选：按区域
+	Status   int      `form:"status,default=1"` // 默认只查启用
+	Page     int      `form:"page,default=1"`
+	Size     int      `form:"size,default=10"`
+	Fields   []string `form:"-"` // 字段投影，由控制器按白名单解析 ?fields=
 }
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"strings"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerAdminRoutes 管理端接口 (/api/admin)，按权限点分组，角色与权限见 auth.Roles
+func registerAdminRoutes(admin *gin.RouterGroup) {
+	admin.GET("/me", controllers.GetAdminProfile) // 当前用户的角色与权限
+
+	// 区域 / 点位 / 主题 (受区域限制的管理员只能操作负责区域，由 services 校验)
+	content := admin.Group("", auth.RequirePermission(auth.PermManageContent))
+	{
+		content.GET("/pois", controllers.GetManagedPOIList)     // 含未上线点位
+		content.GET("/themes", controllers.GetManagedThemeList) // 按 status 筛选
+		registerContentWrites(content)
+	}
+
+	// 商品导流
+	registerProductWrites(admin.Group("", auth.RequirePermission(auth.PermManageProducts)))
+
+	// UGC 审核：列表可按 status 查待审内容，PUT 修改 status，DELETE 删除违规内容
+	// 照片经 theme.region_id、评论经 poi.region_id 归属区域
+	moderation := admin.Group("", auth.RequirePermission(auth.PermModerate))
+	{
+		moderation.GET("/photos", controllers.GetPhotoList)
+		moderation.PUT("/photos/:id", controllers.UpdatePhoto)
+		moderation.DELETE("/photos/:id", controllers.DeletePhoto)
+
+		moderation.GET("/comments", controllers.GetCommentList)
+		moderation.PUT("/comments/:id", controllers.UpdateComment)
+		moderation.DELETE("/comments/:id", controllers.DeleteComment)
+	}
+
+	// 旅拍机设备凭证：签发 / 轮换时返回密钥，列表不含密钥
+	devices := admin.Group("/devices", auth.RequirePermission(auth.PermManageDevices))
+	{
+		devices.GET("", controllers.ListDevices)
+		devices.POST("", controllers.IssueDevice)
+		devices.POST("/:id/rotate", controllers.RotateDeviceSecret)
+		devices.DELETE("/:id", controllers.RevokeDevice)
+	}
+
+	// 角色分配
+	roles := admin.Group("/roles", auth.RequirePermission(auth.PermManageRoles))
+	{
+		roles.GET("", controllers.ListAdmins)
+		roles.PUT("/:openid", controllers.SetAdminRoles)
+		roles.DELETE("/:openid", controllers.RevokeAdmin)
+	}
+}
+
+// registerLegacyRoutes 兼容旧版管理端：区域 / 点位 / 主题 / 商品的增删改已迁至 /api/admin，
+// 旧路径沿用相同的鉴权 (匿名 401，无权限 403) 并在响应头标明弃用，管理端切换完成后移除
+func registerLegacyRoutes(api *gin.RouterGroup) {
+	legacy := api.Group("", deprecated(), auth.RequireLogin())
+	registerContentWrites(legacy.Group("", auth.RequirePermission(auth.PermManageContent)))
+	registerProductWrites(legacy.Group("", auth.RequirePermission(auth.PermManageProducts)))
+}
+
+// registerContentWrites 区域 / 点位 / 主题的增删改
+func registerContentWrites(content *gin.RouterGroup) {
+	content.POST("/regions", controllers.CreateRegion)
+	content.PUT("/regions/:id", controllers.UpdateRegion)
+	content.DELETE("/regions/:id", controllers.DeleteRegion)
+
+	content.POST("/pois", controllers.CreatePOI)
+	content.PUT("/pois/:id", controllers.UpdatePOI)
+	content.DELETE("/pois/:id", controllers.DeletePOI)
+
+	content.POST("/themes", controllers.CreateTheme)
+	content.PUT("/themes/:id", controllers.UpdateTheme)
+	content.DELETE("/themes/:id", controllers.DeleteTheme)
+}
+
+// registerProductWrites 商品的增删改
+func registerProductWrites(products *gin.RouterGroup) {
+	products.POST("/products", controllers.CreateProduct)
+	products.PUT("/products/:id", controllers.UpdateProduct)
+	products.DELETE("/products/:id", controllers.DeleteProduct)
+}
+
+// deprecated 标记已弃用的旧路径，Link 头指向 /api/admin 下的新路径
+func deprecated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+strings.Replace(c.Request.URL.Path, "/api/", "/api/admin/", 1)+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"cultural-tourism-backend/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsMiddleware 按 cors 配置返回跨域响应头
+// allow_origins 含 "*" 时放行任意来源；否则仅回显白名单内的 Origin
+func corsMiddleware(cfg config.CORS) gin.HandlerFunc {
+	anyOrigin := slices.Contains(cfg.AllowOrigins, "*")
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else if origin := c.GetHeader("Origin"); slices.Contains(cfg.AllowOrigins, origin) {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+		}
+		h.Set("Access-Control-Allow-Methods", methods)
+		h.Set("Access-Control-Allow-Headers", headers)
+		h.Set("Access-Control-Expose-Headers", "ETag") // 条件更新需要读取详情接口的 ETag
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
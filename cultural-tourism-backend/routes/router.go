@@ -2,6 +2,8 @@
 package routes
 
 import (
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/config"
 	"cultural-tourism-backend/controllers"
 
 	"github.com/gin-gonic/gin"
@@ -10,82 +12,75 @@ import (
 )
 
 func RegisterRoutes(r *gin.Engine) {
-	// CORS 配置
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	// CORS 配置 (cors.allow_origins 等，见 config)
+	r.Use(corsMiddleware(config.AppConfig.CORS))
 
 	api := r.Group("/api")
+	api.Use(auth.Middleware())             // 校验登录令牌，openid 写入请求 context
+	api.Use(controllers.ResolveFileURLs()) // 响应中的 cloud:// fileID 替换为临时链接
 	{
-		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		if config.AppConfig.Features.Swagger {
+			r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		}
 		// ==============================
 		// Regions 区域管理 (标准 REST API)
+		// 公开接口只读；区域 / 点位 / 主题 / 商品的增删改见 routes/admin.go (旧路径暂作兼容保留)
 		// ==============================
 
-		// 1. 创建 (Create)
-		api.POST("/regions", controllers.CreateRegion)
-
-		// 2. 列表查询 (Read List)
+		// 1. 列表查询 (Read List)
 		api.GET("/regions", controllers.GetRegions)
 
-		// 3. 单条详情 (Read Detail) - 新增
+		// 2. 单条详情 (Read Detail)
 		api.GET("/regions/:id", controllers.GetRegionDetail)
 
-		// 4. 更新 (Update) - 新增
-		// 使用 PUT 用于全量/部分更新
-		api.PUT("/regions/:id", controllers.UpdateRegion)
-
-		// 5. 删除 (Delete)
-		api.DELETE("/regions/:id", controllers.DeleteRegion)
-
 		// === Phase 3: POI (点位管理) ===
-		api.POST("/pois", controllers.CreatePOI)       // 创建
-		api.GET("/pois", controllers.GetPOIList)       // 列表 (支持 region_id, type 筛选)
-		api.GET("/pois/:id", controllers.GetPOI)       // 详情
-		api.PUT("/pois/:id", controllers.UpdatePOI)    // 更新
-		api.DELETE("/pois/:id", controllers.DeletePOI) // 删除
+		api.GET("/pois", controllers.GetPOIList) // 列表 (支持 region_id, type 筛选)
+		api.GET("/pois/:id", controllers.GetPOI) // 详情
 
 		// ================= Phase 4: UGC 旅拍主题 (Themes) =================
-		api.POST("/themes", controllers.CreateTheme)       // 创建主题
 		api.GET("/themes", controllers.GetThemeList)       // 列表 (支持 ?region_id=...)
 		api.GET("/themes/:id", controllers.GetThemeDetail) // 详情
-		api.PUT("/themes/:id", controllers.UpdateTheme)    // 更新
-		api.DELETE("/themes/:id", controllers.DeleteTheme) // 删除
 
 		// ================= Phase 4 (Part 2): UGC 照片管理 (Photos) =================
-		api.POST("/photos", controllers.CreatePhoto)       // 上传 (默认待审)
-		api.GET("/photos", controllers.GetPhotoList)       // 瀑布流 (默认查已过审)
-		api.GET("/photos/:id", controllers.GetPhotoDetail) // 详情
-		api.PUT("/photos/:id", controllers.UpdatePhoto)    // 审核/点赞
-		api.DELETE("/photos/:id", controllers.DeletePhoto) // 删除
+		api.POST("/photos", auth.RequireLogin(), controllers.CreatePhoto)        // 上传 (默认待审)
+		api.GET("/photos", controllers.GetPhotoList)                             // 瀑布流 (默认查已过审)
+		api.GET("/photos/:id", controllers.GetPhotoDetail)                       // 详情
+		api.PUT("/photos/:id", auth.RequireLogin(), controllers.UpdatePhoto)     // 发布者修改
+		api.DELETE("/photos/:id", auth.RequireLogin(), controllers.DeletePhoto)  // 发布者删除
+		api.POST("/photos/:id/like", auth.RequireLogin(), controllers.LikePhoto) // 点赞 (每人一次，原子自增)
 
 		// ================= Phase 5: 评论互动 (Comments) =================
-		api.POST("/comments", controllers.CreateComment)       // 发布评论
-		api.GET("/comments", controllers.GetCommentList)       // 列表
-		api.GET("/comments/:id", controllers.GetCommentDetail) // 详情
-		api.PUT("/comments/:id", controllers.UpdateComment)    // 审核/点赞
-		api.DELETE("/comments/:id", controllers.DeleteComment) // 删除
+		api.POST("/comments", auth.RequireLogin(), controllers.CreateComment)        // 发布评论
+		api.GET("/comments", controllers.GetCommentList)                             // 列表
+		api.GET("/comments/:id", controllers.GetCommentDetail)                       // 详情
+		api.PUT("/comments/:id", auth.RequireLogin(), controllers.UpdateComment)     // 发布者修改内容
+		api.DELETE("/comments/:id", auth.RequireLogin(), controllers.DeleteComment)  // 发布者删除
+		api.POST("/comments/:id/like", auth.RequireLogin(), controllers.LikeComment) // 点赞 (每人一次，原子自增)
 
 		// ================= Phase 5: 商品导流 (Products) =================
-		api.POST("/products", controllers.CreateProduct)       // 创建
 		api.GET("/products", controllers.GetProductList)       // 列表
 		api.GET("/products/:id", controllers.GetProductDetail) // 详情
-		api.PUT("/products/:id", controllers.UpdateProduct)    // 更新
-		api.DELETE("/products/:id", controllers.DeleteProduct) // 删除
 
 		// ================= Phase 6: 收藏体系 (Favorites) =================
-		api.POST("/favorites", controllers.CreateFavorite)                                    // 收藏资源
-		api.DELETE("/favorites/:resource_type/:resource_id", controllers.DeleteFavorite)      // 取消收藏 (RESTful)
-		api.GET("/favorites", controllers.ListFavorites)                                      // 收藏列表
-		api.GET("/favorites/:resource_type/:resource_id", controllers.CheckFavoriteStatus)    // 检查收藏状态
+		// 收藏按登录用户隔离，全部接口要求登录
+		favorites := api.Group("", auth.RequireLogin())
+		favorites.POST("/favorites", controllers.CreateFavorite)                                 // 收藏资源
+		favorites.DELETE("/favorites/:resource_type/:resource_id", controllers.DeleteFavorite)   // 取消收藏 (RESTful)
+		favorites.GET("/favorites", controllers.ListFavorites)                                   // 收藏列表
+		favorites.GET("/favorites/:resource_type/:resource_id", controllers.CheckFavoriteStatus) // 检查收藏状态
+
+		// ================= 小程序登录 (Auth) =================
+		api.POST("/auth/wx-login", controllers.WxLogin) // code 换取登录令牌
+
+		// ================= 云存储 (Files) =================
+		api.POST("/files", auth.RequireLogin(), controllers.UploadFile) // 上传图片，返回 fileID (资源目录需管理权限)
 	}
-}
 
+	registerAdminRoutes(api.Group("/admin", auth.RequireLogin()))
+	registerLegacyRoutes(api)
 
+	// ================= Phase 6: 旅拍机设备接口 (Device) =================
+	// 不使用微信登录，按设备密钥对请求签名 (auth.Sign)，调用方旅拍机点位写入请求 context
+	device := api.Group("/device", auth.RequireDevice())
+	device.GET("/booth", controllers.GetBoothProfile) // 当前旅拍机点位
+}
@@ -0,0 +1,134 @@
+// Package schemacheck 检查 Go 模型、查询代码与 CloudBase 数据模型定义 (model-json) 是否一致
+//
+//   - 模型结构体的 json 标签必须在数据模型中存在，类型与枚举一致，必填字段有对应字段；
+//   - services / controllers 中 where 用到的字段必须标记 x-filter，orderBy 用到的必须标记 x-sort，
+//     否则线上会全表扫描 (见 code_reviwe.md)。
+//
+// 命令行见 cmd/schemacheck；服务启动时按 SCHEMA_CHECK 执行 (见 main.go)。
+package schemacheck
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+)
+
+// Kind 问题类型
+type Kind string
+
+const (
+	KindUnknownModel     Kind = "unknown-model"     // 集合在 model-json 中不存在
+	KindMissingField     Kind = "missing-field"     // 结构体字段在数据模型中不存在
+	KindTypeMismatch     Kind = "type-mismatch"     // 类型不一致
+	KindEnumMismatch     Kind = "enum-mismatch"     // 枚举不一致
+	KindRequiredUnmapped Kind = "required-unmapped" // 必填字段没有对应的结构体字段
+	KindFilterNotIndexed Kind = "filter-not-indexed"
+	KindSortNotIndexed   Kind = "sort-not-indexed"
+)
+
+// Issue 一条检查结果
+type Issue struct {
+	Kind    Kind   `json:"kind"`
+	Model   string `json:"model"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Pos     string `json:"pos,omitempty"` // 源码位置 (仅查询字段检查)
+}
+
+func (i Issue) String() string {
+	s := fmt.Sprintf("[%s] %s", i.Kind, i.Message)
+	if i.Pos != "" {
+		s += " (" + i.Pos + ")"
+	}
+	return s
+}
+
+// Options 检查范围
+type Options struct {
+	Schemas    fs.FS    // model-json 目录
+	SourceDirs []string // 需要扫描查询字段的源码目录，为空时跳过该项检查
+}
+
+// Report 检查报告
+type Report struct {
+	Issues     []Issue    `json:"issues"`
+	Unresolved []FieldUse `json:"unresolved,omitempty"` // 无法确定所属集合的查询字段 (未检查)
+}
+
+// Check 执行全部检查
+func Check(opts Options) (*Report, error) {
+	schemas, err := LoadSchemas(opts.Schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, b := range Bindings {
+		schema, ok := schemas[b.Schema]
+		if !ok {
+			report.Issues = append(report.Issues, Issue{Kind: KindUnknownModel, Model: b.Schema, Message: fmt.Sprintf("数据模型 %s 不存在", b.Schema)})
+			continue
+		}
+		report.Issues = append(report.Issues, checkModel(b, schema)...)
+	}
+
+	if len(opts.SourceDirs) > 0 {
+		uses, unresolved, err := ScanQueries(opts.SourceDirs...)
+		if err != nil {
+			return nil, err
+		}
+		report.Issues = append(report.Issues, checkQueries(uses, schemas)...)
+		report.Unresolved = unresolved
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool { return report.Issues[i].Model < report.Issues[j].Model })
+	return report, nil
+}
+
+// checkQueries where / orderBy 字段必须有索引标记；同一位置的同一问题只报一次
+func checkQueries(uses []FieldUse, schemas map[string]*Schema) []Issue {
+	var issues []Issue
+	seen := make(map[string]bool)
+	for _, u := range uses {
+		schema, ok := schemas[u.Collection]
+		var issue Issue
+		switch {
+		case !ok:
+			issue = Issue{Kind: KindUnknownModel, Model: u.Collection, Message: fmt.Sprintf("查询的集合 %s 在 model-json 中不存在", u.Collection)}
+		case u.Sort && !schema.sortable(u.Field):
+			issue = Issue{Kind: KindSortNotIndexed, Model: u.Collection, Field: u.Field, Message: fmt.Sprintf("%s.%s 用于 orderBy 但未标记 x-sort%s", u.Collection, u.Field, undefined(schema, u.Field))}
+		case !u.Sort && !schema.filterable(u.Field):
+			issue = Issue{Kind: KindFilterNotIndexed, Model: u.Collection, Field: u.Field, Message: fmt.Sprintf("%s.%s 用于 where 但未标记 x-filter%s", u.Collection, u.Field, undefined(schema, u.Field))}
+		default:
+			continue
+		}
+		key := issue.String()
+		if issue.Kind != KindUnknownModel {
+			key += u.Pos // 未知集合只报一次
+		}
+		issue.Pos = u.Pos
+		if !seen[key] {
+			seen[key] = true
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func undefined(schema *Schema, field string) string {
+	if _, ok := schema.Properties[field]; !ok {
+		return " (字段不存在)"
+	}
+	return ""
+}
+
+// Print 输出可读报告
+func (r *Report) Print(w io.Writer) {
+	for _, issue := range r.Issues {
+		fmt.Fprintln(w, issue)
+	}
+	for _, u := range r.Unresolved {
+		fmt.Fprintf(w, "[skipped] 无法确定 %s 所属集合，未检查 (%s)\n", u.Field, u.Pos)
+	}
+}
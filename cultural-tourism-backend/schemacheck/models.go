@@ -0,0 +1,114 @@
+package schemacheck
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cultural-tourism-backend/models"
+)
+
+// Binding Go 模型与数据模型的对应关系
+type Binding struct {
+	Schema string              // 模型标识
+	Model  interface{}         // 结构体零值
+	Enums  map[string][]string // Go 侧枚举 (字段 -> 取值)，与 schema 的 enum 比对
+}
+
+// Bindings 需要检查的模型
+// 结构体字段带 schema:"-" 标签表示不入库 (如计算字段)，不参与检查
+var Bindings = []Binding{
+	{Schema: "regions", Model: models.Region{}},
+	{Schema: "pois", Model: models.POI{}, Enums: map[string][]string{
+		"type": {models.POITypeScenic, models.POITypeFood, models.POITypeHotel, models.POITypeBooth},
+	}},
+	{Schema: "themes", Model: models.Theme{}},
+	{Schema: "photo", Model: models.Photo{}},
+	{Schema: "comment", Model: models.Comment{}},
+	{Schema: "product", Model: models.Product{}},
+	{Schema: "favorites", Model: models.Favorite{}, Enums: map[string][]string{
+		"resource_type": {"theme", "poi", "product"},
+	}},
+	{Schema: "admins", Model: models.Admin{}},
+	{Schema: "devices", Model: models.Device{}},
+	{Schema: "device_nonces", Model: models.DeviceNonce{}},
+	{Schema: "likes", Model: models.Like{}, Enums: map[string][]string{
+		"target_type": {models.LikeTargetPhoto, models.LikeTargetComment},
+	}},
+}
+
+// checkModel 比对结构体 json 标签、类型与枚举
+func checkModel(b Binding, schema *Schema) []Issue {
+	var issues []Issue
+	report := func(kind Kind, field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Kind: kind, Model: b.Schema, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	t := reflect.TypeOf(b.Model)
+	mapped := make(map[string]bool)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+		// _id 为主键，由平台维护 (部分导出文件不包含其定义)
+		if !f.IsExported() || name == "-" || name == "_id" || f.Tag.Get("schema") == "-" {
+			continue
+		}
+		mapped[name] = true
+
+		prop, ok := schema.Properties[name]
+		if !ok {
+			report(KindMissingField, name, "%s.%s 在数据模型中不存在", t.Name(), f.Name)
+			continue
+		}
+		if want := jsonType(f.Type); want != prop.Type {
+			report(KindTypeMismatch, name, "%s.%s 为 %s，数据模型为 %s", t.Name(), f.Name, want, prop.Type)
+		} else if want == "array" && prop.Items != nil {
+			if elem := jsonType(f.Type.Elem()); elem != prop.Items.Type {
+				report(KindTypeMismatch, name, "%s.%s 元素为 %s，数据模型为 %s", t.Name(), f.Name, elem, prop.Items.Type)
+			}
+		}
+
+		if values, ok := b.Enums[name]; ok || len(prop.Enum) > 0 {
+			if !sameSet(values, prop.Enum) {
+				report(KindEnumMismatch, name, "%s.%s 枚举 %v，数据模型为 %v", t.Name(), f.Name, values, prop.Enum)
+			}
+		}
+	}
+
+	for _, name := range schema.Required {
+		if !mapped[name] {
+			report(KindRequiredUnmapped, name, "必填字段 %s 在 %s 中没有对应字段，新增记录会被拒绝", name, t.Name())
+		}
+	}
+	return issues
+}
+
+// jsonType Go 类型对应的 JSON Schema 类型
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	}
+	return "object"
+}
+
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
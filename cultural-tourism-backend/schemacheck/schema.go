@@ -0,0 +1,94 @@
+package schemacheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+)
+
+// Schema 一个 CloudBase 数据模型 (model-json/*.json 中与检查相关的部分)
+type Schema struct {
+	Name       string              // 模型标识 (集合名)
+	File       string              // 来源文件
+	Required   []string            //
+	Properties map[string]Property //
+}
+
+// Property 字段定义
+type Property struct {
+	Type   string    `json:"type"`
+	Format string    `json:"format"`
+	Enum   []string  `json:"enum"`
+	Items  *Property `json:"items"`
+	Filter bool      `json:"x-filter"` // 可用于 where (有索引)
+	Sort   bool      `json:"x-sort"`   // 可用于 orderBy
+	System bool      `json:"x-system"` // 系统字段 (_id、_openid 等)
+	Unique bool      `json:"x-unique"` // 取值唯一 (网关拒绝重复写入)
+}
+
+// LoadSchemas 读取目录下全部 *.json 模型定义，按模型标识索引
+func LoadSchemas(fsys fs.FS) (map[string]*Schema, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]*Schema, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var doc struct {
+			Name   string `json:"name"`
+			Schema struct {
+				Required   []string            `json:"required"`
+				Properties map[string]Property `json:"properties"`
+			} `json:"schema"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if doc.Name == "" {
+			return nil, fmt.Errorf("%s: 缺少模型标识 name", file)
+		}
+		if prev, ok := schemas[doc.Name]; ok {
+			return nil, fmt.Errorf("%s: 模型 %s 与 %s 重复", file, doc.Name, prev.File)
+		}
+		schemas[doc.Name] = &Schema{
+			Name:       doc.Name,
+			File:       path.Base(file),
+			Required:   doc.Schema.Required,
+			Properties: doc.Schema.Properties,
+		}
+	}
+	return schemas, nil
+}
+
+// UniqueFields 模型标识 -> 声明了 x-unique 的业务字段 (不含系统字段)，供 store/local 施加同样的唯一约束
+func UniqueFields(fsys fs.FS) (map[string][]string, error) {
+	schemas, err := LoadSchemas(fsys)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for name, s := range schemas {
+		for field, p := range s.Properties {
+			if p.Unique && !p.System {
+				out[name] = append(out[name], field)
+			}
+		}
+		slices.Sort(out[name])
+	}
+	return out, nil
+}
+
+// filterable _id 为主键，总是可以筛选和排序
+func (s *Schema) filterable(field string) bool {
+	return field == "_id" || s.Properties[field].Filter
+}
+
+func (s *Schema) sortable(field string) bool {
+	return field == "_id" || s.Properties[field].Sort
+}
@@ -0,0 +1,106 @@
+package schemacheck
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+const shopsSchema = `{
+	"name": "shops",
+	"schema": {
+		"required": ["name", "license"],
+		"properties": {
+			"_id":    {"type": "string", "x-system": true},
+			"name":   {"type": "string", "x-filter": true},
+			"status": {"type": "number", "x-filter": true},
+			"rating": {"type": "number", "x-sort": true},
+			"city":   {"type": "string"},
+			"tags":   {"type": "array", "items": {"type": "number"}},
+			"kind":   {"type": "string", "enum": ["cafe", "bar"]}
+		}
+	}
+}`
+
+type shop struct {
+	ID       string   `json:"_id,omitempty"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags"`
+	Kind     string   `json:"kind"`
+	Phone    string   `json:"phone"`
+	Distance float64  `json:"_distance" schema:"-"`
+}
+
+func kinds(issues []Issue) []string {
+	var out []string
+	for _, i := range issues {
+		out = append(out, string(i.Kind)+" "+i.Field)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestCheckModel(t *testing.T) {
+	schemas, err := LoadSchemas(fstest.MapFS{"shops_model.json": {Data: []byte(shopsSchema)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkModel(Binding{Schema: "shops", Model: shop{}, Enums: map[string][]string{"kind": {"cafe", "bar", "tea"}}}, schemas["shops"])
+	want := []string{
+		"enum-mismatch kind",
+		"missing-field phone",
+		"required-unmapped license",
+		"type-mismatch status",
+		"type-mismatch tags",
+	}
+	if got := kinds(issues); !slices.Equal(got, want) {
+		t.Errorf("issues = %v\nwant %v", got, want)
+	}
+}
+
+func TestScanQueries(t *testing.T) {
+	uses, unresolved, err := ScanQueries("testdata/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, u := range uses {
+		kind := "where"
+		if u.Sort {
+			kind = "sort"
+		}
+		got = append(got, u.Collection+"."+u.Field+" "+kind)
+	}
+	want := []string{"shops.status where", "shops.city where", "shops.rating sort", "shops.location where", "photo.theme_id where"}
+	if !slices.Equal(got, want) {
+		t.Errorf("uses = %v\nwant %v", got, want)
+	}
+	// Mixed 同时引用两个集合，无法确定归属
+	if len(unresolved) != 1 || unresolved[0].Field != "owner" {
+		t.Errorf("unresolved = %+v", unresolved)
+	}
+
+	schemas, _ := LoadSchemas(fstest.MapFS{
+		"shops_model.json": {Data: []byte(shopsSchema)},
+		"photo_model.json": {Data: []byte(`{"name": "photo", "schema": {"properties": {"theme_id": {"type": "string", "x-filter": true}}}}`)},
+	})
+	want = []string{"filter-not-indexed city", "filter-not-indexed location"}
+	if got := kinds(checkQueries(uses, schemas)); !slices.Equal(got, want) {
+		t.Errorf("query issues = %v\nwant %v", got, want)
+	}
+}
+
+// TestRepositoryModels 仓库自身的模型与查询必须与 model-json 一致 (与 go run ./cmd/schemacheck 相同)
+func TestRepositoryModels(t *testing.T) {
+	report, err := Check(Options{Schemas: os.DirFS("../model-json"), SourceDirs: []string{"../services", "../controllers"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range report.Issues {
+		t.Error(issue)
+	}
+}
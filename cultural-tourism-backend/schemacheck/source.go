@@ -0,0 +1,243 @@
+package schemacheck
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cultural-tourism-backend/collection"
+)
+
+// FieldUse 源码中一次 where / orderBy 字段引用
+type FieldUse struct {
+	Collection string `json:"collection,omitempty"` // 集合名 (由所在函数引用的 Collection* 常量推断)
+	Field      string `json:"field"`
+	Sort       bool   `json:"sort"` // true 为 orderBy，false 为 where
+	Pos        string `json:"pos"`  // 文件:行号
+}
+
+// whereOps tcb/query 中以字段名为第一个参数的条件构造函数
+var whereOps = map[string]bool{
+	"Eq": true, "Ne": true, "In": true, "Nin": true, "Gt": true, "Gte": true,
+	"Lt": true, "Lte": true, "Range": true, "Regex": true,
+}
+
+// ScanQueries 扫描目录 (不递归) 中的 Go 源码，收集 tcb/query 构造的 where / orderBy 字段
+//
+// 集合按函数推断：函数内引用的注册表资源 (collection.POIs，取默认集合名) 或 Collection* 常量，
+// 以及调用的 xxxRepo() (其函数体引用的集合)。
+// 只引用一个集合的函数才能确定归属，其余函数中的字段记入 unresolved。
+func ScanQueries(dirs ...string) (uses []FieldUse, unresolved []FieldUse, err error) {
+	fset := token.NewFileSet()
+	type pkg struct {
+		files  []*ast.File
+		consts map[string]string
+	}
+	pkgs := make([]pkg, 0, len(dirs))
+	qualified := make(map[string]string) // 包名.常量 -> 集合名，用于解析 services.CollectionPOI 这类跨包引用
+	for _, dir := range dirs {
+		files, err := parseDir(fset, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		consts := collectionConsts(files)
+		for name, v := range consts {
+			qualified[files[0].Name.Name+"."+name] = v
+		}
+		pkgs = append(pkgs, pkg{files: files, consts: consts})
+	}
+
+	for _, p := range pkgs {
+		refs := resolver{local: p.consts, qualified: qualified, repos: make(map[string]string)}
+		for _, file := range p.files {
+			for _, fn := range funcs(file) {
+				if cols := refs.collections(fn); len(cols) == 1 && strings.HasSuffix(fn.Name.Name, "Repo") {
+					refs.repos[fn.Name.Name] = cols[0]
+				}
+			}
+		}
+
+		for _, file := range p.files {
+			for _, fn := range funcs(file) {
+				fields := queryFields(fset, fn)
+				if len(fields) == 0 {
+					continue
+				}
+				cols := refs.collections(fn)
+				for _, u := range fields {
+					if len(cols) == 1 {
+						u.Collection = cols[0]
+						uses = append(uses, u)
+					} else {
+						unresolved = append(unresolved, u)
+					}
+				}
+			}
+		}
+	}
+	return uses, unresolved, nil
+}
+
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	var files []*ast.File
+	entries, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range entries {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, &fs.PathError{Op: "scan", Path: dir, Err: fs.ErrNotExist}
+	}
+	return files, nil
+}
+
+func funcs(file *ast.File) []*ast.FuncDecl {
+	var out []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			out = append(out, fn)
+		}
+	}
+	return out
+}
+
+// collectionConsts 包内 Collection* 字符串常量
+func collectionConsts(files []*ast.File) map[string]string {
+	consts := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if !strings.HasPrefix(name.Name, "Collection") || i >= len(vs.Values) {
+						continue
+					}
+					if v, ok := stringLit(vs.Values[i]); ok {
+						consts[name.Name] = v
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// resolver 解析函数体引用的集合
+type resolver struct {
+	local     map[string]string // 本包 Collection* 常量
+	qualified map[string]string // 包名.Collection* 常量
+	repos     map[string]string // 本包 xxxRepo() -> 集合名
+}
+
+// collections 函数体引用的集合 (去重，按出现顺序)
+func (r resolver) collections(fn *ast.FuncDecl) []string {
+	var cols []string
+	add := func(c string) {
+		if !slices.Contains(cols, c) {
+			cols = append(cols, c)
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			pkg, ok := x.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if c, ok := r.qualified[pkg.Name+"."+x.Sel.Name]; ok {
+				add(c)
+			} else if pkg.Name == "collection" {
+				if c, ok := collection.Default(collection.Resource(strings.ToLower(x.Sel.Name))); ok {
+					add(c)
+				}
+			}
+			return false // 不把 pkg.CollectionX 的 Sel 当作本包常量
+		case *ast.Ident:
+			if c, ok := r.local[x.Name]; ok {
+				add(c)
+			}
+		case *ast.CallExpr:
+			if id, ok := x.Fun.(*ast.Ident); ok {
+				if c, ok := r.repos[id.Name]; ok {
+					add(c)
+				}
+			}
+		}
+		return true
+	})
+	return cols
+}
+
+// queryFields 函数体中 query.Eq("field", ...) / query.Cond{"field": ...} / .OrderBy("field", ...) 的字段
+func queryFields(fset *token.FileSet, fn *ast.FuncDecl) []FieldUse {
+	var uses []FieldUse
+	add := func(field string, sort bool, pos token.Pos) {
+		p := fset.Position(pos)
+		uses = append(uses, FieldUse{Field: field, Sort: sort, Pos: p.Filename + ":" + strconv.Itoa(p.Line)})
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok || len(x.Args) == 0 {
+				return true
+			}
+			field, ok := stringLit(x.Args[0])
+			if !ok {
+				return true
+			}
+			switch {
+			case sel.Sel.Name == "OrderBy":
+				add(field, true, x.Pos())
+			case whereOps[sel.Sel.Name] && isIdent(sel.X, "query"):
+				add(field, false, x.Pos())
+			}
+		case *ast.CompositeLit:
+			sel, ok := x.Type.(*ast.SelectorExpr)
+			if !ok || !isIdent(sel.X, "query") || sel.Sel.Name != "Cond" {
+				return true
+			}
+			for _, elt := range x.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if field, ok := stringLit(kv.Key); ok {
+						add(field, false, kv.Pos())
+					}
+				}
+			}
+		}
+		return true
+	})
+	return uses
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
@@ -0,0 +1,30 @@
+package shop
+
+const (
+	CollectionShop  = "shops"
+	CollectionStaff = "staff"
+)
+
+func shopRepo() *Repo { return NewRepo(CollectionShop) }
+
+func ListShops(city string) {
+	qb := query.New(query.Eq("status", 1), query.Eq("city", city))
+	qb.OrderBy("rating", query.Desc)
+	shopRepo().List(qb.Build())
+}
+
+func Nearby() {
+	near := query.Cond{"location": nil}
+	shopRepo().List(query.New(near).Build())
+}
+
+func Mixed() {
+	other.List(CollectionShop, query.New(query.Eq("owner", "x")).Build())
+	other.List(CollectionStaff, nil)
+}
+
+func staffRepo() *Repo { return NewRepo(collection.Photos.Name()) }
+
+func ListStaff() {
+	staffRepo().List(query.New(query.Eq("theme_id", "t")).Build())
+}
@@ -0,0 +1,154 @@
+// File: services/admin_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// 管理员业务错误 (Controller 通过 errors.Is 判断)
+var (
+	ErrInvalidRole   = errors.New("未知的管理员角色")
+	ErrInvalidRegion = errors.New("负责区域不存在")
+	ErrSelfDemotion  = errors.New("不能移除自己的超级管理员角色")
+	ErrAdminNotFound = fmt.Errorf("admin not found: %w", tcb.ErrNotFound)
+)
+
+func adminRepo() *tcb.Repository[models.Admin] {
+	return tcb.NewRepository[models.Admin](store.Default, collection.Admins.Name())
+}
+
+// AdminRoleSource 从 admins 集合读取角色，供 auth.RoleStore 使用
+type AdminRoleSource struct{}
+
+// GrantOf 实现 auth.RoleSource；未分配角色的用户返回零值，未知角色忽略
+func (AdminRoleSource) GrantOf(ctx context.Context, openID string) (auth.Grant, error) {
+	admin, err := findAdmin(ctx, openID)
+	if errors.Is(err, ErrAdminNotFound) {
+		return auth.Grant{}, nil
+	}
+	if err != nil {
+		return auth.Grant{}, err
+	}
+
+	grant := auth.Grant{Roles: make([]auth.Role, 0, len(admin.Roles)), RegionIDs: admin.RegionIDs}
+	for _, r := range admin.Roles {
+		if role := auth.Role(r); role.Valid() {
+			grant.Roles = append(grant.Roles, role)
+		}
+	}
+	return grant, nil
+}
+
+func findAdmin(ctx context.Context, openID string) (*models.Admin, error) {
+	result, err := adminRepo().List(ctx, query.New(query.Eq("openid", openID)).Build(), 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, ErrAdminNotFound
+	}
+	return &result.Items[0], nil
+}
+
+// ListAdmins 已分配角色的用户列表 (按创建时间倒序)
+func ListAdmins(ctx context.Context, page, size int) (*tcb.Page[models.Admin], error) {
+	filter := query.New().OrderBy("created_at", query.Desc).Build()
+	return adminRepo().List(ctx, filter, page, size)
+}
+
+// CurrentAdmin 当前登录用户的角色与权限
+func CurrentAdmin(ctx context.Context) (*models.AdminProfile, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return nil, auth.ErrUnauthenticated
+	}
+	grant, err := auth.GrantFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.AdminProfile{OpenID: openID, Roles: []string{}, RegionIDs: []string{}, Permissions: []string{}}
+	for _, r := range grant.Roles {
+		profile.Roles = append(profile.Roles, string(r))
+	}
+	if scope := grant.Scope(); !scope.All() {
+		profile.RegionIDs = scope.RegionIDs
+	}
+	for _, p := range auth.Permissions(grant.Roles) {
+		profile.Permissions = append(profile.Permissions, string(p))
+	}
+	return profile, nil
+}
+
+// SetAdminRoles 设置用户角色与负责区域 (整体替换，不存在时创建)
+// 超级管理员不能移除自己的 super_admin，避免误操作后无人可分配角色
+func SetAdminRoles(ctx context.Context, openID string, req models.AdminRolesRequest) (*models.Admin, error) {
+	for _, r := range req.Roles {
+		if !auth.Role(r).Valid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRole, r)
+		}
+	}
+	if self, _ := auth.OpenIDFrom(ctx); self == openID && !slices.Contains(req.Roles, string(auth.RoleSuperAdmin)) {
+		return nil, ErrSelfDemotion
+	}
+
+	regionIDs := slices.Compact(slices.Sorted(slices.Values(req.RegionIDs)))
+	for _, id := range regionIDs {
+		if _, err := regionRepo().Get(ctx, id, "_id"); err != nil {
+			if errors.Is(err, tcb.ErrNotFound) {
+				err = fmt.Errorf("%w: %s", ErrInvalidRegion, id)
+			}
+			return nil, err
+		}
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	roles := slices.Compact(slices.Sorted(slices.Values(req.Roles)))
+
+	current, err := findAdmin(ctx, openID)
+	if errors.Is(err, ErrAdminNotFound) {
+		admin := models.Admin{OpenID: openID, Roles: roles, RegionIDs: regionIDs, Remark: req.Remark, CreatedAt: now, UpdatedAt: now}
+		admin.ID, err = adminRepo().Create(ctx, admin)
+		if err != nil {
+			return nil, err
+		}
+		return &admin, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := map[string]interface{}{"roles": roles, "region_ids": regionIDs, "updated_at": now}
+	current.Roles, current.RegionIDs, current.UpdatedAt = roles, regionIDs, now
+	if req.Remark != "" {
+		updateData["remark"] = req.Remark
+		current.Remark = req.Remark
+	}
+	if err := adminRepo().Update(ctx, current.ID, updateData); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// RevokeAdmin 移除用户的全部角色
+func RevokeAdmin(ctx context.Context, openID string) error {
+	if self, _ := auth.OpenIDFrom(ctx); self == openID {
+		return ErrSelfDemotion
+	}
+	current, err := findAdmin(ctx, openID)
+	if err != nil {
+		return err
+	}
+	return adminRepo().Delete(ctx, current.ID)
+}
@@ -2,66 +2,124 @@
 package services
 
 import (
+	"context"
 	"time"
 
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 )
 
-// [Critical] 数据库实际集合名为单数 "comment"
-const CollectionComment = "comment"
+func commentRepo() *tcb.Repository[models.Comment] {
+	return tcb.NewRepository[models.Comment](store.Default, collection.Comments.Name())
+}
 
-// CreateComment 创建评论（默认待审）
-func CreateComment(comment *models.Comment) (map[string]interface{}, error) {
+// CreateComment 创建评论（默认待审），发布者取自登录态
+func CreateComment(ctx context.Context, comment *models.Comment) (string, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return "", auth.ErrUnauthenticated
+	}
 	comment.ID = ""
+	comment.OpenID = openID
 	comment.Status = 0
 	comment.LikeCount = 0
 	comment.CreatedAt = time.Now().Format(time.RFC3339)
-	comment.UpdatedAt = time.Now().Format(time.RFC3339)
+	comment.UpdatedAt = tcb.Timestamp()
 	if comment.ParentID == "" {
 		comment.ParentID = ""
 	}
 
-	return tcb.Client.CreateData(CollectionComment, comment)
+	return commentRepo().Create(ctx, comment)
 }
 
 // ListComments 获取评论列表
-func ListComments(query models.CommentQuery) (map[string]interface{}, error) {
-	where := make(map[string]interface{})
-	where["status"] = map[string]interface{}{"$eq": query.Status}
-	if query.POIID != "" {
-		where["poi_id"] = map[string]interface{}{"$eq": query.POIID}
+// 待审 / 已拒绝的评论仅审核员可查，受区域限制的审核员只能查看负责区域内点位下的评论
+func ListComments(ctx context.Context, q models.CommentQuery) (*tcb.Page[models.Comment], error) {
+	qb := query.New(query.Eq("status", q.Status))
+	if q.POIID != "" {
+		qb.Where(query.Eq("poi_id", q.POIID))
 	}
 
-	filter := map[string]interface{}{
-		"where": where,
+	if q.Status != 1 {
+		scope, err := auth.ScopeOf(ctx, auth.PermModerate)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case scope.All():
+		case q.POIID != "":
+			if err := auth.AuthorizeIn(ctx, auth.PermModerate, poiRegion(ctx, q.POIID)); err != nil {
+				return nil, err
+			}
+		default:
+			poiIDs, err := poiIDsIn(ctx, scope.RegionIDs)
+			if err != nil {
+				return nil, err
+			}
+			qb.Where(inChunks("poi_id", poiIDs))
+		}
 	}
 
-	return tcb.Client.ListData(CollectionComment, filter, query.Page, query.Size)
+	return commentRepo().List(ctx, qb.Select(q.Fields...).Build(), q.Page, q.Size)
 }
 
 // GetCommentDetail 获取评论详情
-func GetCommentDetail(id string) (map[string]interface{}, error) {
-	return tcb.Client.GetDetail(CollectionComment, id)
+func GetCommentDetail(ctx context.Context, id string) (*models.Comment, error) {
+	return commentRepo().Get(ctx, id)
 }
 
-// UpdateComment 更新评论（审核/点赞）
-func UpdateComment(id string, comment models.Comment) error {
+// UpdateComment 更新评论（审核 / 修改内容）
+// 仅发布者或 (评论所属区域的) 审核员可修改；审核状态只能由审核员修改，发布者修改内容后重新进入待审
+func UpdateComment(ctx context.Context, id string, comment models.Comment, expectedUpdatedAt string) error {
+	current, err := commentRepo().Get(ctx, id, "_openid", "poi_id")
+	if err != nil {
+		return err
+	}
+	region := parentRegion(poiRegion(ctx, current.POIID))
+	if err := auth.RequireOwner(ctx, current.OpenID, auth.PermModerate, region); err != nil {
+		return err
+	}
+	moderator := auth.AuthorizeIn(ctx, auth.PermModerate, region) == nil
+
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
+	if comment.Content != "" {
+		updateData["content"] = comment.Content
+		if !moderator {
+			updateData["status"] = 0
+		}
+	}
 	if comment.Status != 0 {
+		if err := auth.AuthorizeIn(ctx, auth.PermModerate, region); err != nil {
+			return err
+		}
 		updateData["status"] = comment.Status
 	}
-	if comment.LikeCount > 0 {
-		updateData["like_count"] = comment.LikeCount
-	}
 
-	return tcb.Client.UpdateData(CollectionComment, id, updateData)
+	return commentRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
+}
+
+// LikeComment 点赞 (需登录，每个用户只能点赞一次，重复点赞返回 ErrAlreadyLiked)，返回最新点赞数
+// like_count 只能通过原子自增修改，不随更新接口由客户端提交；
+// 点赞不刷新 updated_at，避免打断管理端基于版本的条件更新
+func LikeComment(ctx context.Context, id string) (int, error) {
+	return like(ctx, models.LikeTargetComment, id, commentRepo().Increment)
 }
 
-// DeleteComment 删除评论
-func DeleteComment(id string) error {
-	return tcb.Client.DeleteData(CollectionComment, id)
+// DeleteComment 删除评论 (仅发布者或评论所属区域的审核员)
+func DeleteComment(ctx context.Context, id string) error {
+	current, err := commentRepo().Get(ctx, id, "_openid", "poi_id")
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireOwner(ctx, current.OpenID, auth.PermModerate, parentRegion(poiRegion(ctx, current.POIID))); err != nil {
+		return err
+	}
+	return commentRepo().Delete(ctx, id)
 }
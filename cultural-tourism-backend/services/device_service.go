@@ -0,0 +1,195 @@
+// File: services/device_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// ErrNotBooth 设备只能登记到旅拍机点位
+var ErrNotBooth = errors.New("点位不是旅拍机")
+
+// deviceListFields 设备列表返回的字段 (不含签名密钥)
+var deviceListFields = []string{"_id", "poi_id", "status", "remark", "rotated_at", "created_at", "updated_at"}
+
+func deviceRepo() *tcb.Repository[models.Device] {
+	return tcb.NewRepository[models.Device](store.Default, collection.Devices.Name())
+}
+
+// BoothDeviceSource 从 devices 集合读取设备凭证，供 auth.Devices 使用
+type BoothDeviceSource struct{}
+
+// DeviceOf 实现 auth.DeviceSource；设备不存在 / 已停用，或所属点位已不是旅拍机时返回 auth.ErrUnknownDevice
+func (BoothDeviceSource) DeviceOf(ctx context.Context, deviceID string) (*auth.Device, error) {
+	device, err := deviceRepo().Get(ctx, deviceID, "poi_id", "secret", "status")
+	if errors.Is(err, tcb.ErrNotFound) {
+		return nil, auth.ErrUnknownDevice
+	}
+	if err != nil {
+		return nil, err
+	}
+	if device.Status != 1 || device.Secret == "" {
+		return nil, auth.ErrUnknownDevice
+	}
+	if err := requireBooth(ctx, device.POIID); err != nil {
+		if errors.Is(err, ErrNotBooth) {
+			return nil, auth.ErrUnknownDevice
+		}
+		return nil, err
+	}
+	return &auth.Device{ID: deviceID, POIID: device.POIID, Secret: device.Secret}, nil
+}
+
+// requireBooth 校验点位存在且类型为旅拍机
+func requireBooth(ctx context.Context, poiID string) error {
+	poi, err := poiRepo().Get(ctx, poiID, "type")
+	if errors.Is(err, tcb.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotBooth, poiID)
+	}
+	if err != nil {
+		return err
+	}
+	if poi.Type != models.POITypeBooth {
+		return fmt.Errorf("%w: %s", ErrNotBooth, poiID)
+	}
+	return nil
+}
+
+func deviceNonceRepo() *tcb.Repository[models.DeviceNonce] {
+	return tcb.NewRepository[models.DeviceNonce](store.Default, collection.DeviceNonces.Name())
+}
+
+// nonceSweepInterval 过期 nonce 的清理间隔 (每个实例)
+const nonceSweepInterval = time.Minute
+
+// DeviceNonceStore 在 device_nonces 集合中登记已使用的 nonce，供 auth.Devices 使用
+// 去重依赖 device_nonces.nonce_key 的唯一约束 (x-unique)：任一实例已登记过的 nonce 再次写入失败，即为重放
+type DeviceNonceStore struct {
+	lastSweep atomic.Int64 // 上次清理过期记录的时间 (Unix 秒)
+}
+
+// UseNonce 实现 auth.NonceStore
+func (s *DeviceNonceStore) UseNonce(ctx context.Context, deviceID, nonce string, expiresAt time.Time) error {
+	sum := sha256.Sum256([]byte(deviceID + "\n" + nonce))
+	record := models.DeviceNonce{DeviceID: deviceID, Key: hex.EncodeToString(sum[:]), ExpiresAt: expiresAt.Unix()}
+	s.sweep(ctx)
+
+	_, err := deviceNonceRepo().CreateUnique(ctx, record, "nonce_key", record.Key)
+	if !errors.Is(err, tcb.ErrDuplicateKey) {
+		return err
+	}
+	// 已过期但尚未清理的记录 (设备在窗口期外重复使用了 nonce)：按过期条件删除后重新登记，
+	// 记录仍在有效期内时删除不到任何记录，即为重放；并发重新登记时只有一方能写入成功
+	expired := query.New(query.Eq("nonce_key", record.Key), query.Lte("expires_at", time.Now().Unix())).Build()
+	deleted, err := deviceNonceRepo().DeleteMany(ctx, expired)
+	if err != nil {
+		return err
+	}
+	if deleted.Count == 0 {
+		return auth.ErrNonceReplayed
+	}
+	if _, err := deviceNonceRepo().CreateUnique(ctx, record, "nonce_key", record.Key); err != nil {
+		if errors.Is(err, tcb.ErrDuplicateKey) {
+			return auth.ErrNonceReplayed
+		}
+		return err
+	}
+	return nil
+}
+
+// sweep 每个实例每隔 nonceSweepInterval 删除一次过期记录，失败只记录日志
+func (s *DeviceNonceStore) sweep(ctx context.Context) {
+	now := time.Now().Unix()
+	last := s.lastSweep.Load()
+	if now-last < int64(nonceSweepInterval/time.Second) || !s.lastSweep.CompareAndSwap(last, now) {
+		return
+	}
+	if _, err := deviceNonceRepo().DeleteMany(ctx, query.New(query.Lt("expires_at", now)).Build()); err != nil {
+		log.Printf("⚠️ 清理过期设备 nonce 失败: %v", err)
+	}
+}
+
+// newDeviceSecret 生成 32 字节随机签名密钥 (十六进制)
+func newDeviceSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成设备密钥: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ListDevices 设备列表 (按创建时间倒序，不返回签名密钥)
+func ListDevices(ctx context.Context, q models.DeviceQuery) (*tcb.Page[models.Device], error) {
+	qb := query.New()
+	if q.POIID != "" {
+		qb.Where(query.Eq("poi_id", q.POIID))
+	}
+	filter := qb.OrderBy("created_at", query.Desc).Select(deviceListFields...).Build()
+	return deviceRepo().List(ctx, filter, q.Page, q.Size)
+}
+
+// IssueDevice 为旅拍机点位登记设备并签发凭证，密钥只在返回值中出现一次
+func IssueDevice(ctx context.Context, req models.DeviceRequest) (*models.DeviceCredential, error) {
+	if err := requireBooth(ctx, req.POIID); err != nil {
+		return nil, err
+	}
+	secret, err := newDeviceSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	device := models.Device{POIID: req.POIID, Secret: secret, Status: 1, Remark: req.Remark, RotatedAt: now, CreatedAt: now, UpdatedAt: now}
+	id, err := deviceRepo().Create(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DeviceCredential{DeviceID: id, POIID: req.POIID, Secret: secret}, nil
+}
+
+// RotateDeviceSecret 轮换设备密钥并重新启用设备，旧密钥立即失效
+func RotateDeviceSecret(ctx context.Context, id string) (*models.DeviceCredential, error) {
+	device, err := deviceRepo().Get(ctx, id, "poi_id")
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newDeviceSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	updateData := map[string]interface{}{"secret": secret, "status": 1, "rotated_at": now, "updated_at": now}
+	if err := deviceRepo().Update(ctx, id, updateData); err != nil {
+		return nil, err
+	}
+	return &models.DeviceCredential{DeviceID: id, POIID: device.POIID, Secret: secret}, nil
+}
+
+// RevokeDevice 吊销设备凭证 (删除记录)，该设备的后续请求返回 401
+func RevokeDevice(ctx context.Context, id string) error {
+	return deviceRepo().Delete(ctx, id)
+}
+
+// CurrentBooth 当前签名请求所属的旅拍机点位
+func CurrentBooth(ctx context.Context) (*models.POI, error) {
+	device, ok := auth.DeviceFrom(ctx)
+	if !ok {
+		return nil, auth.ErrUnknownDevice
+	}
+	return GetPOIDetail(ctx, device.POIID)
+}
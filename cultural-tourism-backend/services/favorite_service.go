@@ -1,19 +1,39 @@
 package services
 
 import (
+	"context"
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 	"errors"
+	"fmt"
 	"time"
 )
 
-const CollectionFavorites = "favorites"
+// 收藏业务错误 (Controller 通过 errors.Is 判断)
+var (
+	ErrInvalidResourceType = errors.New("invalid resource_type, must be one of: theme, poi, product")
+	ErrAlreadyFavorited    = fmt.Errorf("already favorited: %w", tcb.ErrConflict)
+	ErrFavoriteNotFound    = fmt.Errorf("favorite not found: %w", tcb.ErrNotFound)
+)
+
+func favoriteRepo() *tcb.Repository[models.Favorite] {
+	return tcb.NewRepository[models.Favorite](store.Default, collection.Favorites.Name())
+}
 
-// CreateFavorite 创建收藏 (幂等：重复收藏同一资源会返回已存在错误)
-func CreateFavorite(favorite *models.Favorite) (map[string]interface{}, error) {
-	// 安全处理：剥离系统字段
+// CreateFavorite 创建收藏 (幂等：同一用户重复收藏同一资源会返回已存在错误)
+func CreateFavorite(ctx context.Context, favorite *models.Favorite) (string, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return "", auth.ErrUnauthenticated
+	}
+
+	// 安全处理：剥离系统字段，归属取自登录态 (服务端令牌调用时 TCB 不会注入 _openid)
 	favorite.ID = ""
-	favorite.OpenID = "" // 由 TCB 自动注入
+	favorite.OpenID = openID
 
 	// 设置时间戳
 	favorite.CreatedAt = time.Now().Format(time.RFC3339)
@@ -22,127 +42,116 @@ func CreateFavorite(favorite *models.Favorite) (map[string]interface{}, error) {
 	// 验证资源类型
 	validTypes := map[string]bool{"theme": true, "poi": true, "product": true}
 	if !validTypes[favorite.ResourceType] {
-		return nil, errors.New("invalid resource_type, must be one of: theme, poi, product")
+		return "", ErrInvalidResourceType
 	}
 
 	// 检查是否已收藏 (防止重复)
-	existFilter := map[string]interface{}{
-		"where": map[string]interface{}{
-			"resource_type": map[string]interface{}{"$eq": favorite.ResourceType},
-			"resource_id":   map[string]interface{}{"$eq": favorite.ResourceID},
-		},
-	}
+	existFilter := query.New(
+		query.Eq("_openid", openID),
+		query.Eq("resource_type", favorite.ResourceType),
+		query.Eq("resource_id", favorite.ResourceID),
+	).Build()
 
-	existResult, err := tcb.Client.ListData(CollectionFavorites, existFilter, 1, 1)
+	existing, err := favoriteRepo().List(ctx, existFilter, 1, 1)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-
-	// 检查返回的数据结构
-	if dataMap, ok := existResult["data"].(map[string]interface{}); ok {
-		if records, ok := dataMap["records"].([]interface{}); ok && len(records) > 0 {
-			return nil, errors.New("already favorited")
-		}
+	if len(existing.Items) > 0 {
+		return "", ErrAlreadyFavorited
 	}
 
 	// 创建收藏记录
-	return tcb.Client.CreateData(CollectionFavorites, favorite)
+	return favoriteRepo().Create(ctx, favorite)
 }
 
-// DeleteFavorite 取消收藏 (通过资源类型和资源ID删除)
-func DeleteFavorite(resourceType, resourceID string) error {
+// DeleteFavorite 取消当前用户的收藏 (通过资源类型和资源ID删除)
+func DeleteFavorite(ctx context.Context, resourceType, resourceID string) error {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+
 	// 验证资源类型
 	validTypes := map[string]bool{"theme": true, "poi": true, "product": true}
 	if !validTypes[resourceType] {
-		return errors.New("invalid resource_type, must be one of: theme, poi, product")
+		return ErrInvalidResourceType
 	}
 
-	// 构造查询条件 - 先找到要删除的记录
-	filter := map[string]interface{}{
-		"where": map[string]interface{}{
-			"resource_type": map[string]interface{}{"$eq": resourceType},
-			"resource_id":   map[string]interface{}{"$eq": resourceID},
-		},
-	}
+	// 构造查询条件 - 先找到要删除的记录 (只在自己的收藏中查找)
+	filter := query.New(
+		query.Eq("_openid", openID),
+		query.Eq("resource_type", resourceType),
+		query.Eq("resource_id", resourceID),
+	).Build()
 
 	// 先查询记录获取 _id
-	result, err := tcb.Client.ListData(CollectionFavorites, filter, 1, 1)
+	result, err := favoriteRepo().List(ctx, filter, 1, 1)
 	if err != nil {
 		return err
 	}
 
 	// 检查是否找到记录
-	if dataMap, ok := result["data"].(map[string]interface{}); ok {
-		if records, ok := dataMap["records"].([]interface{}); ok && len(records) > 0 {
-			if record, ok := records[0].(map[string]interface{}); ok {
-				if id, ok := record["_id"].(string); ok {
-					// 使用 _id 删除
-					return tcb.Client.DeleteData(CollectionFavorites, id)
-				}
-			}
-		}
+	if len(result.Items) == 0 || result.Items[0].ID == "" {
+		return ErrFavoriteNotFound
 	}
 
-	return errors.New("favorite not found")
+	// 使用 _id 删除
+	return favoriteRepo().Delete(ctx, result.Items[0].ID)
 }
 
-// ListFavorites 获取用户收藏列表 (支持资源类型筛选和分页)
-func ListFavorites(resourceType string, page, size int) (map[string]interface{}, error) {
+// ListFavorites 获取当前用户的收藏列表 (支持资源类型筛选和分页)
+func ListFavorites(ctx context.Context, resourceType string, page, size int) (*tcb.Page[models.Favorite], error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return nil, auth.ErrUnauthenticated
+	}
+
 	// 设置默认分页
 	if page < 1 {
 		page = 1
 	}
-	if size < 1 || size > 100 {
+	if size < 1 {
 		size = 20
 	}
 
 	// 构造查询条件
-	where := map[string]interface{}{}
+	qb := query.New(query.Eq("_openid", openID))
 	if resourceType != "" {
 		// 验证资源类型
 		validTypes := map[string]bool{"theme": true, "poi": true, "product": true}
 		if !validTypes[resourceType] {
-			return nil, errors.New("invalid resource_type, must be one of: theme, poi, product")
+			return nil, ErrInvalidResourceType
 		}
-		where["resource_type"] = map[string]interface{}{"$eq": resourceType}
+		qb.Where(query.Eq("resource_type", resourceType))
 	}
+	qb.OrderBy("created_at", query.Desc)
 
-	filter := map[string]interface{}{
-		"where":   where,
-		"orderBy": []interface{}{
-			map[string]interface{}{"field": "created_at", "order": "desc"},
-		},
-	}
-
-	return tcb.Client.ListData(CollectionFavorites, filter, page, size)
+	return favoriteRepo().List(ctx, qb.Build(), page, size)
 }
 
-// CheckFavoriteStatus 检查收藏状态 (用于前端判断是否已收藏)
-func CheckFavoriteStatus(resourceType, resourceID string) (bool, error) {
+// CheckFavoriteStatus 检查当前用户的收藏状态 (用于前端判断是否已收藏)
+func CheckFavoriteStatus(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return false, auth.ErrUnauthenticated
+	}
+
 	// 验证资源类型
 	validTypes := map[string]bool{"theme": true, "poi": true, "product": true}
 	if !validTypes[resourceType] {
-		return false, errors.New("invalid resource_type, must be one of: theme, poi, product")
+		return false, ErrInvalidResourceType
 	}
 
-	filter := map[string]interface{}{
-		"where": map[string]interface{}{
-			"resource_type": map[string]interface{}{"$eq": resourceType},
-			"resource_id":   map[string]interface{}{"$eq": resourceID},
-		},
-	}
+	filter := query.New(
+		query.Eq("_openid", openID),
+		query.Eq("resource_type", resourceType),
+		query.Eq("resource_id", resourceID),
+	).Build()
 
-	result, err := tcb.Client.ListData(CollectionFavorites, filter, 1, 1)
+	result, err := favoriteRepo().List(ctx, filter, 1, 1)
 	if err != nil {
 		return false, err
 	}
 
-	// 检查返回的数据结构
-	if dataMap, ok := result["data"].(map[string]interface{}); ok {
-		if records, ok := dataMap["records"].([]interface{}); ok && len(records) > 0 {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return len(result.Items) > 0, nil
 }
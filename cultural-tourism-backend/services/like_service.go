@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+)
+
+// ErrAlreadyLiked 同一用户对同一照片 / 评论只能点赞一次
+var ErrAlreadyLiked = fmt.Errorf("已点赞: %w", tcb.ErrConflict)
+
+func likeRepo() *tcb.Repository[models.Like] {
+	return tcb.NewRepository[models.Like](store.Default, collection.Likes.Name())
+}
+
+// counter 对象计数字段的原子自增 (如 Repository.Increment)
+type counter func(ctx context.Context, id, field string, delta int) (int, error)
+
+// like 登记当前用户的点赞并将对象的 like_count 加一，返回最新点赞数
+// 去重依赖 likes.like_key 的唯一约束 (x-unique)：并发的重复点赞只有一次写入成功
+func like(ctx context.Context, targetType, targetID string, increment counter) (int, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return 0, auth.ErrUnauthenticated
+	}
+
+	sum := sha256.Sum256([]byte(openID + "\n" + targetType + "\n" + targetID))
+	record := models.Like{
+		OpenID:     openID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Key:        hex.EncodeToString(sum[:]),
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	id, err := likeRepo().CreateUnique(ctx, record, "like_key", record.Key)
+	if err != nil {
+		if errors.Is(err, tcb.ErrDuplicateKey) {
+			return 0, ErrAlreadyLiked
+		}
+		return 0, err
+	}
+
+	count, err := increment(ctx, targetID, "like_count", 1)
+	if err != nil {
+		// 对象不存在或自增失败：撤销点赞记录，用户可以重试
+		if delErr := likeRepo().Delete(ctx, id); delErr != nil {
+			log.Printf("⚠️ 撤销点赞记录失败 %s: %v", id, delErr)
+		}
+		return 0, err
+	}
+	return count, nil
+}
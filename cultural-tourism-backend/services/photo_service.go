@@ -2,63 +2,130 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"log"
 	"time"
 
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+	"cultural-tourism-backend/tcb/storage"
 )
 
-// [Critical] 数据库实际集合名为单数 "photo"
-const CollectionPhoto = "photo"
+func photoRepo() *tcb.Repository[models.Photo] {
+	return tcb.NewRepository[models.Photo](store.Default, collection.Photos.Name())
+}
 
-// CreatePhoto 上传照片（默认待审）
-func CreatePhoto(photo *models.Photo) (map[string]interface{}, error) {
+// CreatePhoto 上传照片（默认待审），发布者取自登录态
+func CreatePhoto(ctx context.Context, photo *models.Photo) (string, error) {
+	openID, ok := auth.OpenIDFrom(ctx)
+	if !ok {
+		return "", auth.ErrUnauthenticated
+	}
 	photo.ID = ""
+	photo.OpenID = openID
 	photo.Status = 0
 	photo.LikeCount = 0
 	photo.CreatedAt = time.Now().Format(time.RFC3339)
-	photo.UpdatedAt = time.Now().Format(time.RFC3339)
+	photo.UpdatedAt = tcb.Timestamp()
 
-	return tcb.Client.CreateData(CollectionPhoto, photo)
+	return photoRepo().Create(ctx, photo)
 }
 
 // ListPhotos 获取照片列表
-func ListPhotos(query models.PhotoQuery) (map[string]interface{}, error) {
-	where := make(map[string]interface{})
-	where["status"] = map[string]interface{}{"$eq": query.Status}
-	if query.ThemeID != "" {
-		where["theme_id"] = map[string]interface{}{"$eq": query.ThemeID}
+// 待审 / 已拒绝的照片仅审核员可查，受区域限制的审核员只能查看负责区域内主题下的照片
+func ListPhotos(ctx context.Context, q models.PhotoQuery) (*tcb.Page[models.Photo], error) {
+	qb := query.New(query.Eq("status", q.Status))
+	if q.ThemeID != "" {
+		qb.Where(query.Eq("theme_id", q.ThemeID))
 	}
 
-	filter := map[string]interface{}{
-		"where": where,
+	if q.Status != 1 {
+		scope, err := auth.ScopeOf(ctx, auth.PermModerate)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case scope.All():
+		case q.ThemeID != "":
+			if err := auth.AuthorizeIn(ctx, auth.PermModerate, themeRegion(ctx, q.ThemeID)); err != nil {
+				return nil, err
+			}
+		default:
+			themeIDs, err := themeIDsIn(ctx, scope.RegionIDs)
+			if err != nil {
+				return nil, err
+			}
+			qb.Where(inChunks("theme_id", themeIDs))
+		}
 	}
 
-	return tcb.Client.ListData(CollectionPhoto, filter, query.Page, query.Size)
+	return photoRepo().List(ctx, qb.Select(q.Fields...).Build(), q.Page, q.Size)
 }
 
 // GetPhotoDetail 获取照片详情
-func GetPhotoDetail(id string) (map[string]interface{}, error) {
-	return tcb.Client.GetDetail(CollectionPhoto, id)
+func GetPhotoDetail(ctx context.Context, id string) (*models.Photo, error) {
+	return photoRepo().Get(ctx, id)
 }
 
-// UpdatePhoto 更新照片（审核/点赞）
-func UpdatePhoto(id string, photo models.Photo) error {
+// UpdatePhoto 更新照片（审核）
+// 仅发布者或 (照片所属区域的) 审核员可修改；审核状态只能由审核员修改
+func UpdatePhoto(ctx context.Context, id string, photo models.Photo, expectedUpdatedAt string) error {
+	current, err := photoRepo().Get(ctx, id, "_openid", "theme_id")
+	if err != nil {
+		return err
+	}
+	region := parentRegion(themeRegion(ctx, current.ThemeID))
+	if err := auth.RequireOwner(ctx, current.OpenID, auth.PermModerate, region); err != nil {
+		return err
+	}
+
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
 	if photo.Status != 0 {
+		if err := auth.AuthorizeIn(ctx, auth.PermModerate, region); err != nil {
+			return err
+		}
 		updateData["status"] = photo.Status
 	}
-	if photo.LikeCount > 0 {
-		updateData["like_count"] = photo.LikeCount
-	}
 
-	return tcb.Client.UpdateData(CollectionPhoto, id, updateData)
+	return photoRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
 }
 
-// DeletePhoto 删除照片
-func DeletePhoto(id string) error {
-	return tcb.Client.DeleteData(CollectionPhoto, id)
+// LikePhoto 点赞 (需登录，每个用户只能点赞一次，重复点赞返回 ErrAlreadyLiked)，返回最新点赞数
+// like_count 只能通过原子自增修改，不随更新接口由客户端提交；
+// 点赞不刷新 updated_at，避免打断管理端基于版本的条件更新
+func LikePhoto(ctx context.Context, id string) (int, error) {
+	return like(ctx, models.LikeTargetPhoto, id, photoRepo().Increment)
+}
+
+// DeletePhoto 删除照片 (仅发布者或照片所属区域的审核员)，并尽力清理云存储中的图片文件 (清理失败只记录日志)
+func DeletePhoto(ctx context.Context, id string) error {
+	photo, err := photoRepo().Get(ctx, id, "image_url", "_openid", "theme_id")
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireOwner(ctx, photo.OpenID, auth.PermModerate, parentRegion(themeRegion(ctx, photo.ThemeID))); err != nil {
+		return err
+	}
+	if err := photoRepo().Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if storage.Default != nil && storage.IsFileID(photo.ImageURL) {
+		results, err := storage.Default.Delete(ctx, photo.ImageURL)
+		if err == nil && len(results) == 1 && !results[0].OK {
+			err = errors.New(results[0].Error)
+		}
+		if err != nil {
+			log.Printf("⚠️ 照片 %s 的文件 %s 清理失败: %v", id, photo.ImageURL, err)
+		}
+	}
+	return nil
 }
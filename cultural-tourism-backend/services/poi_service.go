@@ -2,23 +2,37 @@
 package services
 
 import (
-	"fmt"
+	"context"
+	"math"
 	"time"
 
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 )
 
-// Collection name for POIs
-const CollectionPOI = "poi"
+func poiRepo() *tcb.Repository[models.POI] {
+	return tcb.NewRepository[models.POI](store.Default, collection.POIs.Name())
+}
 
 // CreatePOI creates a new POI
-func CreatePOI(poi *models.POI) (map[string]interface{}, error) {
+// 受区域限制的管理员只能在负责区域内创建
+func CreatePOI(ctx context.Context, poi *models.POI) (string, error) {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region(poi.RegionID)); err != nil {
+		return "", err
+	}
+
 	// [Security] 强制初始化字段，防止恶意篡改
 	poi.ID = ""
-	poi.Status = 1
 	poi.CreatedAt = time.Now().Format(time.RFC3339)
-	poi.UpdatedAt = time.Now().Format(time.RFC3339)
+	poi.UpdatedAt = tcb.Timestamp()
+	poi.Distance = 0
+	if poi.Status == 0 {
+		poi.Status = 1
+	}
 
 	// Images 数组防止 nil 导致入库异常
 	if len(poi.Images) == 0 {
@@ -27,45 +41,100 @@ func CreatePOI(poi *models.POI) (map[string]interface{}, error) {
 
 	// 防止恶意写入非预期字段（业务兜底）
 
-
-	return tcb.Client.CreateData(CollectionPOI, poi)
+	return poiRepo().Create(ctx, poi)
 }
 
 // ListPOIs retrieves POI list with filtering and pagination
-func ListPOIs(query models.POIQuery) (map[string]interface{}, error) {
-	where := make(map[string]interface{})
+// 传入用户位置 (UserLat/UserLng) 时为每个点位计算距离 (_distance，单位米)
+func ListPOIs(ctx context.Context, q models.POIQuery) (*tcb.Page[models.POI], error) {
+	withDistance := q.UserLat != 0 && q.UserLng != 0
 
 	// 状态筛选 - 默认只返回上线状态
-	where["status"] = map[string]interface{}{"$eq": 1}
+	qb := query.New(query.Eq("status", 1))
 
 	// 区域筛选
-	if query.RegionID != "" {
-		where["region_id"] = map[string]interface{}{"$eq": query.RegionID}
+	if q.RegionID != "" {
+		qb.Where(query.Eq("region_id", q.RegionID))
 	}
 
 	// POI 类型筛选
-	if query.Type != "" {
-		where["type"] = map[string]interface{}{"$eq": query.Type}
+	if q.Type != "" {
+		qb.Where(query.Eq("type", q.Type))
 	}
 
+	if len(q.Fields) > 0 {
+		// _distance 不存库，由经纬度现算
+		for _, f := range q.Fields {
+			if f != "_distance" {
+				qb.Select(f)
+			}
+		}
+		if withDistance {
+			qb.Select("latitude", "longitude")
+		}
+	}
 
+	result, err := poiRepo().List(ctx, qb.Build(), q.Page, q.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// [LBS Feature] 距离计算
+	if withDistance {
+		for i := range result.Items {
+			poi := &result.Items[i]
+			if poi.Latitude != 0 && poi.Longitude != 0 {
+				poi.Distance = math.Round(calculateDistance(q.UserLat, q.UserLng, poi.Latitude, poi.Longitude))
+			}
+		}
+	}
+	return result, nil
+}
+
+// calculateDistance 两点间的球面距离 (米)
+func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	const R = 6371000 // 地球半径 (米)
+	dLat := (lat2 - lat1) * (math.Pi / 180.0)
+	dLon := (lon2 - lon1) * (math.Pi / 180.0)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*(math.Pi/180.0))*math.Cos(lat2*(math.Pi/180.0))*
+			math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return R * c
+}
 
-	filter := map[string]interface{}{
-		"where": where,
+// ListManagedPOIs 管理端点位列表：包含未上线点位，受区域限制时只返回负责区域内的点位
+func ListManagedPOIs(ctx context.Context, q models.POIQuery) (*tcb.Page[models.POI], error) {
+	qb := query.New()
+	if q.RegionID != "" {
+		qb.Where(query.Eq("region_id", q.RegionID))
+	}
+	if q.Type != "" {
+		qb.Where(query.Eq("type", q.Type))
+	}
+	if err := restrictToScope(ctx, qb, q.RegionID); err != nil {
+		return nil, err
 	}
 
-	return tcb.Client.ListData(CollectionPOI, filter, query.Page, query.Size)
+	return poiRepo().List(ctx, qb.Select(q.Fields...).Build(), q.Page, q.Size)
 }
 
 // GetPOIDetail retrieves a single POI by ID
-func GetPOIDetail(id string) (map[string]interface{}, error) {
-	return tcb.Client.GetDetail(CollectionPOI, id)
+func GetPOIDetail(ctx context.Context, id string) (*models.POI, error) {
+	return poiRepo().Get(ctx, id)
 }
 
 // UpdatePOI updates an existing POI
-func UpdatePOI(id string, poi *models.POI) error {
+// 受区域限制的管理员只能修改负责区域内的点位，且不能移到其他区域
+func UpdatePOI(ctx context.Context, id string, poi *models.POI, expectedUpdatedAt string) error {
+	if err := authorizeMove(ctx, auth.PermManageContent, poiRegion(ctx, id), poi.RegionID); err != nil {
+		return err
+	}
+
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
 	if poi.Name != "" {
@@ -103,61 +172,37 @@ func UpdatePOI(id string, poi *models.POI) error {
 		updateData["status"] = poi.Status
 	}
 
-	return tcb.Client.UpdateData(CollectionPOI, id, updateData)
+	return poiRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
 }
 
 // DeletePOI deletes a POI by ID
-func DeletePOI(id string) error {
-	return tcb.Client.DeleteData(CollectionPOI, id)
-}
-
-// GetNearbyPOIs retrieves POIs within a radius of a point
-func GetNearbyPOIs(lat, lng float64, radiusKM int, page, size int) (map[string]interface{}, error) {
-	where := map[string]interface{}{
-		"status": map[string]interface{}{"$eq": 1}, // 只返回上线 POI
-		"location": map[string]interface{}{
-			"$near": map[string]interface{}{
-				"$geometry": map[string]interface{}{
-					"type":        "Point",
-					"coordinates": []float64{lng, lat},
-				},
-				"$maxDistance": radiusKM * 1000,
-			},
-		},
-	}
-
-	filter := map[string]interface{}{
-		"where": where,
+func DeletePOI(ctx context.Context, id string) error {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, poiRegion(ctx, id)); err != nil {
+		return err
 	}
-
-	return tcb.Client.ListData(CollectionPOI, filter, page, size)
+	return poiRepo().Delete(ctx, id)
 }
 
 // CountPOIsByRegion counts POIs by region (for statistics)
-func CountPOIsByRegion(regionID string) (map[string]interface{}, error) {
-	where := map[string]interface{}{
-		"region_id": map[string]interface{}{"$eq": regionID},
-		"status":    map[string]interface{}{"$eq": 1},
-	}
-
-	filter := map[string]interface{}{
-		"where": where,
-		"count": true,
-	}
+func CountPOIsByRegion(ctx context.Context, regionID string) (int, error) {
+	filter := query.New(
+		query.Eq("region_id", regionID),
+		query.Eq("status", 1),
+	).Build()
 
-	return tcb.Client.ListData(CollectionPOI, filter, 1, 1)
+	return poiRepo().Count(ctx, filter)
 }
 
 // BatchUpdatePOIStatus batch updates POI status (for admin operations)
-func BatchUpdatePOIStatus(ids []string, status int) error {
-	for _, id := range ids {
-		updateData := map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now().Format(time.RFC3339),
-		}
-		if err := tcb.Client.UpdateData(CollectionPOI, id, updateData); err != nil {
-			return fmt.Errorf("failed to update POI %s: %v", id, err)
-		}
+// 单次 updateMany 完成，不存在的 ID 在结果中逐条标记，不中断整批
+// 批量操作不逐条校验区域，仅限不受区域限制的管理员
+func BatchUpdatePOIStatus(ctx context.Context, ids []string, status int) (*tcb.BatchResult, error) {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region("")); err != nil {
+		return nil, err
+	}
+	updateData := map[string]interface{}{
+		"status":     status,
+		"updated_at": tcb.Timestamp(),
 	}
-	return nil
-}
\ No newline at end of file
+	return poiRepo().UpdateByIDs(ctx, ids, updateData)
+}
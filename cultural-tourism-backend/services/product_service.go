@@ -2,54 +2,56 @@
 package services
 
 import (
+	"context"
 	"time"
 
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 )
 
-// Collection name for Products
-const CollectionProduct = "product"
+func productRepo() *tcb.Repository[models.Product] {
+	return tcb.NewRepository[models.Product](store.Default, collection.Products.Name())
+}
 
 // CreateProduct creates a new product
-func CreateProduct(product *models.Product) (map[string]interface{}, error) {
+func CreateProduct(ctx context.Context, product *models.Product) (string, error) {
 	// [Security] 强制初始化字段，防止恶意篡改
 	product.ID = ""
 
 	product.CreatedAt = time.Now().Format(time.RFC3339)
-	product.UpdatedAt = time.Now().Format(time.RFC3339)
+	product.UpdatedAt = tcb.Timestamp()
 
 	// 防止恶意写入非预期字段（业务兜底）
 	if product.Price <= 0 {
 		product.Price = 0
 	}
+	if product.Status == 0 {
+		product.Status = 1
+	}
 
-	return tcb.Client.CreateData(CollectionProduct, product)
+	return productRepo().Create(ctx, product)
 }
 
 // ListProducts retrieves product list with pagination
-func ListProducts(query models.ProductQuery) (map[string]interface{}, error) {
-	where := make(map[string]interface{})
-
+func ListProducts(ctx context.Context, q models.ProductQuery) (*tcb.Page[models.Product], error) {
 	// 状态筛选 - 默认只返回上线状态
-	where["status"] = map[string]interface{}{"$eq": 1}
+	filter := query.New(query.Eq("status", 1)).Select(q.Fields...).Build()
 
-	filter := map[string]interface{}{
-		"where": where,
-	}
-
-	return tcb.Client.ListData(CollectionProduct, filter, query.Page, query.Size)
+	return productRepo().List(ctx, filter, q.Page, q.Size)
 }
 
 // GetProductDetail retrieves a single product by ID
-func GetProductDetail(id string) (map[string]interface{}, error) {
-	return tcb.Client.GetDetail(CollectionProduct, id)
+func GetProductDetail(ctx context.Context, id string) (*models.Product, error) {
+	return productRepo().Get(ctx, id)
 }
 
 // UpdateProduct updates an existing product
-func UpdateProduct(id string, product *models.Product) error {
+func UpdateProduct(ctx context.Context, id string, product *models.Product, expectedUpdatedAt string) error {
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
 	if product.Name != "" {
@@ -67,40 +69,31 @@ func UpdateProduct(id string, product *models.Product) error {
 	if product.JumpPath != "" {
 		updateData["jump_path"] = product.JumpPath
 	}
+	if product.Status != 0 {
+		updateData["status"] = product.Status
+	}
 
-
-	return tcb.Client.UpdateData(CollectionProduct, id, updateData)
+	return productRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
 }
 
 // DeleteProduct deletes a product by ID
-func DeleteProduct(id string) error {
-	return tcb.Client.DeleteData(CollectionProduct, id)
+func DeleteProduct(ctx context.Context, id string) error {
+	return productRepo().Delete(ctx, id)
 }
 
 // BatchUpdateProductStatus batch updates product status (for admin operations)
-func BatchUpdateProductStatus(ids []string, status int) error {
-	for _, id := range ids {
-		updateData := map[string]interface{}{
-
-			"updated_at": time.Now().Format(time.RFC3339),
-		}
-		if err := tcb.Client.UpdateData(CollectionProduct, id, updateData); err != nil {
-			return err
-		}
+// 单次 updateMany 完成，不存在的 ID 在结果中逐条标记，不中断整批
+func BatchUpdateProductStatus(ctx context.Context, ids []string, status int) (*tcb.BatchResult, error) {
+	updateData := map[string]interface{}{
+		"status":     status,
+		"updated_at": tcb.Timestamp(),
 	}
-	return nil
+	return productRepo().UpdateByIDs(ctx, ids, updateData)
 }
 
 // CountProducts counts products (for statistics)
-func CountProducts() (map[string]interface{}, error) {
-	where := map[string]interface{}{
-		"status": map[string]interface{}{"$eq": 1},
-	}
+func CountProducts(ctx context.Context) (int, error) {
+	filter := query.New(query.Eq("status", 1)).Build()
 
-	filter := map[string]interface{}{
-		"where": where,
-		"count": true,
-	}
-
-	return tcb.Client.ListData(CollectionProduct, filter, 1, 1)
-}
\ No newline at end of file
+	return productRepo().Count(ctx, filter)
+}
@@ -2,48 +2,53 @@
 package services
 
 import (
+	"context"
 	"time"
 
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 )
 
-const CollectionRegion = "regions"
+func regionRepo() *tcb.Repository[models.Region] {
+	return tcb.NewRepository[models.Region](store.Default, collection.Regions.Name())
+}
+
+// CreateRegion 创建新区域 (仅限不受区域限制的管理员)
+func CreateRegion(ctx context.Context, region models.Region) (string, error) {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region("")); err != nil {
+		return "", err
+	}
 
-// CreateRegion 创建新区域
-func CreateRegion(region models.Region) (map[string]interface{}, error) {
 	// 补全默认值
 	region.ID = "" // 安全置空，ID由云开发生成
 	region.Status = 1
 	region.CreatedAt = time.Now().Format(time.RFC3339)
-	region.UpdatedAt = time.Now().Format(time.RFC3339)
+	region.UpdatedAt = tcb.Timestamp()
 	if region.Sort == 0 {
 		region.Sort = 100 // 默认排序权重
 	}
 
-	result, err := tcb.Client.CreateData(CollectionRegion, region)
+	id, err := regionRepo().Create(ctx, region)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return result, nil
+	return id, nil
 }
 
 // ListRegions 获取区域列表
-func ListRegions(page, size, status int) (map[string]interface{}, error) {
-	// 构造筛选条件
-	where := map[string]interface{}{
-		"status": map[string]interface{}{
-			"$eq": status,
-		},
-	}
-
-	filter := map[string]interface{}{
-		"where": where,
-		// TODO: 等待 SDK 支持 orderBy
-	}
-
-	result, err := tcb.Client.ListData(CollectionRegion, filter, page, size)
+func ListRegions(ctx context.Context, page, size, status int, fields ...string) (*tcb.Page[models.Region], error) {
+	// 构造筛选条件：排序权重值越大越靠前
+	filter := query.New(query.Eq("status", status)).
+		OrderBy("sort", query.Desc).
+		Select(fields...).
+		Build()
+
+	result, err := regionRepo().List(ctx, filter, page, size)
 	if err != nil {
 		return nil, err
 	}
@@ -52,8 +57,8 @@ func ListRegions(page, size, status int) (map[string]interface{}, error) {
 }
 
 // GetRegionDetail 获取单条区域详情
-func GetRegionDetail(id string) (map[string]interface{}, error) {
-	result, err := tcb.Client.GetDetail(CollectionRegion, id)
+func GetRegionDetail(ctx context.Context, id string) (*models.Region, error) {
+	result, err := regionRepo().Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -61,11 +66,15 @@ func GetRegionDetail(id string) (map[string]interface{}, error) {
 	return result, nil
 }
 
-// UpdateRegion 更新区域
-func UpdateRegion(id string, region models.Region) error {
+// UpdateRegion 更新区域 (受区域限制的管理员只能修改负责的区域)
+func UpdateRegion(ctx context.Context, id string, region models.Region, expectedUpdatedAt string) error {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region(id)); err != nil {
+		return err
+	}
+
 	// 使用 Map 构造更新数据，支持 Partial Update
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
 	if region.Name != "" {
@@ -79,7 +88,7 @@ func UpdateRegion(id string, region models.Region) error {
 		updateData["status"] = region.Status
 	}
 
-	err := tcb.Client.UpdateData(CollectionRegion, id, updateData)
+	err := regionRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
 	if err != nil {
 		return err
 	}
@@ -87,12 +96,15 @@ func UpdateRegion(id string, region models.Region) error {
 	return nil
 }
 
-// DeleteRegion 删除区域
-func DeleteRegion(id string) error {
-	err := tcb.Client.DeleteData(CollectionRegion, id)
+// DeleteRegion 删除区域 (仅限不受区域限制的管理员)
+func DeleteRegion(ctx context.Context, id string) error {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region("")); err != nil {
+		return err
+	}
+	err := regionRepo().Delete(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	return nil
-}
\ No newline at end of file
+}
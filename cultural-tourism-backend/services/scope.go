@@ -0,0 +1,145 @@
+// File: services/scope.go
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// 按区域收集 ID 并作为 $in 条件下发时的限制
+const (
+	scanPageSize = 200  // 收集 ID 时的单页条数 (list 接口上限)
+	inChunkSize  = 100  // 单个 $in 的取值个数，超出时拆成多个 $in 以 $or 连接
+	maxScopedIDs = 1000 // 区域内 ID 总数上限，超出时要求调用方指定主题 / 点位筛选
+)
+
+// ErrScopeTooLarge 负责区域内的主题 / 点位过多，无法按区域收窄列表
+var ErrScopeTooLarge = errors.New("负责区域内内容过多，请指定主题或点位筛选")
+
+// themeRegion 主题所属区域 (首次调用时查询)
+func themeRegion(ctx context.Context, themeID string) auth.RegionOf {
+	return lazyRegion(func() (string, error) {
+		if themeID == "" {
+			return "", nil
+		}
+		theme, err := themeRepo().Get(ctx, themeID, "region_id")
+		if err != nil {
+			return "", err
+		}
+		return theme.RegionID, nil
+	})
+}
+
+// poiRegion 点位所属区域 (首次调用时查询)
+func poiRegion(ctx context.Context, poiID string) auth.RegionOf {
+	return lazyRegion(func() (string, error) {
+		if poiID == "" {
+			return "", nil
+		}
+		poi, err := poiRepo().Get(ctx, poiID, "region_id")
+		if err != nil {
+			return "", err
+		}
+		return poi.RegionID, nil
+	})
+}
+
+// parentRegion 照片 / 评论经由主题 / 点位归属区域；上级已被删除时视为未归属区域 (仅不限区域的管理员可操作)
+func parentRegion(region auth.RegionOf) auth.RegionOf {
+	return func() (string, error) {
+		regionID, err := region()
+		if errors.Is(err, tcb.ErrNotFound) {
+			return "", nil
+		}
+		return regionID, err
+	}
+}
+
+func lazyRegion(get func() (string, error)) auth.RegionOf {
+	var (
+		done     bool
+		regionID string
+		err      error
+	)
+	return func() (string, error) {
+		if !done {
+			regionID, err = get()
+			done = true
+		}
+		return regionID, err
+	}
+}
+
+// authorizeMove 修改归属区域时，目标区域同样需要在负责范围内
+func authorizeMove(ctx context.Context, perm auth.Permission, current auth.RegionOf, target string) error {
+	if err := auth.AuthorizeIn(ctx, perm, current); err != nil {
+		return err
+	}
+	if target == "" {
+		return nil
+	}
+	return auth.AuthorizeIn(ctx, perm, auth.Region(target))
+}
+
+// themeIDsIn 区域内全部主题的 ID
+func themeIDsIn(ctx context.Context, regionIDs []string) ([]string, error) {
+	return idsInRegions(ctx, collection.Themes.Name(), regionIDs)
+}
+
+// poiIDsIn 区域内全部点位的 ID
+func poiIDsIn(ctx context.Context, regionIDs []string) ([]string, error) {
+	return idsInRegions(ctx, collection.POIs.Name(), regionIDs)
+}
+
+// idsInRegions 按 _id 游标遍历区域内记录的 ID，超过 maxScopedIDs 时返回 ErrScopeTooLarge
+func idsInRegions(ctx context.Context, model string, regionIDs []string) ([]string, error) {
+	filter := query.New(query.In("region_id", regionIDs...)).Select("_id").Build()
+
+	ids := []string{}
+	for rec, err := range tcb.ListAll(ctx, store.Default, model, filter, tcb.ScanOptions{Order: tcb.ScanByID, PageSize: scanPageSize}) {
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == maxScopedIDs {
+			return nil, ErrScopeTooLarge
+		}
+		id, _ := rec["_id"].(string)
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// inChunks field 属于 values：按 inChunkSize 拆分为多个 $in，避免单个条件过长
+func inChunks(field string, values []string) query.Cond {
+	if len(values) <= inChunkSize {
+		return query.In(field, values...)
+	}
+	var chunks []query.Cond
+	for chunk := range slices.Chunk(values, inChunkSize) {
+		chunks = append(chunks, query.In(field, chunk...))
+	}
+	return query.Or(chunks...)
+}
+
+// restrictToScope 按内容管理员的负责区域收窄列表：指定了 regionID 时校验其在范围内，否则限定为负责区域
+func restrictToScope(ctx context.Context, qb *query.Query, regionID string) error {
+	scope, err := auth.ScopeOf(ctx, auth.PermManageContent)
+	if err != nil || scope.All() {
+		return err
+	}
+	if regionID == "" {
+		qb.Where(query.In("region_id", scope.RegionIDs...))
+		return nil
+	}
+	if !scope.Allows(regionID) {
+		return auth.ErrOutOfScope
+	}
+	return nil
+}
@@ -2,59 +2,90 @@
 package services
 
 import (
+	"context"
 	"time"
 
+	"cultural-tourism-backend/auth"
+	"cultural-tourism-backend/collection"
 	"cultural-tourism-backend/models"
+	"cultural-tourism-backend/store"
 	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
 )
 
-// Collection name for Themes
-const CollectionTheme = "theme"
+func themeRepo() *tcb.Repository[models.Theme] {
+	return tcb.NewRepository[models.Theme](store.Default, collection.Themes.Name())
+}
 
 // CreateTheme creates a new theme
-func CreateTheme(theme *models.Theme) (map[string]interface{}, error) {
+// 受区域限制的管理员只能在负责区域内创建
+func CreateTheme(ctx context.Context, theme *models.Theme) (string, error) {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region(theme.RegionID)); err != nil {
+		return "", err
+	}
+
 	// [Security] 强制初始化字段，防止恶意篡改
 	theme.ID = ""
-	theme.Status = 1
 	theme.CreatedAt = time.Now().Format(time.RFC3339)
-	theme.UpdatedAt = time.Now().Format(time.RFC3339)
-
-	// 防止恶意写入非预期字段（业务兜底）
+	theme.UpdatedAt = tcb.Timestamp()
 	if theme.Sort <= 0 {
-		theme.Sort = 9999
+		theme.Sort = 100
+	}
+	if theme.Status == 0 {
+		theme.Status = 1 // 默认上架
 	}
 
-	return tcb.Client.CreateData(CollectionTheme, theme)
+	return themeRepo().Create(ctx, theme)
 }
 
 // ListThemes retrieves theme list with filtering and pagination
-func ListThemes(query models.ThemeQuery) (map[string]interface{}, error) {
-	where := make(map[string]interface{})
+// 未启用的主题仅内容管理员可查 (同 ListManagedThemes)
+func ListThemes(ctx context.Context, q models.ThemeQuery) (*tcb.Page[models.Theme], error) {
+	if q.Status != 1 {
+		return ListManagedThemes(ctx, q)
+	}
 
 	// 状态筛选 - 默认只返回上线状态
-	where["status"] = map[string]interface{}{"$eq": 1}
+	qb := query.New(query.Eq("status", 1))
 
 	// 区域筛选（区域优先）
-	if query.RegionID != "" {
-		where["region_id"] = map[string]interface{}{"$eq": query.RegionID}
+	if q.RegionID != "" {
+		qb.Where(query.Eq("region_id", q.RegionID))
 	}
 
-	filter := map[string]interface{}{
-		"where": where,
+	qb.OrderBy("sort", query.Desc)
+
+	return themeRepo().List(ctx, qb.Select(q.Fields...).Build(), q.Page, q.Size)
+}
+
+// ListManagedThemes 管理端主题列表：按 status 筛选，受区域限制时只返回负责区域内的主题
+func ListManagedThemes(ctx context.Context, q models.ThemeQuery) (*tcb.Page[models.Theme], error) {
+	qb := query.New(query.Eq("status", q.Status))
+	if q.RegionID != "" {
+		qb.Where(query.Eq("region_id", q.RegionID))
 	}
+	if err := restrictToScope(ctx, qb, q.RegionID); err != nil {
+		return nil, err
+	}
+	qb.OrderBy("sort", query.Desc)
 
-	return tcb.Client.ListData(CollectionTheme, filter, query.Page, query.Size)
+	return themeRepo().List(ctx, qb.Select(q.Fields...).Build(), q.Page, q.Size)
 }
 
 // GetThemeDetail retrieves a single theme by ID
-func GetThemeDetail(id string) (map[string]interface{}, error) {
-	return tcb.Client.GetDetail(CollectionTheme, id)
+func GetThemeDetail(ctx context.Context, id string) (*models.Theme, error) {
+	return themeRepo().Get(ctx, id)
 }
 
 // UpdateTheme updates an existing theme
-func UpdateTheme(id string, theme *models.Theme) error {
+// 受区域限制的管理员只能修改负责区域内的主题，且不能移到其他区域
+func UpdateTheme(ctx context.Context, id string, theme *models.Theme, expectedUpdatedAt string) error {
+	if err := authorizeMove(ctx, auth.PermManageContent, themeRegion(ctx, id), theme.RegionID); err != nil {
+		return err
+	}
+
 	updateData := map[string]interface{}{
-		"updated_at": time.Now().Format(time.RFC3339),
+		"updated_at": tcb.Timestamp(),
 	}
 
 	if theme.Name != "" {
@@ -69,60 +100,54 @@ func UpdateTheme(id string, theme *models.Theme) error {
 	if theme.RegionID != "" {
 		updateData["region_id"] = theme.RegionID
 	}
-	if theme.Sort != 0 {
+	if theme.Sort > 0 {
 		updateData["sort"] = theme.Sort
 	}
 	if theme.Status != 0 {
 		updateData["status"] = theme.Status
 	}
 
-	return tcb.Client.UpdateData(CollectionTheme, id, updateData)
+	return themeRepo().UpdateIf(ctx, id, expectedUpdatedAt, updateData)
 }
 
 // DeleteTheme deletes a theme by ID
-func DeleteTheme(id string) error {
-	return tcb.Client.DeleteData(CollectionTheme, id)
+func DeleteTheme(ctx context.Context, id string) error {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, themeRegion(ctx, id)); err != nil {
+		return err
+	}
+	return themeRepo().Delete(ctx, id)
 }
 
 // GetThemesByRegion retrieves all themes for a specific region
-func GetThemesByRegion(regionID string, page, size int) (map[string]interface{}, error) {
-	where := map[string]interface{}{
-		"region_id": map[string]interface{}{"$eq": regionID},
-		"status":    map[string]interface{}{"$eq": 1},
-	}
-
-	filter := map[string]interface{}{
-		"where": where,
-	}
+func GetThemesByRegion(ctx context.Context, regionID string, page, size int) (*tcb.Page[models.Theme], error) {
+	filter := query.New(
+		query.Eq("region_id", regionID),
+		query.Eq("status", 1),
+	).OrderBy("sort", query.Desc).Build()
 
-	return tcb.Client.ListData(CollectionTheme, filter, page, size)
+	return themeRepo().List(ctx, filter, page, size)
 }
 
 // BatchUpdateThemeStatus batch updates theme status (for admin operations)
-func BatchUpdateThemeStatus(ids []string, status int) error {
-	for _, id := range ids {
-		updateData := map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now().Format(time.RFC3339),
-		}
-		if err := tcb.Client.UpdateData(CollectionTheme, id, updateData); err != nil {
-			return err
-		}
-	}
-	return nil
+// 单次 updateMany 完成，不存在的 ID 在结果中逐条标记，不中断整批
+// 批量操作不逐条校验区域，仅限不受区域限制的管理员
+func BatchUpdateThemeStatus(ctx context.Context, ids []string, status int) (*tcb.BatchResult, error) {
+	if err := auth.AuthorizeIn(ctx, auth.PermManageContent, auth.Region("")); err != nil {
+		return nil, err
+	}
+	updateData := map[string]interface{}{
+		"status":     status,
+		"updated_at": tcb.Timestamp(),
+	}
+	return themeRepo().UpdateByIDs(ctx, ids, updateData)
 }
 
 // CountThemesByRegion counts themes by region (for statistics)
-func CountThemesByRegion(regionID string) (map[string]interface{}, error) {
-	where := map[string]interface{}{
-		"region_id": map[string]interface{}{"$eq": regionID},
-		"status":    map[string]interface{}{"$eq": 1},
-	}
+func CountThemesByRegion(ctx context.Context, regionID string) (int, error) {
+	filter := query.New(
+		query.Eq("region_id", regionID),
+		query.Eq("status", 1),
+	).Build()
 
-	filter := map[string]interface{}{
-		"where": where,
-		"count": true,
-	}
-
-	return tcb.Client.ListData(CollectionTheme, filter, 1, 1)
-}
\ No newline at end of file
+	return themeRepo().Count(ctx, filter)
+}
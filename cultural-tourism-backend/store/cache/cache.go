@@ -0,0 +1,224 @@
+// Package cache 为 store.DataStore 提供读穿透缓存
+//
+// ListData / GetDetail 的结果按 "模型 + filter + 分页" 缓存，
+// 同一模型上的任何写操作 (create/update/delete 及批量版本) 都会使该模型的缓存失效。
+// 同一个键的并发未命中通过 singleflight 合并为一次后端请求。
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cultural-tourism-backend/config"
+	"cultural-tourism-backend/store"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 默认配置
+const (
+	DefaultTTL        = 30 * time.Second
+	DefaultMaxEntries = 1000
+)
+
+// Options 缓存配置
+type Options struct {
+	TTL     time.Duration
+	Backend Backend         // 为 nil 时使用 NewLRU(DefaultMaxEntries)
+	Models  map[string]bool // 仅缓存这些模型；为空表示全部缓存
+	Exclude map[string]bool // 始终不缓存的模型 (优先于 Models)，如鉴权数据：其他实例上的写入无法使本实例缓存失效
+}
+
+// Store 带缓存的 DataStore 装饰器
+type Store struct {
+	next    store.DataStore
+	backend Backend
+	ttl     time.Duration
+	models  map[string]bool
+	exclude map[string]bool
+
+	group singleflight.Group
+
+	mu          sync.Mutex
+	generations map[string]uint64 // 模型写入代数：失效时递增，防止失效前发出的查询回填旧数据
+
+	hits, misses atomic.Int64
+}
+
+var _ store.DataStore = (*Store)(nil)
+
+// Wrap 用缓存包装 next
+func Wrap(next store.DataStore, opts Options) *Store {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Backend == nil {
+		opts.Backend = NewLRU(DefaultMaxEntries)
+	}
+	return &Store{
+		next:        next,
+		backend:     opts.Backend,
+		ttl:         opts.TTL,
+		models:      opts.Models,
+		exclude:     opts.Exclude,
+		generations: make(map[string]uint64),
+	}
+}
+
+// OptionsFromConfig 由统一配置构造缓存选项 (TTL / 最大条目数 / 缓存的模型，为空表示全部)
+func OptionsFromConfig(cfg config.Cache) Options {
+	opts := Options{TTL: cfg.TTL, Backend: NewLRU(cfg.MaxEntries)}
+	if len(cfg.Models) > 0 {
+		opts.Models = make(map[string]bool, len(cfg.Models))
+		for _, m := range cfg.Models {
+			opts.Models[m] = true
+		}
+	}
+	return opts
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Stats 返回命中统计快照
+func (s *Store) Stats() Stats {
+	return Stats{Hits: s.hits.Load(), Misses: s.misses.Load()}
+}
+
+// ---------------------------------------------------------------------------
+// 读操作：读穿透
+// ---------------------------------------------------------------------------
+
+func (s *Store) ListData(ctx context.Context, model string, filter map[string]interface{}, page, size int) (map[string]interface{}, error) {
+	if !s.cacheable(model) {
+		return s.next.ListData(ctx, model, filter, page, size)
+	}
+	params, err := json.Marshal(map[string]interface{}{"filter": filter, "page": page, "size": size}) // map 键有序，可作为稳定键
+	if err != nil {
+		return s.next.ListData(ctx, model, filter, page, size)
+	}
+	return s.load(ctx, model, "list:"+string(params), func(ctx context.Context) (map[string]interface{}, error) {
+		return s.next.ListData(ctx, model, filter, page, size)
+	})
+}
+
+func (s *Store) GetDetail(ctx context.Context, model, id string, fields ...string) (map[string]interface{}, error) {
+	if !s.cacheable(model) {
+		return s.next.GetDetail(ctx, model, id, fields...)
+	}
+	return s.load(ctx, model, "detail:"+id+":"+strings.Join(fields, ","), func(ctx context.Context) (map[string]interface{}, error) {
+		return s.next.GetDetail(ctx, model, id, fields...)
+	})
+}
+
+// load 命中直接返回；未命中时合并并发请求，结果回填缓存 (错误不缓存)
+func (s *Store) load(ctx context.Context, model, suffix string, fetch func(context.Context) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	gen := s.generation(model)
+	key := fmt.Sprintf("%s:%d:%s", model, gen, suffix)
+
+	if data, ok := s.backend.Get(ctx, key); ok {
+		if out, err := decode(data); err == nil {
+			s.hits.Add(1)
+			return out, nil
+		}
+	}
+	s.misses.Add(1)
+
+	// 共享的后端请求不随某一个调用方取消而中止，各调用方各自等待自己的 ctx
+	ch := s.group.DoChan(key, func() (interface{}, error) {
+		result, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		if s.generation(model) == gen {
+			s.backend.Set(context.WithoutCancel(ctx), key, data, s.ttl)
+		}
+		return data, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// 每个调用方拿到独立副本，互不影响
+		return decode(res.Val.([]byte))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 写操作：透传后使该模型缓存失效
+// ---------------------------------------------------------------------------
+
+func (s *Store) CreateData(ctx context.Context, model string, data interface{}) (map[string]interface{}, error) {
+	defer s.invalidate(ctx, model)
+	return s.next.CreateData(ctx, model, data)
+}
+
+func (s *Store) UpdateData(ctx context.Context, model, id string, data interface{}) error {
+	defer s.invalidate(ctx, model)
+	return s.next.UpdateData(ctx, model, id, data)
+}
+
+func (s *Store) DeleteData(ctx context.Context, model, id string) error {
+	defer s.invalidate(ctx, model)
+	return s.next.DeleteData(ctx, model, id)
+}
+
+func (s *Store) CreateMany(ctx context.Context, model string, data []interface{}) (*store.BatchResult, error) {
+	defer s.invalidate(ctx, model)
+	return s.next.CreateMany(ctx, model, data)
+}
+
+func (s *Store) UpdateMany(ctx context.Context, model string, filter map[string]interface{}, data interface{}) (*store.BatchResult, error) {
+	defer s.invalidate(ctx, model)
+	return s.next.UpdateMany(ctx, model, filter, data)
+}
+
+func (s *Store) DeleteMany(ctx context.Context, model string, filter map[string]interface{}) (*store.BatchResult, error) {
+	defer s.invalidate(ctx, model)
+	return s.next.DeleteMany(ctx, model, filter)
+}
+
+// invalidate 写操作后 (无论成败，失败也可能已部分生效) 使模型缓存失效
+func (s *Store) invalidate(ctx context.Context, model string) {
+	if !s.cacheable(model) {
+		return
+	}
+	s.mu.Lock()
+	s.generations[model]++
+	s.mu.Unlock()
+	s.backend.DeletePrefix(context.WithoutCancel(ctx), model+":")
+}
+
+func (s *Store) generation(model string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generations[model]
+}
+
+func (s *Store) cacheable(model string) bool {
+	return !s.exclude[model] && (len(s.models) == 0 || s.models[model])
+}
+
+func decode(data []byte) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
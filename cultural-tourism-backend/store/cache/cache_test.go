@@ -0,0 +1,140 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/store/cache"
+	"cultural-tourism-backend/store/local"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// countingStore 统计穿透到后端的读请求，可选地阻塞读请求
+type countingStore struct {
+	store.DataStore
+	reads atomic.Int64
+	gate  chan struct{}
+}
+
+func (s *countingStore) ListData(ctx context.Context, model string, filter map[string]interface{}, page, size int) (map[string]interface{}, error) {
+	s.reads.Add(1)
+	if s.gate != nil {
+		<-s.gate
+	}
+	return s.DataStore.ListData(ctx, model, filter, page, size)
+}
+
+func (s *countingStore) GetDetail(ctx context.Context, model, id string, fields ...string) (map[string]interface{}, error) {
+	s.reads.Add(1)
+	return s.DataStore.GetDetail(ctx, model, id, fields...)
+}
+
+type region struct {
+	ID   string `json:"_id,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestReadThroughAndInvalidation(t *testing.T) {
+	backend := &countingStore{DataStore: local.New()}
+	repo := tcb.NewRepository[region](cache.Wrap(backend, cache.Options{TTL: time.Minute}), "regions")
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, region{Name: "西湖"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := query.New(query.Eq("name", "西湖")).Build()
+	for i := 0; i < 3; i++ {
+		if _, err := repo.List(ctx, filter, 1, 10); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := backend.reads.Load(); n != 2 {
+		t.Fatalf("backend reads = %d, want 2 (one list + one detail)", n)
+	}
+
+	if err := repo.Update(ctx, id, map[string]interface{}{"name": "西湖风景区"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "西湖风景区" {
+		t.Errorf("after update name = %q, want fresh value", got.Name)
+	}
+}
+
+func TestExcludedModelsBypassCache(t *testing.T) {
+	backend := &countingStore{DataStore: local.New()}
+	ds := cache.Wrap(backend, cache.Options{TTL: time.Minute, Exclude: map[string]bool{"admins": true}})
+	admins := tcb.NewRepository[region](ds, "admins")
+	ctx := context.Background()
+
+	id, err := admins.Create(ctx, region{Name: "o_admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := admins.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := backend.reads.Load(); n != 3 {
+		t.Errorf("backend reads = %d, want 3 (excluded model never cached)", n)
+	}
+}
+
+func TestConcurrentMissesCollapse(t *testing.T) {
+	backend := &countingStore{DataStore: local.New(), gate: make(chan struct{})}
+	repo := tcb.NewRepository[region](cache.Wrap(backend, cache.Options{}), "regions")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.List(ctx, nil, 1, 10); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // 让所有请求进入等待
+	close(backend.gate)
+	wg.Wait()
+
+	if n := backend.reads.Load(); n != 1 {
+		t.Errorf("backend reads = %d, want 1", n)
+	}
+}
+
+func TestLRUEvictionAndTTL(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(2)
+	lru.Set(ctx, "a", []byte("1"), time.Minute)
+	lru.Set(ctx, "b", []byte("2"), time.Minute)
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok := lru.Get(ctx, "b"); ok {
+		t.Error("least recently used key b should be evicted")
+	}
+	if _, ok := lru.Get(ctx, "a"); !ok {
+		t.Error("recently used key a should survive")
+	}
+
+	lru.Set(ctx, "d", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := lru.Get(ctx, "d"); ok {
+		t.Error("expired key d should miss")
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend 缓存后端 (值为 JSON 字节，便于替换为 Redis 等外部缓存)
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	// DeletePrefix 删除以 prefix 开头的全部键 (按模型失效)
+	DeletePrefix(ctx context.Context, prefix string)
+}
+
+// LRU 进程内 LRU + TTL 缓存
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+var _ Backend = (*LRU)(nil)
+
+// NewLRU 创建最多容纳 maxEntries 条的 LRU 缓存
+func NewLRU(maxEntries int) *LRU {
+	return &LRU{maxEntries: maxEntries, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *LRU) DeletePrefix(_ context.Context, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+}
+
+// Len 当前条目数
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).key)
+}
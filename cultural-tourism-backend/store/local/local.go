@@ -0,0 +1,517 @@
+// Package local 基于单个 JSON 文件的嵌入式数据存储
+//
+// 实现 store.DataStore，筛选 / 排序语义复用 tcb/match，与 CloudBase 网关保持一致。
+// 全部数据常驻内存，每次写入后整体落盘 (先写临时文件再 rename)，
+// 适合演示与本地 QA 完全离线运行，不适合生产数据量。
+//
+// 文件格式与 cmd/tcbfake 的种子文件相同: {"regions": [{...}], "pois": [...]}，
+// 缺少 _id 的记录在加载时自动补全。
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/match"
+)
+
+// ErrPersist 落盘失败 (区别于筛选条件等参数错误)
+var ErrPersist = errors.New("本地数据落盘失败")
+
+// ErrDuplicateID 写入的记录自带的 _id 已存在 (与网关一致，主键不可重复)
+var ErrDuplicateID = errors.New("记录 _id 已存在")
+
+// ErrDuplicateKey 写入的记录与已有记录的唯一字段 (见 Unique) 取值相同
+var ErrDuplicateKey = errors.New("唯一字段取值已存在")
+
+// Store 嵌入式数据存储
+type Store struct {
+	mu      sync.Mutex
+	models  map[string][]match.Record
+	uniques map[string][]string // 模型 -> 唯一字段 (model-json 中的 x-unique)
+	path    string              // 为空表示纯内存 (不落盘)
+}
+
+var _ store.DataStore = (*Store)(nil)
+
+// New 创建纯内存存储
+func New() *Store {
+	return &Store{models: make(map[string][]match.Record), uniques: make(map[string][]string)}
+}
+
+// Unique 声明模型的唯一字段 (对应 model-json 中的 x-unique)，写入时取值与已有记录相同返回 ErrDuplicateKey
+// 仅在新增时检查；字段缺失或为空的记录不参与比较
+func (s *Store) Unique(model string, fields ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uniques[model] = append(s.uniques[model], fields...)
+}
+
+// Open 打开文件存储，文件不存在时在首次写入时创建
+func Open(path string) (*Store, error) {
+	s := New()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取本地数据文件失败: %w", err)
+	}
+
+	var models map[string][]match.Record
+	if err := json.Unmarshal(data, &models); err != nil {
+		return nil, fmt.Errorf("本地数据文件格式错误: %w", err)
+	}
+	for model, records := range models {
+		for _, rec := range records {
+			s.insert(model, rec)
+		}
+	}
+	return s, nil
+}
+
+// ---------------------------------------------------------------------------
+// 底层操作 (tcbtest 假服务器同样基于这些方法)
+// ---------------------------------------------------------------------------
+
+// Insert 写入一条记录 (结构体或 map)，返回 _id (记录自带 _id 时沿用)
+func (s *Store) Insert(model string, v interface{}) (string, error) {
+	rec, err := toRecord(v)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkIDs(model, rec); err != nil {
+		return "", err
+	}
+	id := s.insert(model, rec)
+	return id, s.save()
+}
+
+// InsertMany 批量写入，任一记录无法解析时整批不写入
+func (s *Store) InsertMany(model string, values []interface{}) ([]string, error) {
+	recs := make([]match.Record, 0, len(values))
+	for i, v := range values {
+		rec, err := toRecord(v)
+		if err != nil {
+			return nil, fmt.Errorf("data[%d]: %w", i, err)
+		}
+		recs = append(recs, rec)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkIDs(model, recs...); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		ids = append(ids, s.insert(model, rec))
+	}
+	return ids, s.save()
+}
+
+// Query 按 where / orderBy 分页查询，返回当前页记录 (副本) 与命中总数
+func (s *Store) Query(model string, where map[string]interface{}, orderBy []interface{}, page, size int) ([]match.Record, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matched, err := s.find(model, where)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(orderBy) > 0 {
+		keys, err := match.ParseOrderBy(orderBy)
+		if err != nil {
+			return nil, 0, err
+		}
+		match.Sort(matched, keys)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+	start := min((page-1)*size, len(matched))
+	end := min(start+size, len(matched))
+
+	records := make([]match.Record, 0, end-start)
+	for _, rec := range matched[start:end] {
+		records = append(records, clone(rec))
+	}
+	return records, len(matched), nil
+}
+
+// Update 把 data 合并进满足 where 的记录，all=false 时只更新第一条，返回更新条数
+func (s *Store) Update(model string, where map[string]interface{}, data match.Record, all bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matched, err := s.find(model, where)
+	if err != nil {
+		return 0, err
+	}
+	if !all && len(matched) > 1 {
+		matched = matched[:1]
+	}
+	for _, rec := range matched {
+		apply(rec, data)
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+	return len(matched), s.save()
+}
+
+// Delete 删除满足 where 的记录，all=false 时只删除第一条，返回删除条数
+func (s *Store) Delete(model string, where map[string]interface{}, all bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matched, err := s.find(model, where)
+	if err != nil {
+		return 0, err
+	}
+	if !all && len(matched) > 1 {
+		matched = matched[:1]
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	drop := make(map[interface{}]bool, len(matched))
+	for _, rec := range matched {
+		drop[rec["_id"]] = true
+	}
+	kept := s.models[model][:0]
+	for _, rec := range s.models[model] {
+		if !drop[rec["_id"]] {
+			kept = append(kept, rec)
+		}
+	}
+	s.models[model] = kept
+	return len(matched), s.save()
+}
+
+// Records 返回某个模型当前的全部记录 (副本)
+func (s *Store) Records(model string) []match.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]match.Record, 0, len(s.models[model]))
+	for _, rec := range s.models[model] {
+		out = append(out, clone(rec))
+	}
+	return out
+}
+
+// Reset 清空全部数据
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.models = make(map[string][]match.Record)
+	return s.save()
+}
+
+// find 返回满足 where 的记录 (直接引用，调用方需持有锁)
+func (s *Store) find(model string, where map[string]interface{}) ([]match.Record, error) {
+	matched := make([]match.Record, 0)
+	for _, rec := range s.models[model] {
+		ok, err := match.Where(where, rec)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, rec)
+		}
+	}
+	return matched, nil
+}
+
+// checkIDs 记录自带的 _id 与唯一字段不能与已有记录或同批记录重复 (调用方需持有锁)
+func (s *Store) checkIDs(model string, recs ...match.Record) error {
+	if err := s.checkField(model, "_id", ErrDuplicateID, recs); err != nil {
+		return err
+	}
+	for _, field := range s.uniques[model] {
+		if err := s.checkField(model, field, ErrDuplicateKey, recs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) checkField(model, field string, dup error, recs []match.Record) error {
+	seen := make(map[string]bool, len(recs))
+	for _, rec := range recs {
+		v, _ := rec[field].(string)
+		if v == "" {
+			continue
+		}
+		if seen[v] {
+			return fmt.Errorf("%w: %s=%s", dup, field, v)
+		}
+		seen[v] = true
+		if found, _ := s.find(model, map[string]interface{}{field: map[string]interface{}{"$eq": v}}); len(found) > 0 {
+			return fmt.Errorf("%w: %s=%s", dup, field, v)
+		}
+	}
+	return nil
+}
+
+// insert 写入一条记录并补全系统字段 (调用方需持有锁)
+func (s *Store) insert(model string, rec match.Record) string {
+	rec = clone(rec)
+	id, _ := rec["_id"].(string)
+	if id == "" {
+		id = NewID()
+		rec["_id"] = id
+	}
+	now := time.Now().UnixMilli()
+	if _, ok := rec["createdAt"]; !ok {
+		rec["createdAt"] = now
+	}
+	if _, ok := rec["updatedAt"]; !ok {
+		rec["updatedAt"] = now
+	}
+	s.models[model] = append(s.models[model], rec)
+	return id
+}
+
+// save 整体落盘 (调用方需持有锁)
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.models, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersist, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("%w: 创建目录失败: %v", ErrPersist, err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("%w: %v", ErrPersist, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("%w: %v", ErrPersist, err)
+	}
+	return nil
+}
+
+// apply 把 data 合并进记录 (_id 不可修改)
+func apply(rec, data match.Record) {
+	for k, v := range data {
+		if k == "_id" {
+			continue
+		}
+		rec[k] = v
+	}
+	rec["updatedAt"] = time.Now().UnixMilli()
+}
+
+// ---------------------------------------------------------------------------
+// store.DataStore 实现 (响应结构与 CloudBase 网关一致)
+// ---------------------------------------------------------------------------
+
+func (s *Store) CreateData(ctx context.Context, model string, data interface{}) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	id, err := s.Insert(model, data)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	return envelope(map[string]interface{}{"id": id}), nil
+}
+
+func (s *Store) ListData(ctx context.Context, model string, filter map[string]interface{}, page, size int) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	where, orderBy, err := parseFilter(filter)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	matched, total, err := s.Query(model, where, orderBy, page, size)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+
+	sel, _ := filter["select"].(map[string]interface{})
+	records := make([]interface{}, len(matched))
+	for i, rec := range matched {
+		records[i] = match.Project(rec, sel)
+	}
+	return envelope(map[string]interface{}{"records": records, "total": total}), nil
+}
+
+func (s *Store) UpdateData(ctx context.Context, model, id string, data interface{}) error {
+	_, err := s.update(ctx, model, byID(id), data, false)
+	return err
+}
+
+func (s *Store) DeleteData(ctx context.Context, model, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err := s.Delete(model, byID(id), false)
+	return err
+}
+
+func (s *Store) GetDetail(ctx context.Context, model, id string, fields ...string) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	records, _, err := s.Query(model, byID(id), nil, 1, 1)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	if len(records) == 0 {
+		return nil, tcb.ErrNotFound
+	}
+	sel := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		sel[f] = true
+	}
+	return match.Project(records[0], sel), nil
+}
+
+func (s *Store) CreateMany(ctx context.Context, model string, data []interface{}) (*store.BatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ids, err := s.InsertMany(model, data)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	res := &store.BatchResult{Count: len(ids), Items: make([]store.BatchItem, len(ids))}
+	for i, id := range ids {
+		res.Items[i] = store.BatchItem{ID: id, OK: true}
+	}
+	return res, nil
+}
+
+func (s *Store) UpdateMany(ctx context.Context, model string, filter map[string]interface{}, data interface{}) (*store.BatchResult, error) {
+	where, _, err := parseFilter(filter)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	n, err := s.update(ctx, model, where, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return &store.BatchResult{Count: n}, nil
+}
+
+func (s *Store) DeleteMany(ctx context.Context, model string, filter map[string]interface{}) (*store.BatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	where, _, err := parseFilter(filter)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	n, err := s.Delete(model, where, true)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	return &store.BatchResult{Count: n}, nil
+}
+
+func (s *Store) update(ctx context.Context, model string, where map[string]interface{}, data interface{}, all bool) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	rec, err := toRecord(data)
+	if err != nil {
+		return 0, invalidParam(err)
+	}
+	n, err := s.Update(model, where, rec, all)
+	if err != nil {
+		return 0, invalidParam(err)
+	}
+	return n, nil
+}
+
+// parseFilter 拆出 where / orderBy，先做一次 JSON 往返使数值类型与存储一致
+func parseFilter(filter map[string]interface{}) (map[string]interface{}, []interface{}, error) {
+	if len(filter) == 0 {
+		return nil, nil, nil
+	}
+	normalized, err := toRecord(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	where, _ := normalized["where"].(map[string]interface{})
+	orderBy, _ := normalized["orderBy"].([]interface{})
+	return where, orderBy, nil
+}
+
+func byID(id string) map[string]interface{} {
+	return map[string]interface{}{"_id": map[string]interface{}{"$eq": id}}
+}
+
+func envelope(data interface{}) map[string]interface{} {
+	return map[string]interface{}{"data": data}
+}
+
+// invalidParam 与网关一致：请求参数错误以 400 INVALID_PARAM 返回，主键重复返回 409 (落盘失败原样返回)
+// 唯一字段冲突的网关错误码没有文档约定，这里按参数错误返回，业务层不能以错误码判断 (见 tcb.Repository.CreateUnique)
+func invalidParam(err error) error {
+	if errors.Is(err, ErrPersist) {
+		return err
+	}
+	if errors.Is(err, ErrDuplicateID) {
+		return &tcb.APIError{StatusCode: http.StatusConflict, Code: "DUPLICATE_KEY", Message: err.Error()}
+	}
+	return &tcb.APIError{StatusCode: http.StatusBadRequest, Code: "INVALID_PARAM", Message: err.Error()}
+}
+
+// toRecord 通过 JSON 往返把结构体 / map 转为 Record，同时把数字统一为 float64 (与网关返回一致)
+func toRecord(v interface{}) (match.Record, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var rec match.Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("data 必须是对象")
+	}
+	return rec, nil
+}
+
+// clone 深拷贝
+func clone(rec match.Record) match.Record {
+	out, err := toRecord(rec)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// NewID 生成 24 位十六进制 _id
+func NewID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,116 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"cultural-tourism-backend/store/local"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+)
+
+type region struct {
+	ID     string `json:"_id,omitempty"`
+	Name   string `json:"name"`
+	Status int    `json:"status"`
+	Sort   int    `json:"sort"`
+}
+
+func TestPersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	ctx := context.Background()
+
+	s, err := local.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := tcb.NewRepository[region](s, "regions")
+	if _, err := repo.CreateMany(ctx, []region{{Name: "西湖", Status: 1, Sort: 1}, {Name: "千岛湖", Status: 1, Sort: 2}, {Name: "下线", Status: 0}}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := repo.Create(ctx, region{Name: "良渚", Status: 1, Sort: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(ctx, id, map[string]interface{}{"name": "良渚古城"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := local.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo = tcb.NewRepository[region](reopened, "regions")
+
+	page, err := repo.List(ctx, query.New(query.Eq("status", 1)).OrderBy("sort", query.Desc).Build(), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || page.Items[0].Name != "良渚古城" {
+		t.Fatalf("page = %+v, want 3 online regions led by 良渚古城", page)
+	}
+
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, id); !errors.Is(err, tcb.ErrNotFound) {
+		t.Errorf("Get after delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInvalidFilterIsBadRequest(t *testing.T) {
+	repo := tcb.NewRepository[region](local.New(), "regions")
+	if _, err := repo.Create(context.Background(), region{Name: "西湖"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := repo.List(context.Background(), map[string]interface{}{
+		"where": map[string]interface{}{"name": map[string]interface{}{"$near": 1}},
+	}, 1, 10)
+
+	var apiErr *tcb.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want 400 APIError", err)
+	}
+}
+
+func TestDuplicateIDIsConflict(t *testing.T) {
+	repo := tcb.NewRepository[region](local.New(), "regions")
+	ctx := context.Background()
+	if _, err := repo.Create(ctx, region{ID: "r1", Name: "西湖"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, region{ID: "r1", Name: "千岛湖"}); !errors.Is(err, tcb.ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if got, _ := repo.Get(ctx, "r1"); got.Name != "西湖" {
+		t.Errorf("name = %q, existing record overwritten", got.Name)
+	}
+}
+
+func TestUniqueFieldRejectsDuplicates(t *testing.T) {
+	s := local.New()
+	s.Unique("likes", "like_key")
+	ctx := context.Background()
+	if _, err := s.Insert("likes", map[string]interface{}{"like_key": "k1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Insert("likes", map[string]interface{}{"like_key": "k1"}); !errors.Is(err, local.ErrDuplicateKey) {
+		t.Fatalf("Insert err = %v, want ErrDuplicateKey", err)
+	}
+	if _, err := s.InsertMany("likes", []interface{}{map[string]interface{}{"like_key": "k2"}, map[string]interface{}{"like_key": "k2"}}); !errors.Is(err, local.ErrDuplicateKey) {
+		t.Fatalf("InsertMany err = %v, want ErrDuplicateKey", err)
+	}
+	// 未声明唯一的模型与缺失的字段不受影响
+	if _, err := s.Insert("likes", map[string]interface{}{"target_id": "p1"}); err != nil {
+		t.Errorf("record without key: %v", err)
+	}
+	if _, err := s.CreateData(ctx, "favorites", map[string]interface{}{"like_key": "k1"}); err != nil {
+		t.Errorf("other model: %v", err)
+	}
+	if n, _ := tcb.NewRepository[region](s, "likes").Count(ctx, nil); n != 2 {
+		t.Errorf("likes = %d, want 2", n)
+	}
+}
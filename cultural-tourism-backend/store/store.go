@@ -0,0 +1,61 @@
+// Package store 定义数据存储后端接口
+//
+// 线上使用 tcb.CloudBaseClient (CloudBase 数据模型 HTTP API)，
+// 离线演示 / 本地 QA 使用 store/local (单个 JSON 文件)。
+// 所有实现遵循同一套 filter 语义 ({"where": ..., "orderBy": ..., "select": ...})
+// 与同样的响应结构 ({"data": {"records": [...], "total": n}} 等)，
+// 上层 tcb.Repository 与业务代码无需区分后端。
+package store
+
+import "context"
+
+// DataStore 数据存储后端
+type DataStore interface {
+	// CreateData 新增记录，返回 {"data": {"id": "..."}}
+	CreateData(ctx context.Context, model string, data interface{}) (map[string]interface{}, error)
+	// ListData 分页查询，返回 {"data": {"records": [...], "total": n}}
+	ListData(ctx context.Context, model string, filter map[string]interface{}, page, size int) (map[string]interface{}, error)
+	// UpdateData 按 _id 更新
+	UpdateData(ctx context.Context, model, id string, data interface{}) error
+	// DeleteData 按 _id 删除
+	DeleteData(ctx context.Context, model, id string) error
+	// GetDetail 按 _id 获取单条记录，fields 非空时只返回这些字段 (及 _id)；不存在时返回 tcb.ErrNotFound
+	GetDetail(ctx context.Context, model, id string, fields ...string) (map[string]interface{}, error)
+
+	// CreateMany 批量新增，Items 与 data 按下标一一对应
+	CreateMany(ctx context.Context, model string, data []interface{}) (*BatchResult, error)
+	// UpdateMany 按 filter 批量更新
+	UpdateMany(ctx context.Context, model string, filter map[string]interface{}, data interface{}) (*BatchResult, error)
+	// DeleteMany 按 filter 批量删除
+	DeleteMany(ctx context.Context, model string, filter map[string]interface{}) (*BatchResult, error)
+}
+
+// Default 全局数据存储 (启动时按配置选择)
+var Default DataStore
+
+// BatchItem 批量操作中单条记录的结果
+type BatchItem struct {
+	ID    string `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// BatchResult 批量操作结果
+// Count 为后端确认写入/更新/删除的条数；
+// Items 仅在能够逐条确认时填充 (CreateMany、tcb.Repository.UpdateByIDs)，
+// 按 filter 的 UpdateMany / DeleteMany 只返回总数。
+type BatchResult struct {
+	Count int         `json:"count"`
+	Items []BatchItem `json:"items,omitempty"`
+}
+
+// Failed 返回失败的条目
+func (r *BatchResult) Failed() []BatchItem {
+	var failed []BatchItem
+	for _, it := range r.Items {
+		if !it.OK {
+			failed = append(failed, it)
+		}
+	}
+	return failed
+}
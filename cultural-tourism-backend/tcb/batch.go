@@ -0,0 +1,104 @@
+package tcb
+
+import (
+	"context"
+	"fmt"
+
+	"cultural-tourism-backend/store"
+)
+
+// BatchItem / BatchResult 定义在 store 包，这里保留别名方便调用方使用
+type (
+	BatchItem   = store.BatchItem
+	BatchResult = store.BatchResult
+)
+
+// createManyEnvelope createMany 接口的响应结构
+type createManyEnvelope struct {
+	Data *struct {
+		IDList []string `json:"idList"`
+	} `json:"data"`
+}
+
+// countEnvelope updateMany / deleteMany 接口的响应结构
+type countEnvelope struct {
+	Data *struct {
+		Count *int `json:"count"`
+	} `json:"data"`
+}
+
+// CreateMany 批量新增
+// API: POST /v1/model/{stage}/{modelName}/createMany
+// Items 与 data 按下标一一对应，网关未返回 _id 的条目标记为失败
+func (c *CloudBaseClient) CreateMany(ctx context.Context, modelName string, data []interface{}) (*BatchResult, error) {
+	path := c.modelPath(modelName, "createMany")
+	payload := map[string]interface{}{
+		"data": data,
+	}
+
+	result, err := c.call(ctx, opWrite, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var env createManyEnvelope
+	if err := remarshal(result, &env); err != nil {
+		return nil, fmt.Errorf("[%s] %w: 解析批量创建响应失败: %v", modelName, ErrUnexpectedResponse, err)
+	}
+	if env.Data == nil || env.Data.IDList == nil {
+		return nil, fmt.Errorf("[%s] %w: 缺少 data.idList", modelName, ErrUnexpectedResponse)
+	}
+
+	res := &BatchResult{Items: make([]BatchItem, len(data))}
+	for i := range data {
+		if i < len(env.Data.IDList) && env.Data.IDList[i] != "" {
+			res.Items[i] = BatchItem{ID: env.Data.IDList[i], OK: true}
+			res.Count++
+			continue
+		}
+		res.Items[i] = BatchItem{Error: "网关未返回 _id"}
+	}
+	return res, nil
+}
+
+// UpdateMany 按 filter 批量更新 (filter 语义与 ListData 一致，仅 where 生效)
+// API: PUT /v1/model/{stage}/{modelName}/updateMany
+func (c *CloudBaseClient) UpdateMany(ctx context.Context, modelName string, filter map[string]interface{}, data interface{}) (*BatchResult, error) {
+	path := c.modelPath(modelName, "updateMany")
+	payload := map[string]interface{}{
+		"filter": filter,
+		"data":   data,
+	}
+
+	result, err := c.call(ctx, opWrite, "PUT", path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return parseCount(modelName, result)
+}
+
+// DeleteMany 按 filter 批量删除
+// API: POST /v1/model/{stage}/{modelName}/deleteMany
+func (c *CloudBaseClient) DeleteMany(ctx context.Context, modelName string, filter map[string]interface{}) (*BatchResult, error) {
+	path := c.modelPath(modelName, "deleteMany")
+	payload := map[string]interface{}{
+		"filter": filter,
+	}
+
+	result, err := c.call(ctx, opDelete, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return parseCount(modelName, result)
+}
+
+func parseCount(modelName string, result map[string]interface{}) (*BatchResult, error) {
+	var env countEnvelope
+	if err := remarshal(result, &env); err != nil {
+		return nil, fmt.Errorf("[%s] %w: 解析批量响应失败: %v", modelName, ErrUnexpectedResponse, err)
+	}
+	if env.Data == nil || env.Data.Count == nil {
+		return nil, fmt.Errorf("[%s] %w: 缺少 data.count", modelName, ErrUnexpectedResponse)
+	}
+	return &BatchResult{Count: *env.Data.Count}, nil
+}
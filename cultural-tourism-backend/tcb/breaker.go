@@ -0,0 +1,120 @@
+package tcb
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器打开期间直接拒绝请求，避免在已宕机的网关上排队等待
+var ErrCircuitOpen = errors.New("云开发网关熔断中，请稍后再试")
+
+// BreakerState 熔断器状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 正常放行
+	BreakerOpen                         // 快速失败
+	BreakerHalfOpen                     // 冷却结束，放行单个探测请求
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker 连续失败计数熔断器
+// 连续 FailureThreshold 次失败后打开，Cooldown 之后进入半开状态放行一个探测请求：
+// 探测成功则关闭，失败则重新打开。
+type CircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker 创建熔断器，threshold 必须大于 0 (不启用熔断时 CloudBaseClient.Breaker 置空)
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: threshold, Cooldown: cooldown}
+}
+
+// Allow 判断当前请求是否放行
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.transition(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Success 记录一次成功
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != BreakerClosed {
+		b.transition(BreakerClosed)
+	}
+}
+
+// Failure 记录一次失败
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.openedAt = time.Now()
+		if b.state != BreakerOpen {
+			b.transition(BreakerOpen)
+		}
+	}
+}
+
+// Release 放弃本次放行 (调用方取消、请求未到达网关等)：不计成功或失败，半开状态下允许下一个探测请求
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State 当前状态
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// transition 切换状态并记录日志 (调用方需持有锁)
+func (b *CircuitBreaker) transition(to BreakerState) {
+	log.Printf("⚡ 云开发熔断器状态: %s -> %s (连续失败 %d 次)", b.state, to, b.failures)
+	b.state = to
+}
@@ -0,0 +1,121 @@
+package tcb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func expectState(t *testing.T, b *CircuitBreaker, want BreakerState) {
+	t.Helper()
+	if got := b.State(); got != want {
+		t.Fatalf("state = %s, want %s", got, want)
+	}
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b := NewCircuitBreaker(3, 20*time.Millisecond)
+
+	// 成功会清零连续失败计数
+	b.Failure()
+	b.Failure()
+	b.Success()
+	b.Failure()
+	b.Failure()
+	expectState(t, b, BreakerClosed)
+
+	// 连续失败达到阈值后打开，冷却期内拒绝
+	b.Failure()
+	expectState(t, b, BreakerOpen)
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow during cooldown = %v, want ErrCircuitOpen", err)
+	}
+
+	// 冷却结束进入半开，只放行一个探测请求；探测失败重新打开
+	time.Sleep(25 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	expectState(t, b, BreakerHalfOpen)
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe = %v, want ErrCircuitOpen", err)
+	}
+	b.Failure()
+	expectState(t, b, BreakerOpen)
+
+	// 放弃的探测不改变状态，下一个请求可以继续探测；探测成功则关闭
+	time.Sleep(25 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	b.Release()
+	expectState(t, b, BreakerHalfOpen)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after release rejected: %v", err)
+	}
+	b.Success()
+	expectState(t, b, BreakerClosed)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("closed breaker rejected: %v", err)
+	}
+}
+
+func TestBreakerCountsGatewayOutcomesOnly(t *testing.T) {
+	ctx := context.Background()
+	g := &gateway{respond: statuses(503, 400, 503, 503)}
+	c := newTestClient(t, g)
+	c.Retry = RetryPolicy{}
+	c.Breaker = NewCircuitBreaker(2, time.Hour)
+
+	// 4xx 说明网关可用，清零 5xx 计数
+	for range 3 {
+		c.ListData(ctx, "items", nil, 1, 10)
+	}
+	expectState(t, c.Breaker, BreakerClosed)
+
+	// 本地错误 (令牌获取失败) 不计入失败
+	c.Tokens = failingTokens{}
+	for range 3 {
+		c.ListData(ctx, "items", nil, 1, 10)
+	}
+	expectState(t, c.Breaker, BreakerClosed)
+
+	c.Tokens = StaticTokenSource("token")
+	c.ListData(ctx, "items", nil, 1, 10)
+	expectState(t, c.Breaker, BreakerOpen)
+	if _, err := c.ListData(ctx, "items", nil, 1, 10); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if stats := c.Stats(); stats.BreakerRejections != 1 || stats.BreakerState != "open" {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestCancelledProbeKeepsBreakerHalfOpen(t *testing.T) {
+	g := &gateway{respond: func(hit int, w http.ResponseWriter, r *http.Request) {
+		if hit == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		statuses()(hit, w, r)
+	}}
+	c := newTestClient(t, g)
+	c.Retry = RetryPolicy{}
+	c.Breaker = NewCircuitBreaker(1, 10*time.Millisecond)
+	c.Breaker.Failure()
+	time.Sleep(15 * time.Millisecond)
+
+	// 探测请求被调用方取消：既不关闭也不重新打开，且不占用探测名额
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListData(ctx, "items", nil, 1, 10); err == nil {
+		t.Fatal("cancelled probe: want error")
+	}
+	expectState(t, c.Breaker, BreakerHalfOpen)
+
+	if _, err := c.ListData(context.Background(), "items", nil, 1, 10); err != nil {
+		t.Fatalf("next probe: %v", err)
+	}
+	expectState(t, c.Breaker, BreakerClosed)
+}
@@ -1,61 +1,252 @@
-// File: tcb/client.go
 package tcb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
-	"os"
+	"time"
 
-	"github.com/joho/godotenv"
+	"cultural-tourism-backend/config"
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb/query"
 )
 
 // Global Client Instance
 var Client *CloudBaseClient
 
+// CloudBaseClient 是 store.DataStore 的线上实现
+var _ store.DataStore = (*CloudBaseClient)(nil)
+
+// 默认超时：读操作 (list/detail) 与写操作 (create/update/delete) 分开配置
+const (
+	DefaultReadTimeout  = 5 * time.Second
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// Timeouts 按操作类型区分的默认截止时间
+// 调用方 ctx 自带更早的 deadline 时以调用方为准
+type Timeouts struct {
+	Read  time.Duration // ListData / GetDetail
+	Write time.Duration // CreateData / UpdateData / DeleteData
+}
+
+// opKind 操作类型，用于选择默认超时及是否允许重试
+type opKind int
+
+const (
+	opRead   opKind = iota // list / detail，幂等
+	opWrite                // create / update，不重试
+	opDelete               // 按 _id 删除，幂等
+)
+
+func (k opKind) idempotent() bool {
+	return k == opRead || k == opDelete
+}
+
 type CloudBaseClient struct {
-	EnvID       string
-	AccessToken string
-	BaseURL     string
-	HTTPClient  *http.Client
+	EnvID      string
+	Stage      string      // 数据模型阶段 StageProd / StagePre，为空时按 StageProd
+	Tokens     TokenSource // 网关访问令牌来源
+	BaseURL    string
+	HTTPClient *http.Client
+	Timeouts   Timeouts
+	Retry      RetryPolicy     // 仅对幂等操作生效
+	Breaker    *CircuitBreaker // nil 表示不启用熔断
+
+	stats clientStats
 }
 
-// Init 初始化全局客户端
-func Init() {
-	_ = godotenv.Load() // 加载 .env (本地开发用)
+// Init 按配置初始化全局客户端
+// 运行环境 (环境 ID / 数据模型阶段 / 网关) 由 cfg.Profile 选择，见 ResolveProfile
+func Init(cfg config.TCB) {
+	profile, err := ResolveProfile(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("配置错误: %v", err))
+	}
+	if cfg.Profile == "" {
+		fmt.Printf("⚠️ 未配置 APP_PROFILE，默认使用 %s 环境\n", DefaultProfile)
+	}
+	fmt.Println(profile.Banner())
+	baseURL := profile.GatewayURL()
 
-	envID := os.Getenv("CLOUDBASE_ENV_ID")
-	accessToken := os.Getenv("CLOUDBASE_ACCESS_TOKEN")
-	useInternalAPI := os.Getenv("USE_INTERNAL_API") // 是否使用内网 API
+	httpClient := &http.Client{}
 
-	if envID == "" {
-		fmt.Println("⚠️ 警告: 未找到 CLOUDBASE_ENV_ID 环境变量")
+	Client = &CloudBaseClient{
+		EnvID:      profile.EnvID,
+		Stage:      profile.Stage,
+		Tokens:     tokenSourceFromConfig(cfg, baseURL, httpClient),
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+		Timeouts: Timeouts{
+			Read:  cfg.ReadTimeout,
+			Write: cfg.WriteTimeout,
+		},
+		Retry: RetryPolicy{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay,
+			MaxDelay:    cfg.RetryMaxDelay,
+		},
+	}
+	if cfg.BreakerThreshold > 0 {
+		Client.Breaker = NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown)
 	}
+}
 
-	// 根据环境变量选择内网或外网 API
-	var baseURL string
-	if useInternalAPI == "true" {
-		// 云托管内网访问数据模型 API (内网与外网使用相同域名)
-		baseURL = fmt.Sprintf("https://%s.api.tcloudbasegateway.com", envID)
-		fmt.Println("✅ 云开发 HTTP 客户端已初始化 (内网模式 - 数据模型 API)")
+// tokenSourceFromConfig 按配置选择令牌来源
+// 优先级: APIKey > SecretID/SecretKey > AccessToken (静态令牌，过期需重新部署)
+func tokenSourceFromConfig(cfg config.TCB, baseURL string, httpClient *http.Client) TokenSource {
+	if cfg.APIKey != "" {
+		fmt.Println("🔑 云开发鉴权: API Key")
+		return StaticTokenSource(cfg.APIKey)
+	}
+
+	if cfg.SecretID != "" && cfg.SecretKey != "" {
+		fmt.Println("🔑 云开发鉴权: SecretId/SecretKey 自动换取令牌")
+		src := NewCachedTokenSource(&ClientCredentialsSource{
+			TokenURL:   baseURL + "/auth/v1/token",
+			SecretID:   cfg.SecretID,
+			SecretKey:  cfg.SecretKey,
+			HTTPClient: httpClient,
+		})
+		src.RefreshAhead = cfg.TokenRefreshAhead
+		return src
+	}
+
+	if cfg.AccessToken == "" {
+		fmt.Println("⚠️ 警告: 未配置云开发凭证 (CLOUDBASE_API_KEY 或 CLOUDBASE_SECRET_ID/CLOUDBASE_SECRET_KEY)")
 	} else {
-		// 外网访问数据模型 API (本地开发用)
-		baseURL = fmt.Sprintf("https://%s.api.tcloudbasegateway.com", envID)
-		fmt.Println("✅ 云开发 HTTP 客户端已初始化 (外网模式 - 数据模型 API)")
+		fmt.Println("⚠️ 云开发鉴权: 静态 CLOUDBASE_ACCESS_TOKEN，过期后需重新部署")
 	}
+	return StaticTokenSource(cfg.AccessToken)
+}
 
-	Client = &CloudBaseClient{
-		EnvID:       envID,
-		AccessToken: accessToken,
-		BaseURL:     baseURL,
-		HTTPClient:  &http.Client{},
+// modelPath 数据模型接口路径 /v1/model/{stage}/{modelName}/{action}
+func (c *CloudBaseClient) modelPath(modelName, action string) string {
+	stage := c.Stage
+	if stage == "" {
+		stage = StageProd
+	}
+	return fmt.Sprintf("/v1/model/%s/%s/%s", stage, modelName, action)
+}
+
+// withTimeout 为单次尝试附加默认截止时间
+func (c *CloudBaseClient) withTimeout(ctx context.Context, op opKind) (context.Context, context.CancelFunc) {
+	timeout := c.Timeouts.Read
+	if op != opRead {
+		timeout = c.Timeouts.Write
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Invoke 调用数据模型以外的网关接口 (如云存储)，复用鉴权、超时、熔断与重试
+// idempotent 为 true 时按读操作处理 (读超时，可重试)，否则按写操作处理；返回解码后的 JSON (对象或数组)
+func (c *CloudBaseClient) Invoke(ctx context.Context, idempotent bool, method, path string, body interface{}) (interface{}, error) {
+	op := opWrite
+	if idempotent {
+		op = opRead
+	}
+	return c.invoke(ctx, op, method, path, body)
+}
+
+// call 数据模型接口调用，响应必须是 JSON 对象
+func (c *CloudBaseClient) call(ctx context.Context, op opKind, method, path string, body interface{}) (map[string]interface{}, error) {
+	result, err := c.invoke(ctx, op, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return resultMap, nil
 	}
+	return nil, ErrUnexpectedResponse
+}
+
+// invoke 按操作类型施加超时、熔断与重试后发起请求
+// 只有幂等操作 (opRead / opDelete) 会在可重试错误上重试
+func (c *CloudBaseClient) invoke(ctx context.Context, op opKind, method, path string, body interface{}) (interface{}, error) {
+	attempts := 1
+	if op.idempotent() && c.Retry.MaxAttempts > 1 {
+		attempts = c.Retry.MaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			c.stats.retries.Add(1)
+			log.Printf("↻ 重试 %s %s (第 %d 次): %v", method, path, attempt, lastErr)
+			if err := sleepCtx(ctx, c.Retry.backoff(attempt)); err != nil {
+				break
+			}
+		}
+
+		if c.Breaker != nil {
+			if err := c.Breaker.Allow(); err != nil {
+				c.stats.breakerRejections.Add(1)
+				lastErr = err
+				break
+			}
+		}
+
+		result, err := c.attempt(ctx, op, method, path, body)
+		if err == nil {
+			if c.Breaker != nil {
+				c.Breaker.Success()
+			}
+			return result, nil
+		}
+
+		lastErr = err
+		if c.Breaker != nil {
+			switch gatewayOutcome(ctx, err) {
+			case outcomeFailure:
+				c.Breaker.Failure()
+			case outcomeHealthy:
+				c.Breaker.Success()
+			default:
+				c.Breaker.Release()
+			}
+		}
+		if !isRetryable(ctx, err) {
+			break
+		}
+	}
+
+	c.stats.failures.Add(1)
+	return nil, lastErr
+}
+
+// attempt 单次请求 (独立超时)
+func (c *CloudBaseClient) attempt(ctx context.Context, op opKind, method, path string, body interface{}) (interface{}, error) {
+	ctx, cancel := c.withTimeout(ctx, op)
+	defer cancel()
+
+	c.stats.requests.Add(1)
+	result, err := c.Request(ctx, method, path, body, nil)
+	if isExpiredToken(err) {
+		// 令牌被提前吊销或时钟偏差：丢弃缓存重新换取，只重试一次
+		// 401 说明请求未被执行，写操作重放也是安全的
+		if inv, ok := c.Tokens.(invalidator); ok {
+			inv.Invalidate()
+			log.Printf("↻ 令牌失效，重新获取后重试 %s %s", method, path)
+			result, err = c.Request(ctx, method, path, body, nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Request 通用 HTTP 请求处理
-func (c *CloudBaseClient) Request(method, path string, body interface{}, customHeaders map[string]string) (interface{}, error) {
+// ctx 取消 (如客户端断开) 时上游请求随之中止
+func (c *CloudBaseClient) Request(ctx context.Context, method, path string, body interface{}, customHeaders map[string]string) (interface{}, error) {
 	url := c.BaseURL + path
 	var reqBody io.Reader
 
@@ -67,64 +258,66 @@ func (c *CloudBaseClient) Request(method, path string, body interface{}, customH
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	// 鉴权核心：云托管内网或本地调试通过 Token 访问
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	if c.Tokens != nil {
+		tok, err := c.Tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+tok.Value)
+	}
+	for k, v := range customHeaders {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return nil, &transportError{op: "请求失败", err: err}
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, &transportError{op: "读取响应失败", err: err}
 	}
 
-	// 错误处理：非 2xx 视为错误
+	// 错误处理：非 2xx 统一转换为 *APIError
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API错误 [%d]: %s", resp.StatusCode, string(bodyBytes))
+		return nil, newAPIError(resp, bodyBytes)
 	}
 
 	var result interface{}
 	if len(bodyBytes) > 0 {
 		if err := json.Unmarshal(bodyBytes, &result); err != nil {
-			return nil, fmt.Errorf("JSON解析失败: %w", err)
+			return nil, fmt.Errorf("%w: JSON解析失败: %v", ErrUnexpectedResponse, err)
 		}
 	}
 	return result, nil
 }
 
 // CreateData 新增数据
-// API: POST /v1/model/prod/{modelName}/create
-func (c *CloudBaseClient) CreateData(modelName string, data interface{}) (map[string]interface{}, error) {
-	path := fmt.Sprintf("/v1/model/prod/%s/create", modelName)
+// API: POST /v1/model/{stage}/{modelName}/create
+func (c *CloudBaseClient) CreateData(ctx context.Context, modelName string, data interface{}) (map[string]interface{}, error) {
+	path := c.modelPath(modelName, "create")
 	payload := map[string]interface{}{
 		"data": data,
 	}
 
-	result, err := c.Request("POST", path, payload, nil)
-	if err != nil {
-		return nil, err
-	}
-	if resultMap, ok := result.(map[string]interface{}); ok {
-		return resultMap, nil
-	}
-	return nil, fmt.Errorf("返回格式异常")
+	return c.call(ctx, opWrite, "POST", path, payload)
 }
 
 // ListData 查询列表 (核心修正版)
 // ⚠️ 严禁在此处硬编码 $eq 等逻辑。
-// filter 参数必须由 Controller 层构造成完整的 TCB 查询对象 (包含 where, orderBy 等)
-// API: POST /v1/model/prod/{modelName}/list
-func (c *CloudBaseClient) ListData(modelName string, filter map[string]interface{}, page, size int) (map[string]interface{}, error) {
-	path := fmt.Sprintf("/v1/model/prod/%s/list", modelName)
+// filter 参数必须由调用方构造成完整的 TCB 查询对象 (包含 where, orderBy 等)，推荐使用 tcb/query 构造
+// API: POST /v1/model/{stage}/{modelName}/list
+func (c *CloudBaseClient) ListData(ctx context.Context, modelName string, filter map[string]interface{}, page, size int) (map[string]interface{}, error) {
+	path := c.modelPath(modelName, "list")
 
 	payload := map[string]interface{}{
 		"pageNumber": page,
@@ -133,83 +326,83 @@ func (c *CloudBaseClient) ListData(modelName string, filter map[string]interface
 	}
 
 	// [Audit Fix]: 仅仅透传 filter，不做任何假设或加工
-	// 调用方 (Controller) 负责构造 { "where": {...}, "orderBy": [...] }
-	if filter != nil && len(filter) > 0 {
-		payload["filter"] = filter
+	// 调用方负责构造 { "where": {...}, "orderBy": [...], "select": {...} }
+	// 按 HTTP API 协议，orderBy / select 与 filter 同级，这里只做位置拆分
+	if len(filter) > 0 {
+		rest := make(map[string]interface{}, len(filter))
+		for k, v := range filter {
+			switch k {
+			case "orderBy", "select":
+				payload[k] = v
+			default:
+				rest[k] = v
+			}
+		}
+		if len(rest) > 0 {
+			payload["filter"] = rest
+		}
 	}
 
-	result, err := c.Request("POST", path, payload, nil)
-	if err != nil {
-		return nil, err
-	}
-	if resultMap, ok := result.(map[string]interface{}); ok {
-		return resultMap, nil
-	}
-	return nil, fmt.Errorf("返回格式异常")
+	return c.call(ctx, opRead, "POST", path, payload)
 }
 
 // UpdateData 更新数据
 // [Audit Fix]: 使用 PUT 方法 + /update 路径，并正确构造 filter
-// API: PUT /v1/model/prod/{modelName}/update
-func (c *CloudBaseClient) UpdateData(modelName, id string, data interface{}) error {
-	path := fmt.Sprintf("/v1/model/prod/%s/update", modelName)
+// API: PUT /v1/model/{stage}/{modelName}/update
+func (c *CloudBaseClient) UpdateData(ctx context.Context, modelName, id string, data interface{}) error {
+	path := c.modelPath(modelName, "update")
 
 	payload := map[string]interface{}{
-		"filter": map[string]interface{}{
-			"where": map[string]interface{}{
-				"_id": map[string]interface{}{
-					"$eq": id,
-				},
-			},
-		},
-		"data": data,
+		"filter": byID(id),
+		"data":   data,
 	}
 
-	_, err := c.Request("PUT", path, payload, nil)
+	_, err := c.call(ctx, opWrite, "PUT", path, payload)
 	return err
 }
 
 // DeleteData 删除数据
-// API: POST /v1/model/prod/{modelName}/delete
-func (c *CloudBaseClient) DeleteData(modelName, id string) error {
-	path := fmt.Sprintf("/v1/model/prod/%s/delete", modelName)
+// API: POST /v1/model/{stage}/{modelName}/delete
+func (c *CloudBaseClient) DeleteData(ctx context.Context, modelName, id string) error {
+	path := c.modelPath(modelName, "delete")
 
 	payload := map[string]interface{}{
-		"filter": map[string]interface{}{
-			"where": map[string]interface{}{
-				"_id": map[string]interface{}{
-					"$eq": id,
-				},
-			},
-		},
+		"filter": byID(id),
 	}
 
-	_, err := c.Request("POST", path, payload, nil)
+	_, err := c.call(ctx, opDelete, "POST", path, payload)
 	return err
 }
 
 // GetDetail 获取单条详情
 // 复用 list 接口，查询 _id
-func (c *CloudBaseClient) GetDetail(modelName, id string) (map[string]interface{}, error) {
-	// 构造标准 filter
-	filter := map[string]interface{}{
-		"where": map[string]interface{}{
-			"_id": map[string]interface{}{
-				"$eq": id,
-			},
-		},
-	}
-
-	// 复用 ListData 逻辑
-	result, err := c.ListData(modelName, filter, 1, 1)
+func (c *CloudBaseClient) GetDetail(ctx context.Context, modelName, id string, fields ...string) (map[string]interface{}, error) {
+	// 复用 ListData 逻辑 (fields 通过 select 投影)
+	filter := query.New(query.Eq("_id", id)).Select(fields...).Build()
+	result, err := c.ListData(ctx, modelName, filter, 1, 1)
 	if err != nil {
 		return nil, err
 	}
 
 	if dataMap, ok := result["data"].(map[string]interface{}); ok {
 		if records, ok := dataMap["records"].([]interface{}); ok && len(records) > 0 {
-			return records[0].(map[string]interface{}), nil
+			record, ok := records[0].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("[%s] %w: 记录 %s 不是对象", modelName, ErrUnexpectedResponse, id)
+			}
+			return record, nil
 		}
 	}
-	return nil, fmt.Errorf("未找到记录")
+	return nil, ErrNotFound
+}
+
+// isExpiredToken 网关因令牌无效返回 401 (403 为权限不足，换令牌无意义)
+func isExpiredToken(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// byID 按主键定位单条记录的 filter
+func byID(id string) map[string]interface{} {
+	return query.New(query.Eq("_id", id)).Build()
 }
@@ -0,0 +1,65 @@
+package tcb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// slowGateway 每个请求延迟 delay 后返回空列表 / 创建成功
+func slowGateway(delay time.Duration) *gateway {
+	return &gateway{respond: func(hit int, w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte(`{"data":{"records":[],"total":0,"id":"new-id"}}`))
+	}}
+}
+
+func TestPerOperationTimeouts(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, slowGateway(100*time.Millisecond))
+	c.Retry = RetryPolicy{}
+	c.Timeouts = Timeouts{Read: 20 * time.Millisecond, Write: time.Second}
+
+	// 读操作使用读超时
+	start := time.Now()
+	if _, err := c.ListData(ctx, "items", nil, 1, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("read: err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("read took %v, want about the 20ms read timeout", elapsed)
+	}
+
+	// 写操作使用写超时，慢响应仍能完成
+	if _, err := c.CreateData(ctx, "items", map[string]interface{}{"name": "x"}); err != nil {
+		t.Errorf("write: %v", err)
+	}
+
+	// 调用方更早的 deadline 优先
+	c.Timeouts.Read = time.Second
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, err := c.ListData(cctx, "items", nil, 1, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("caller deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("caller deadline: took %v", elapsed)
+	}
+
+	// 调用方取消时上游请求随之中止
+	cctx, cancel = context.WithCancel(ctx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := c.ListData(cctx, "items", nil, 1, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v, want Canceled", err)
+	}
+}
+
+func TestZeroTimeoutMeansNoDefault(t *testing.T) {
+	c := newTestClient(t, slowGateway(30*time.Millisecond))
+	c.Timeouts = Timeouts{}
+	if _, err := c.ListData(context.Background(), "items", nil, 1, 10); err != nil {
+		t.Errorf("ListData without default timeout: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package tcb
+
+import (
+	"context"
+	"time"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// versionLayout updated_at 的格式：定宽纳秒精度的 RFC 3339
+// 同一秒内的多次修改也能得到不同的版本，且不会与旧的秒级时间戳相等
+const versionLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Timestamp 当前时间，作为 updated_at 写入 (即条件更新比较的版本)
+func Timestamp() string {
+	return time.Now().Format(versionLayout)
+}
+
+// UpdateIf 条件更新 (乐观并发控制)
+// 仅当存储中的 updated_at 仍等于 expectedUpdatedAt 时更新 (data 应包含由 Timestamp 生成的新 updated_at)。
+// 条件不满足时返回 *PreconditionError (附当前记录)，记录不存在时返回 ErrNotFound。
+// 判断与写入在同一次 updateMany 中完成，不存在先读后写的竞争窗口。
+func (c *CloudBaseClient) UpdateIf(ctx context.Context, modelName, id, expectedUpdatedAt string, data interface{}) error {
+	return UpdateIf(ctx, c, modelName, id, expectedUpdatedAt, data)
+}
+
+// UpdateIf 基于任意 store.DataStore 的条件更新，见 CloudBaseClient.UpdateIf
+func UpdateIf(ctx context.Context, ds store.DataStore, modelName, id, expectedUpdatedAt string, data interface{}) error {
+	filter := query.New(
+		query.Eq("_id", id),
+		query.Eq("updated_at", expectedUpdatedAt),
+	).Build()
+
+	res, err := ds.UpdateMany(ctx, modelName, filter, data)
+	if err != nil {
+		return err
+	}
+	if res.Count > 0 {
+		return nil
+	}
+
+	current, err := ds.GetDetail(ctx, modelName, id)
+	if err != nil {
+		return err
+	}
+	return &PreconditionError{Current: current}
+}
+
+// UpdateIf 条件更新，expectedUpdatedAt 为空时等同 Update
+func (r *Repository[T]) UpdateIf(ctx context.Context, id, expectedUpdatedAt string, data interface{}) error {
+	if expectedUpdatedAt == "" {
+		return r.Update(ctx, id, data)
+	}
+	return UpdateIf(ctx, r.ds, r.model, id, expectedUpdatedAt, data)
+}
@@ -0,0 +1,83 @@
+package tcb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// incrementRetry CAS 冲突时的退避策略 (冲突说明有并发写入，短暂错开即可)
+var incrementRetry = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   5 * time.Millisecond,
+	MaxDelay:    200 * time.Millisecond,
+}
+
+// Increment 原子地给数值字段加上 delta，返回更新后的值
+// 数据模型 HTTP API 的 update 不支持 $inc，这里以 "读取当前值 → 以当前值为条件 updateMany" 的
+// CAS 循环实现：条件不满足 (期间有其他写入) 时重新读取并重试，超过次数返回 ErrConflict。
+func (c *CloudBaseClient) Increment(ctx context.Context, modelName, id, field string, delta int) (int, error) {
+	return Increment(ctx, c, modelName, id, field, delta)
+}
+
+// Increment 基于任意 store.DataStore 的原子自增，见 CloudBaseClient.Increment
+func Increment(ctx context.Context, ds store.DataStore, modelName, id, field string, delta int) (int, error) {
+	for attempt := range incrementRetry.MaxAttempts {
+		if attempt > 0 {
+			if err := sleepCtx(ctx, incrementRetry.backoff(attempt)); err != nil {
+				return 0, err
+			}
+		}
+		rec, err := ds.GetDetail(ctx, modelName, id, field)
+		if err != nil {
+			return 0, err
+		}
+		current, ok := intValue(rec[field])
+		if !ok {
+			return 0, fmt.Errorf("%w: %s.%s=%v 不是整数", ErrUnexpectedResponse, modelName, field, rec[field])
+		}
+
+		cond := query.Eq(field, current)
+		if rec[field] == nil {
+			// 字段缺失时以 null 为条件，与读到的状态保持一致
+			cond = query.Cond{field: map[string]interface{}{"$eq": nil}}
+		}
+		filter := query.New(query.Eq("_id", id), cond).Build()
+		res, err := ds.UpdateMany(ctx, modelName, filter, map[string]interface{}{field: current + delta})
+		if err != nil {
+			return 0, err
+		}
+		if res.Count > 0 {
+			return current + delta, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s.%s 并发更新过于频繁", ErrConflict, modelName, field)
+}
+
+// Increment 原子自增，见 tcb.Increment
+func (r *Repository[T]) Increment(ctx context.Context, id, field string, delta int) (int, error) {
+	return Increment(ctx, r.ds, r.model, id, field, delta)
+}
+
+// intValue 解析 JSON 解码后的数值，字段缺失视为 0
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case nil:
+		return 0, true
+	case float64:
+		if n != float64(int(n)) {
+			return 0, false
+		}
+		return int(n), true
+	case int:
+		return n, true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
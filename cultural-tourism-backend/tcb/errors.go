@@ -0,0 +1,102 @@
+package tcb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 哨兵错误：业务层通过 errors.Is 判断，不再依赖错误字符串
+var (
+	ErrNotFound           = errors.New("未找到记录")
+	ErrConflict           = errors.New("数据冲突")
+	ErrRateLimited        = errors.New("请求过于频繁")
+	ErrUnauthorized       = errors.New("云开发鉴权失败")
+	ErrUnexpectedResponse = errors.New("返回格式异常")
+	ErrPreconditionFailed = errors.New("记录已被修改")
+)
+
+// ErrDuplicateKey 唯一字段 (x-unique) 取值已存在，见 Repository.CreateUnique
+var ErrDuplicateKey = fmt.Errorf("%w: 唯一字段取值已存在", ErrConflict)
+
+// PreconditionError 条件更新失败 (updated_at 已变化)，附带当前记录供前端合并
+type PreconditionError struct {
+	Current map[string]interface{}
+}
+
+func (e *PreconditionError) Error() string {
+	return fmt.Sprintf("%v (当前 updated_at=%v)", ErrPreconditionFailed, e.Current["updated_at"])
+}
+
+func (e *PreconditionError) Is(target error) bool {
+	return target == ErrPreconditionFailed
+}
+
+// APIError CloudBase 网关返回的非 2xx 错误
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Code       string // CloudBase 错误码，如 INVALID_PARAM
+	Message    string // CloudBase 错误描述
+	RequestID  string // CloudBase requestId，便于工单排查
+	Retryable  bool   // 是否可重试 (429 / 5xx)
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		msg = e.Code + ": " + msg
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("API错误 [%d] %s (requestId=%s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("API错误 [%d] %s", e.StatusCode, msg)
+}
+
+// Is 让 errors.Is(err, ErrNotFound) 等判断对 *APIError 生效
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// newAPIError 解析网关错误响应 {"code": "...", "message": "...", "requestId": "..."}
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+	}
+
+	var payload struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"requestId"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Message
+		if payload.RequestID != "" {
+			apiErr.RequestID = payload.RequestID
+		}
+	} else if len(body) > 0 {
+		// 非 JSON 响应 (如网关 HTML 错误页)，截断保留以便排查
+		const maxLen = 256
+		if len(body) > maxLen {
+			body = body[:maxLen]
+		}
+		apiErr.Message = string(body)
+	}
+	return apiErr
+}
@@ -0,0 +1,250 @@
+// Package match 在内存中执行 TCB 数据模型的 where / orderBy 语义
+// 供 tcbtest 假服务器等离线实现复用，保证与线上网关的筛选行为一致。
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Record 一条数据记录
+type Record = map[string]interface{}
+
+// Where 判断记录是否满足 where 条件
+// 支持: $eq $ne/$neq $in $nin $regex $gt $gte $lt $lte $and $or
+func Where(where map[string]interface{}, rec Record) (bool, error) {
+	for key, cond := range where {
+		switch key {
+		case "$and", "$or":
+			items, ok := cond.([]interface{})
+			if !ok {
+				return false, fmt.Errorf("%s 必须是数组", key)
+			}
+			matched, err := logical(key, items, rec)
+			if err != nil || !matched {
+				return false, err
+			}
+		default:
+			matched, err := field(rec[key], cond)
+			if err != nil {
+				return false, fmt.Errorf("字段 %s: %w", key, err)
+			}
+			if !matched {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func logical(op string, items []interface{}, rec Record) (bool, error) {
+	for _, item := range items {
+		sub, ok := item.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s 的元素必须是对象", op)
+		}
+		matched, err := Where(sub, rec)
+		if err != nil {
+			return false, err
+		}
+		if op == "$or" && matched {
+			return true, nil
+		}
+		if op == "$and" && !matched {
+			return false, nil
+		}
+	}
+	return op == "$and", nil
+}
+
+// field 判断字段值是否满足条件，条件为 {"$op": value, ...} 或裸值 (视为 $eq)
+func field(value, cond interface{}) (bool, error) {
+	ops, ok := cond.(map[string]interface{})
+	if !ok {
+		return equal(value, cond), nil
+	}
+	for op, arg := range ops {
+		matched, err := operator(op, value, arg)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func operator(op string, value, arg interface{}) (bool, error) {
+	switch op {
+	case "$eq":
+		return equal(value, arg), nil
+	case "$ne", "$neq":
+		return !equal(value, arg), nil
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s 必须是数组", op)
+		}
+		found := false
+		for _, item := range list {
+			if equal(value, item) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in"), nil
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("$regex 必须是字符串")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("$regex 非法: %w", err)
+		}
+		s, ok := value.(string)
+		return ok && re.MatchString(s), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		c, ok := Compare(value, arg)
+		if !ok {
+			return false, nil
+		}
+		switch op {
+		case "$gt":
+			return c > 0, nil
+		case "$gte":
+			return c >= 0, nil
+		case "$lt":
+			return c < 0, nil
+		default:
+			return c <= 0, nil
+		}
+	}
+	return false, fmt.Errorf("不支持的操作符 %s", op)
+}
+
+func equal(a, b interface{}) bool {
+	if c, ok := Compare(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Compare 比较两个同类标量 (数字或字符串)，类型不同时 ok=false
+func Compare(a, b interface{}) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	sb, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(sa, sb), true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// SortKey 一个排序字段
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOrderBy 解析 orderBy，兼容 [{"field": "desc"}] 与 [{"field": "x", "direction": "desc"}] 两种写法
+func ParseOrderBy(orderBy []interface{}) ([]SortKey, error) {
+	keys := make([]SortKey, 0, len(orderBy))
+	for _, item := range orderBy {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("orderBy 元素必须是对象")
+		}
+		if name, ok := m["field"].(string); ok {
+			dir, _ := m["direction"].(string)
+			if dir == "" {
+				dir, _ = m["order"].(string)
+			}
+			keys = append(keys, SortKey{Field: name, Desc: strings.EqualFold(dir, "desc")})
+			continue
+		}
+		for name, dir := range m {
+			d, _ := dir.(string)
+			keys = append(keys, SortKey{Field: name, Desc: strings.EqualFold(d, "desc")})
+		}
+	}
+	return keys, nil
+}
+
+// Sort 按排序字段稳定排序 (缺失字段排在最后)
+func Sort(records []Record, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, k := range keys {
+			a, aok := records[i][k.Field]
+			b, bok := records[j][k.Field]
+			if !aok || !bok {
+				if aok != bok {
+					return aok
+				}
+				continue
+			}
+			c, ok := Compare(a, b)
+			if !ok || c == 0 {
+				continue
+			}
+			if k.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// Project 按 select 投影记录 ({"field": true, ...})，_id 始终保留；sel 为空时原样返回
+func Project(rec Record, sel map[string]interface{}) Record {
+	if len(sel) == 0 {
+		return rec
+	}
+	out := make(Record, len(sel)+1)
+	if id, ok := rec["_id"]; ok {
+		out["_id"] = id
+	}
+	for field, want := range sel {
+		if on, _ := want.(bool); !on {
+			continue
+		}
+		if v, ok := rec[field]; ok {
+			out[field] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package tcb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cultural-tourism-backend/config"
+)
+
+// 数据模型发布阶段：/v1/model/{stage}/{modelName}/...
+const (
+	StageProd = "prod" // 正式版数据
+	StagePre  = "pre"  // 体验版 (预发布) 数据，供 QA 验证
+)
+
+// Profile 运行环境配置：决定云开发环境 ID、数据模型阶段以及网关域名
+type Profile struct {
+	Name     string
+	EnvID    string
+	Stage    string // StageProd / StagePre
+	Internal bool   // 云托管内网网关 (仅在云托管容器内可达)
+	BaseURL  string // 网关地址，为空时按 EnvID + Internal 推导
+}
+
+// profiles 预置环境，EnvID 由配置提供
+var profiles = map[string]Profile{
+	"dev":     {Name: "dev", Stage: StagePre, Internal: false},    // 本地开发：外网网关 + 体验版数据
+	"staging": {Name: "staging", Stage: StagePre, Internal: true}, // 预发布云托管：内网网关 + 体验版数据
+	"prod":    {Name: "prod", Stage: StageProd, Internal: true},   // 线上云托管：内网网关 + 正式版数据
+}
+
+// DefaultProfile 未配置 APP_PROFILE 时使用的环境
+const DefaultProfile = "prod"
+
+// ResolveProfile 按 cfg.Profile 选择预置环境，再应用单项覆盖:
+//   - 环境 ID: cfg.EnvIDs[profile]，未配置时回退到 cfg.EnvID
+//   - cfg.Stage: prod / pre
+//   - cfg.Internal: 内网 / 外网网关
+//   - cfg.BaseURL: 直接指定网关地址 (本地联调 tcbtest 假服务器)
+func ResolveProfile(cfg config.TCB) (Profile, error) {
+	name := cfg.Profile
+	p, ok := profiles[name]
+	if name == "" {
+		// 兼容未配置 APP_PROFILE 的旧部署：正式版数据 + 外网网关 (与此前行为一致)
+		p, ok = profiles[DefaultProfile], true
+		name, p.Internal = DefaultProfile, false
+	}
+	if !ok {
+		return Profile{}, fmt.Errorf("APP_PROFILE=%q 仅支持 %s", name, strings.Join(ProfileNames(), " / "))
+	}
+
+	p.EnvID = cfg.ProfileEnvID(name)
+	if cfg.Stage != "" {
+		p.Stage = cfg.Stage
+	}
+	if cfg.Internal != nil {
+		p.Internal = *cfg.Internal
+	}
+	p.BaseURL = cfg.BaseURL
+
+	return p, p.Validate()
+}
+
+// ProfileNames 预置环境名称 (已排序)
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate 校验阶段取值，并在未指定 BaseURL 时要求 EnvID
+func (p Profile) Validate() error {
+	if p.Stage != StageProd && p.Stage != StagePre {
+		return fmt.Errorf("数据模型阶段 %q 仅支持 %s / %s", p.Stage, StageProd, StagePre)
+	}
+	if p.BaseURL == "" && p.EnvID == "" {
+		return fmt.Errorf("环境 %s 未配置 CLOUDBASE_ENV_ID", p.Name)
+	}
+	return nil
+}
+
+// GatewayURL 网关地址：内网为 {envId}.api.intra.tcloudbasegateway.com，外网为 {envId}.api.tcloudbasegateway.com
+func (p Profile) GatewayURL() string {
+	if p.BaseURL != "" {
+		return p.BaseURL
+	}
+	if p.Internal {
+		return fmt.Sprintf("https://%s.api.intra.tcloudbasegateway.com", p.EnvID)
+	}
+	return fmt.Sprintf("https://%s.api.tcloudbasegateway.com", p.EnvID)
+}
+
+// Banner 启动时打印的环境信息
+func (p Profile) Banner() string {
+	gateway := "外网"
+	switch {
+	case p.BaseURL != "":
+		gateway = "自定义"
+	case p.Internal:
+		gateway = "内网"
+	}
+	stage := "正式版"
+	if p.Stage == StagePre {
+		stage = "体验版"
+	}
+	return fmt.Sprintf("🌐 运行环境: %s | 云开发环境: %s | 数据模型: %s (%s) | 网关: %s %s",
+		p.Name, p.EnvID, p.Stage, stage, gateway, p.GatewayURL())
+}
@@ -0,0 +1,147 @@
+// Package query 构造 TCB 数据模型查询对象
+//
+// 生成结果与 tcb.CloudBaseClient.ListData 的 filter 参数完全一致：
+//
+//	query.New(query.Eq("status", 1), query.In("type", "scenic", "food")).
+//		OrderBy("sort", query.Desc).
+//		Build()
+//	// => {"where": {"status": {"$eq": 1}, "type": {"$in": ["scenic", "food"]}},
+//	//     "orderBy": [{"sort": "desc"}]}
+package query
+
+import "maps"
+
+// Scalar 可用于比较的字段值类型
+type Scalar interface {
+	~string | ~int | ~int64 | ~float64 | ~bool
+}
+
+// Cond 一个 where 片段，如 {"status": {"$eq": 1}}
+// 少数 DSL 未覆盖的操作符 (如 $near) 可以直接写 Cond 字面量
+type Cond map[string]interface{}
+
+func op(field, operator string, value interface{}) Cond {
+	return Cond{field: map[string]interface{}{operator: value}}
+}
+
+// Eq 等于
+func Eq[T Scalar](field string, value T) Cond { return op(field, "$eq", value) }
+
+// Ne 不等于
+func Ne[T Scalar](field string, value T) Cond { return op(field, "$ne", value) }
+
+// In 属于集合
+func In[T Scalar](field string, values ...T) Cond { return op(field, "$in", toList(values)) }
+
+// Nin 不属于集合
+func Nin[T Scalar](field string, values ...T) Cond { return op(field, "$nin", toList(values)) }
+
+// Regex 正则匹配
+func Regex(field, pattern string) Cond { return op(field, "$regex", pattern) }
+
+// Gt 大于
+func Gt[T Scalar](field string, value T) Cond { return op(field, "$gt", value) }
+
+// Gte 大于等于
+func Gte[T Scalar](field string, value T) Cond { return op(field, "$gte", value) }
+
+// Lt 小于
+func Lt[T Scalar](field string, value T) Cond { return op(field, "$lt", value) }
+
+// Lte 小于等于
+func Lte[T Scalar](field string, value T) Cond { return op(field, "$lte", value) }
+
+// Range 半开区间 [from, to)，常用于时间范围
+func Range[T Scalar](field string, from, to T) Cond {
+	return Cond{field: map[string]interface{}{"$gte": from, "$lt": to}}
+}
+
+// And 逻辑与
+func And(conds ...Cond) Cond { return Cond{"$and": condList(conds)} }
+
+// Or 逻辑或
+func Or(conds ...Cond) Cond { return Cond{"$or": condList(conds)} }
+
+// Order 排序方向
+type Order string
+
+const (
+	Asc  Order = "asc"
+	Desc Order = "desc"
+)
+
+// Query 查询构造器
+type Query struct {
+	conds   []Cond
+	orderBy []interface{}
+	fields  []string
+}
+
+// New 创建查询，conds 之间为逻辑与
+func New(conds ...Cond) *Query {
+	return &Query{conds: conds}
+}
+
+// Where 追加条件 (逻辑与)
+func (q *Query) Where(conds ...Cond) *Query {
+	q.conds = append(q.conds, conds...)
+	return q
+}
+
+// OrderBy 追加排序字段 (按调用顺序生效)
+func (q *Query) OrderBy(field string, order Order) *Query {
+	q.orderBy = append(q.orderBy, map[string]interface{}{field: string(order)})
+	return q
+}
+
+// Select 字段投影 (仅返回指定字段，_id 始终返回)
+func (q *Query) Select(fields ...string) *Query {
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+// Build 输出 ListData 所需的 filter 对象
+func (q *Query) Build() map[string]interface{} {
+	out := map[string]interface{}{"where": q.where()}
+	if len(q.orderBy) > 0 {
+		out["orderBy"] = q.orderBy
+	}
+	if len(q.fields) > 0 {
+		sel := map[string]interface{}{"_id": true}
+		for _, f := range q.fields {
+			sel[f] = true
+		}
+		out["select"] = sel
+	}
+	return out
+}
+
+// where 合并条件：字段互不冲突时平铺为一个对象 (与手写格式一致)，否则使用 $and
+func (q *Query) where() map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, c := range q.conds {
+		for k := range c {
+			if _, dup := merged[k]; dup {
+				return map[string]interface{}{"$and": condList(q.conds)}
+			}
+		}
+		maps.Copy(merged, c)
+	}
+	return merged
+}
+
+func toList[T any](values []T) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func condList(conds []Cond) []interface{} {
+	out := make([]interface{}, len(conds))
+	for i, c := range conds {
+		out[i] = map[string]interface{}(c)
+	}
+	return out
+}
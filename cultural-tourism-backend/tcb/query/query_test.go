@@ -0,0 +1,71 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildFlatWhere(t *testing.T) {
+	got := New(Eq("status", 1), In("type", "scenic", "food")).
+		Where(Regex("name", "^西湖")).
+		OrderBy("sort", Desc).
+		OrderBy("created_at", Asc).
+		Build()
+
+	want := map[string]interface{}{
+		"where": map[string]interface{}{
+			"status": map[string]interface{}{"$eq": 1},
+			"type":   map[string]interface{}{"$in": []interface{}{"scenic", "food"}},
+			"name":   map[string]interface{}{"$regex": "^西湖"},
+		},
+		"orderBy": []interface{}{
+			map[string]interface{}{"sort": "desc"},
+			map[string]interface{}{"created_at": "asc"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestBuildDuplicateFieldsUseAnd(t *testing.T) {
+	got := New(Gt("score", 1.5), Lt("score", 4.0)).Build()
+
+	want := map[string]interface{}{
+		"where": map[string]interface{}{
+			"$and": []interface{}{
+				map[string]interface{}{"score": map[string]interface{}{"$gt": 1.5}},
+				map[string]interface{}{"score": map[string]interface{}{"$lt": 4.0}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildLogicalAndSelect(t *testing.T) {
+	got := New(
+		Or(Eq("type", "food"), Range("created_at", "2026-01-01", "2026-02-01")),
+	).Select("name", "latitude").Build()
+
+	want := map[string]interface{}{
+		"where": map[string]interface{}{
+			"$or": []interface{}{
+				map[string]interface{}{"type": map[string]interface{}{"$eq": "food"}},
+				map[string]interface{}{"created_at": map[string]interface{}{"$gte": "2026-01-01", "$lt": "2026-02-01"}},
+			},
+		},
+		"select": map[string]interface{}{"_id": true, "name": true, "latitude": true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	got := New().Build()
+	if !reflect.DeepEqual(got, map[string]interface{}{"where": map[string]interface{}{}}) {
+		t.Errorf("Build() = %v", got)
+	}
+}
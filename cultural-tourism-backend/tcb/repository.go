@@ -0,0 +1,244 @@
+package tcb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// Page 分页结果 (列表接口统一返回结构)
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Size  int `json:"size"`
+}
+
+// Repository 类型化的数据模型访问层
+// 负责把 TCB 返回的 data.records 解码为具体的 models 结构体，
+// 业务层不再手工做 result["data"].(map)["records"] 之类的类型断言。
+// 返回结构不符合预期 (字段改名、类型不符) 时直接报错，而不是静默返回空结果。
+type Repository[T any] struct {
+	ds    store.DataStore
+	model string
+}
+
+// NewRepository 创建指定数据模型的 Repository
+// ds 通常为 store.Default (CloudBaseClient 或 store/local)
+func NewRepository[T any](ds store.DataStore, model string) *Repository[T] {
+	return &Repository[T]{ds: ds, model: model}
+}
+
+// Model 返回数据模型名称
+func (r *Repository[T]) Model() string {
+	return r.model
+}
+
+// listEnvelope list 接口的响应结构
+type listEnvelope struct {
+	Data *struct {
+		Records []json.RawMessage `json:"records"`
+		Total   *int              `json:"total"`
+	} `json:"data"`
+}
+
+// createEnvelope create 接口的响应结构
+type createEnvelope struct {
+	Data *struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// List 分页查询 (filter 语义与 ListData 一致)
+func (r *Repository[T]) List(ctx context.Context, filter map[string]interface{}, page, size int) (*Page[T], error) {
+	result, err := r.ds.ListData(ctx, r.model, filter, page, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var env listEnvelope
+	if err := remarshal(result, &env); err != nil {
+		return nil, fmt.Errorf("[%s] %w: 解析列表响应失败: %v", r.model, ErrUnexpectedResponse, err)
+	}
+	if env.Data == nil || env.Data.Records == nil {
+		return nil, fmt.Errorf("[%s] %w: 缺少 data.records", r.model, ErrUnexpectedResponse)
+	}
+
+	items := make([]T, 0, len(env.Data.Records))
+	for i, raw := range env.Data.Records {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("[%s] %w: 第 %d 条记录解码失败: %v", r.model, ErrUnexpectedResponse, i, err)
+		}
+		items = append(items, item)
+	}
+
+	total := len(items)
+	if env.Data.Total != nil {
+		total = *env.Data.Total
+	}
+
+	return &Page[T]{Items: items, Total: total, Page: page, Size: size}, nil
+}
+
+// Count 统计满足 filter 的记录数 (利用 list 接口的 getCount)
+func (r *Repository[T]) Count(ctx context.Context, filter map[string]interface{}) (int, error) {
+	page, err := r.List(ctx, filter, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// Get 按 _id 获取单条记录，fields 非空时只取这些字段 (其余字段为零值)
+func (r *Repository[T]) Get(ctx context.Context, id string, fields ...string) (*T, error) {
+	record, err := r.ds.GetDetail(ctx, r.model, id, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	var item T
+	if err := remarshal(record, &item); err != nil {
+		return nil, fmt.Errorf("[%s] %w: 记录 %s 解码失败: %v", r.model, ErrUnexpectedResponse, id, err)
+	}
+	return &item, nil
+}
+
+// Create 新增记录，返回新记录的 _id
+func (r *Repository[T]) Create(ctx context.Context, data interface{}) (string, error) {
+	result, err := r.ds.CreateData(ctx, r.model, data)
+	if err != nil {
+		return "", err
+	}
+
+	var env createEnvelope
+	if err := remarshal(result, &env); err != nil {
+		return "", fmt.Errorf("[%s] %w: 解析创建响应失败: %v", r.model, ErrUnexpectedResponse, err)
+	}
+	if env.Data == nil || env.Data.ID == "" {
+		return "", fmt.Errorf("[%s] %w: 缺少 data.id", r.model, ErrUnexpectedResponse)
+	}
+	return env.Data.ID, nil
+}
+
+// CreateUnique 新增 field 取值为 value 的记录，模型中 field 需声明 x-unique，并发写入同值时只有一条成功
+// 网关对唯一约束冲突返回的错误码没有文档约定，这里不依据错误码判断：
+// 写入失败后按 field 回查，已存在同值记录时返回 ErrDuplicateKey，否则原样返回写入错误
+func (r *Repository[T]) CreateUnique(ctx context.Context, data interface{}, field, value string) (string, error) {
+	id, err := r.Create(ctx, data)
+	if err == nil || ctx.Err() != nil {
+		return id, err
+	}
+	existing, lookupErr := r.List(ctx, query.New(query.Eq(field, value)).Select("_id").Build(), 1, 1)
+	if lookupErr != nil || len(existing.Items) == 0 {
+		return "", err
+	}
+	return "", fmt.Errorf("[%s] %w: %s=%s", r.model, ErrDuplicateKey, field, value)
+}
+
+// Update 按 _id 更新 (data 建议使用 map 以支持部分更新)
+func (r *Repository[T]) Update(ctx context.Context, id string, data interface{}) error {
+	return r.ds.UpdateData(ctx, r.model, id, data)
+}
+
+// Delete 按 _id 删除
+func (r *Repository[T]) Delete(ctx context.Context, id string) error {
+	return r.ds.DeleteData(ctx, r.model, id)
+}
+
+// CreateMany 批量新增，Items 与 items 按下标一一对应
+func (r *Repository[T]) CreateMany(ctx context.Context, items []T) (*BatchResult, error) {
+	data := make([]interface{}, len(items))
+	for i := range items {
+		data[i] = items[i]
+	}
+	return r.ds.CreateMany(ctx, r.model, data)
+}
+
+// UpdateMany 按 filter 批量更新
+func (r *Repository[T]) UpdateMany(ctx context.Context, filter map[string]interface{}, data interface{}) (*BatchResult, error) {
+	return r.ds.UpdateMany(ctx, r.model, filter, data)
+}
+
+// DeleteMany 按 filter 批量删除
+func (r *Repository[T]) DeleteMany(ctx context.Context, filter map[string]interface{}) (*BatchResult, error) {
+	return r.ds.DeleteMany(ctx, r.model, filter)
+}
+
+// maxBatchIDs 单次 $in 查询 / 更新的 _id 数量上限 (不超过 list 接口 pageSize 上限)
+const maxBatchIDs = 100
+
+// UpdateByIDs 按 _id 列表批量更新，逐条返回结果
+// 先查询实际存在的 _id，再对存在的记录执行一次 updateMany，
+// 不存在的 _id 在 Items 中标记为失败而不是中断整批操作。
+func (r *Repository[T]) UpdateByIDs(ctx context.Context, ids []string, data interface{}) (*BatchResult, error) {
+	res := &BatchResult{Items: make([]BatchItem, 0, len(ids))}
+	for start := 0; start < len(ids); start += maxBatchIDs {
+		end := min(start+maxBatchIDs, len(ids))
+		chunk := ids[start:end]
+
+		existing, err := r.existingIDs(ctx, chunk)
+		if err != nil {
+			return res, err
+		}
+
+		found := make([]string, 0, len(existing))
+		for id := range existing {
+			found = append(found, id)
+		}
+		if len(found) > 0 {
+			updated, err := r.UpdateMany(ctx, query.New(query.In("_id", found...)).Build(), data)
+			if err != nil {
+				return res, err
+			}
+			res.Count += updated.Count
+		}
+
+		for _, id := range chunk {
+			if existing[id] {
+				res.Items = append(res.Items, BatchItem{ID: id, OK: true})
+			} else {
+				res.Items = append(res.Items, BatchItem{ID: id, Error: "记录不存在"})
+			}
+		}
+	}
+	return res, nil
+}
+
+// existingIDs 返回 ids 中实际存在的 _id 集合
+func (r *Repository[T]) existingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	filter := query.New(query.In("_id", ids...)).Select("_id").Build()
+	result, err := r.ds.ListData(ctx, r.model, filter, 1, len(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	var env struct {
+		Data *struct {
+			Records []struct {
+				ID string `json:"_id"`
+			} `json:"records"`
+		} `json:"data"`
+	}
+	if err := remarshal(result, &env); err != nil || env.Data == nil {
+		return nil, fmt.Errorf("[%s] %w: 解析列表响应失败", r.model, ErrUnexpectedResponse)
+	}
+
+	existing := make(map[string]bool, len(env.Data.Records))
+	for _, rec := range env.Data.Records {
+		existing[rec.ID] = true
+	}
+	return existing, nil
+}
+
+// remarshal 将通用 JSON 结构转换为目标类型
+func remarshal(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
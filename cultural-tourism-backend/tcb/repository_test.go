@@ -0,0 +1,155 @@
+package tcb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	ID    string  `json:"_id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+// fixed 总是返回同一响应的网关
+func fixed(status int, body string) *gateway {
+	return &gateway{respond: func(_ int, w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}}
+}
+
+func repoFor(t *testing.T, g *gateway) *Repository[record] {
+	t.Helper()
+	c := newTestClient(t, g)
+	c.Retry = RetryPolicy{}
+	return NewRepository[record](c, "items")
+}
+
+func TestRepositoryList(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repoFor(t, fixed(http.StatusOK, `{"data":{"records":[{"_id":"a","name":"雷峰塔","score":4.8,"extra":true},{"_id":"b","name":"灵隐寺"}],"total":12}}`))
+	page, err := repo.List(ctx, nil, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []record{{ID: "a", Name: "雷峰塔", Score: 4.8}, {ID: "b", Name: "灵隐寺"}}
+	if len(page.Items) != 2 || page.Items[0] != want[0] || page.Items[1] != want[1] || page.Total != 12 || page.Page != 2 || page.Size != 2 {
+		t.Errorf("page = %+v", page)
+	}
+
+	// 未返回 total 时按本页条数
+	repo = repoFor(t, fixed(http.StatusOK, `{"data":{"records":[{"_id":"a"}]}}`))
+	if n, err := repo.Count(ctx, nil); err != nil || n != 1 {
+		t.Errorf("Count = %d, %v", n, err)
+	}
+}
+
+func TestRepositoryUnexpectedResponses(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name string
+		body string
+		call func(*Repository[record]) error
+	}{
+		{"list without records", `{"data":{"total":0}}`, func(r *Repository[record]) error {
+			_, err := r.List(ctx, nil, 1, 10)
+			return err
+		}},
+		{"list record type mismatch", `{"data":{"records":[{"_id":"a","name":123}]}}`, func(r *Repository[record]) error {
+			_, err := r.List(ctx, nil, 1, 10)
+			return err
+		}},
+		{"get record type mismatch", `{"data":{"records":[{"_id":"a","score":"high"}]}}`, func(r *Repository[record]) error {
+			_, err := r.Get(ctx, "a")
+			return err
+		}},
+		{"get record is not an object", `{"data":{"records":["a"]}}`, func(r *Repository[record]) error {
+			_, err := r.Get(ctx, "a")
+			return err
+		}},
+		{"create without id", `{"data":{}}`, func(r *Repository[record]) error {
+			_, err := r.Create(ctx, record{Name: "x"})
+			return err
+		}},
+		{"invalid json", `<html>gateway error</html>`, func(r *Repository[record]) error {
+			_, err := r.List(ctx, nil, 1, 10)
+			return err
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(repoFor(t, fixed(http.StatusOK, tc.body))); !errors.Is(err, ErrUnexpectedResponse) {
+				t.Errorf("err = %v, want ErrUnexpectedResponse", err)
+			}
+		})
+	}
+}
+
+func TestRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// 记录不存在
+	repo := repoFor(t, fixed(http.StatusOK, `{"data":{"records":[],"total":0}}`))
+	if _, err := repo.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing: err = %v, want ErrNotFound", err)
+	}
+
+	// 网关错误原样返回 *APIError
+	repo = repoFor(t, &gateway{respond: func(_ int, w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"code":"DUPLICATE_KEY","message":"duplicate _id"}`))
+	}})
+	_, err := repo.Create(ctx, record{ID: "a"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrConflict) || apiErr.Code != "DUPLICATE_KEY" || apiErr.RequestID != "req-1" {
+		t.Errorf("Create conflict: err = %v", err)
+	}
+}
+
+func TestRepositoryCreate(t *testing.T) {
+	repo := repoFor(t, fixed(http.StatusOK, `{"data":{"id":"new-id"}}`))
+	if id, err := repo.Create(context.Background(), record{Name: "x"}); err != nil || id != "new-id" {
+		t.Errorf("Create = %q, %v", id, err)
+	}
+}
+
+func TestRepositoryCreateUnique(t *testing.T) {
+	ctx := context.Background()
+	// 写入失败时网关使用任意错误码，是否重复只看回查结果
+	uniqueGateway := func(createStatus int, existing string) *gateway {
+		return &gateway{respond: func(_ int, w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/create") {
+				w.WriteHeader(createStatus)
+				if createStatus == http.StatusOK {
+					w.Write([]byte(`{"data":{"id":"new-id"}}`))
+					return
+				}
+				w.Write([]byte(`{"code":"UNDOCUMENTED","message":"write rejected"}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"records":[` + existing + `]}}`))
+		}}
+	}
+
+	if id, err := repoFor(t, uniqueGateway(http.StatusOK, "")).CreateUnique(ctx, record{Name: "x"}, "name", "x"); err != nil || id != "new-id" {
+		t.Errorf("created: %q, %v", id, err)
+	}
+	for _, status := range []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError} {
+		_, err := repoFor(t, uniqueGateway(status, `{"_id":"a"}`)).CreateUnique(ctx, record{Name: "x"}, "name", "x")
+		if !errors.Is(err, ErrDuplicateKey) || !errors.Is(err, ErrConflict) {
+			t.Errorf("%d with existing record: err = %v, want ErrDuplicateKey", status, err)
+		}
+	}
+	// 不存在同值记录：原样返回写入错误
+	_, err := repoFor(t, uniqueGateway(http.StatusBadRequest, "")).CreateUnique(ctx, record{Name: "x"}, "name", "x")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "UNDOCUMENTED" || errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("without existing record: err = %v, want the write error", err)
+	}
+}
@@ -0,0 +1,136 @@
+package tcb
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy 幂等请求的重试策略 (指数退避 + Full Jitter)
+type RetryPolicy struct {
+	MaxAttempts int           // 最大尝试次数 (含首次)，<=1 表示不重试
+	BaseDelay   time.Duration // 首次退避基准
+	MaxDelay    time.Duration // 单次退避上限
+}
+
+// DefaultRetryPolicy 默认重试策略
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// backoff 第 attempt 次重试前的等待时间，取 [0, min(MaxDelay, BaseDelay*2^attempt)] 内的随机值
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// Stats 客户端调用统计 (用于日志与健康检查)
+type Stats struct {
+	Requests          uint64 `json:"requests"`           // 发往网关的请求数 (含重试)
+	Retries           uint64 `json:"retries"`            // 重试次数
+	Failures          uint64 `json:"failures"`           // 最终失败的调用数
+	BreakerRejections uint64 `json:"breaker_rejections"` // 被熔断器拒绝的调用数
+	BreakerState      string `json:"breaker_state"`
+}
+
+type clientStats struct {
+	requests          atomic.Uint64
+	retries           atomic.Uint64
+	failures          atomic.Uint64
+	breakerRejections atomic.Uint64
+}
+
+// Stats 返回当前调用统计快照
+func (c *CloudBaseClient) Stats() Stats {
+	state := BreakerClosed
+	if c.Breaker != nil {
+		state = c.Breaker.State()
+	}
+	return Stats{
+		Requests:          c.stats.requests.Load(),
+		Retries:           c.stats.retries.Load(),
+		Failures:          c.stats.failures.Load(),
+		BreakerRejections: c.stats.breakerRejections.Load(),
+		BreakerState:      state.String(),
+	}
+}
+
+// isRetryable 判断单次尝试的错误是否值得重试
+// parent 为调用方的 ctx：调用方已取消/超时则不再重试
+func isRetryable(parent context.Context, err error) bool {
+	if parent.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable
+	}
+	// 仅网络错误或单次尝试超时；序列化 / 令牌获取等本地错误重试无意义
+	var tErr *transportError
+	return errors.As(err, &tErr)
+}
+
+// transportError 请求未得到网关响应 (连接失败 / 单次尝试超时 / 读取响应中断)
+type transportError struct {
+	op  string
+	err error
+}
+
+func (e *transportError) Error() string { return e.op + ": " + e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// outcome 单次尝试对熔断器的意义
+type outcome int
+
+const (
+	outcomeNeutral outcome = iota // 调用方已取消，或请求未发出 (序列化 / 令牌获取失败)：不影响熔断
+	outcomeHealthy                // 网关正常响应 (含 4xx 业务错误)
+	outcomeFailure                // 网关不健康：传输错误或 5xx
+)
+
+// gatewayOutcome 判断错误对应的网关状态
+// parent 为调用方的 ctx：调用方取消 / 超时导致的失败不能说明网关的健康状况
+func gatewayOutcome(parent context.Context, err error) outcome {
+	if parent.Err() != nil {
+		return outcomeNeutral
+	}
+	var apiErr *APIError
+	var tErr *transportError
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode >= 500 {
+			return outcomeFailure
+		}
+		return outcomeHealthy
+	case errors.As(err, &tErr):
+		return outcomeFailure
+	case errors.Is(err, ErrUnexpectedResponse):
+		return outcomeHealthy
+	}
+	return outcomeNeutral
+}
+
+// sleepCtx 可被取消的等待
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
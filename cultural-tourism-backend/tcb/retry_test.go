@@ -0,0 +1,172 @@
+package tcb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gateway 按请求序号返回预设状态码的网关，记录请求次数
+type gateway struct {
+	hits    atomic.Int32
+	respond func(hit int, w http.ResponseWriter, r *http.Request)
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.respond(int(g.hits.Add(1)), w, r)
+}
+
+// statuses 第 n 次请求返回 codes[n-1]，超出部分返回空列表
+func statuses(codes ...int) func(int, http.ResponseWriter, *http.Request) {
+	return func(hit int, w http.ResponseWriter, _ *http.Request) {
+		if hit <= len(codes) && codes[hit-1] != http.StatusOK {
+			w.WriteHeader(codes[hit-1])
+			w.Write([]byte(`{"code":"INJECTED","message":"injected"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"records":[],"total":0}}`))
+	}
+}
+
+// newTestClient 指向 g 的客户端，退避时间压缩到毫秒级
+func newTestClient(t *testing.T, g *gateway) *CloudBaseClient {
+	t.Helper()
+	srv := httptest.NewServer(g)
+	t.Cleanup(srv.Close)
+	return &CloudBaseClient{
+		EnvID:      "test",
+		Tokens:     StaticTokenSource("token"),
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Client(),
+		Retry:      RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	}
+}
+
+type failingTokens struct{}
+
+func (failingTokens) Token(context.Context) (*Token, error) {
+	return nil, errors.New("token endpoint down")
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for attempt, ceiling := range []time.Duration{10, 20, 40, 50, 50} {
+		ceiling *= time.Millisecond
+		for range 200 {
+			if d := p.backoff(attempt); d < 0 || d >= ceiling {
+				t.Fatalf("backoff(%d) = %v, want [0, %v)", attempt, d, ceiling)
+			}
+		}
+	}
+	// 移位溢出时取上限
+	if d := p.backoff(80); d < 0 || d >= p.MaxDelay {
+		t.Errorf("backoff(80) = %v, want [0, %v)", d, p.MaxDelay)
+	}
+	if d := (RetryPolicy{}).backoff(3); d != 0 {
+		t.Errorf("zero policy backoff = %v, want 0", d)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name      string
+		codes     []int
+		write     bool
+		wantHits  int32
+		wantError bool
+	}{
+		{"read 5xx exhausts attempts", []int{503, 503, 503, 503}, false, 3, true},
+		{"read recovers after 5xx", []int{502, 200}, false, 2, false},
+		{"read 429 retries", []int{429, 200}, false, 2, false},
+		{"read 4xx is final", []int{400}, false, 1, true},
+		{"write is never retried", []int{503}, true, 1, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &gateway{respond: statuses(tc.codes...)}
+			c := newTestClient(t, g)
+			var err error
+			if tc.write {
+				_, err = c.CreateData(ctx, "items", map[string]interface{}{"name": "x"})
+			} else {
+				_, err = c.ListData(ctx, "items", nil, 1, 10)
+			}
+			if (err != nil) != tc.wantError {
+				t.Fatalf("err = %v, wantError %v", err, tc.wantError)
+			}
+			if got := g.hits.Load(); got != tc.wantHits {
+				t.Errorf("hits = %d, want %d", got, tc.wantHits)
+			}
+			stats := c.Stats()
+			if stats.Requests != uint64(tc.wantHits) || stats.Retries != uint64(tc.wantHits-1) {
+				t.Errorf("stats = %+v", stats)
+			}
+			if (stats.Failures == 1) != tc.wantError {
+				t.Errorf("failures = %d, wantError %v", stats.Failures, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	// 首次请求超过单次超时，第二次正常返回
+	g := &gateway{respond: func(hit int, w http.ResponseWriter, r *http.Request) {
+		if hit == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		statuses()(hit, w, r)
+	}}
+	c := newTestClient(t, g)
+	c.Timeouts.Read = 20 * time.Millisecond
+	if _, err := c.ListData(context.Background(), "items", nil, 1, 10); err != nil {
+		t.Fatalf("ListData: %v", err)
+	}
+	if got := g.hits.Load(); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+}
+
+func TestLocalErrorsAreNotRetried(t *testing.T) {
+	ctx := context.Background()
+
+	// 令牌获取失败：请求未发出，不重试
+	g := &gateway{respond: statuses()}
+	c := newTestClient(t, g)
+	c.Tokens = failingTokens{}
+	if _, err := c.ListData(ctx, "items", nil, 1, 10); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if stats := c.Stats(); g.hits.Load() != 0 || stats.Requests != 1 || stats.Retries != 0 {
+		t.Errorf("token failure: hits = %d, stats = %+v", g.hits.Load(), stats)
+	}
+
+	// 序列化失败同样不重试
+	c = newTestClient(t, g)
+	bad := map[string]interface{}{"where": map[string]interface{}{"f": func() {}}}
+	if _, err := c.ListData(ctx, "items", bad, 1, 10); err == nil {
+		t.Error("marshal failure: want error")
+	}
+	if stats := c.Stats(); stats.Requests != 1 || stats.Retries != 0 {
+		t.Errorf("marshal failure: stats = %+v", stats)
+	}
+
+	// 调用方取消后不再重试 (网关返回 5xx 时调用方已放弃)
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g = &gateway{respond: func(hit int, w http.ResponseWriter, r *http.Request) {
+		cancel()
+		statuses(503)(hit, w, r)
+	}}
+	c = newTestClient(t, g)
+	if _, err := c.ListData(cctx, "items", nil, 1, 10); err == nil {
+		t.Error("cancelled: want error")
+	}
+	if got := g.hits.Load(); got != 1 {
+		t.Errorf("cancelled: hits = %d, want 1", got)
+	}
+}
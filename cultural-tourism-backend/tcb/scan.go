@@ -0,0 +1,175 @@
+package tcb
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"cultural-tourism-backend/store"
+	"cultural-tourism-backend/tcb/query"
+)
+
+// ScanOrder 全量扫描使用的排序键
+type ScanOrder string
+
+const (
+	// ScanByID 按 _id 升序：扫描期间新增/修改记录不会导致已有记录重复或遗漏
+	ScanByID ScanOrder = "_id"
+	// ScanByUpdatedAt 按 updated_at 升序 (同值再按 _id)，适合增量同步；
+	// 扫描期间被更新的记录会移到末尾，可能再出现一次
+	ScanByUpdatedAt ScanOrder = "updated_at"
+)
+
+// DefaultScanPageSize 全量扫描默认每页条数
+const DefaultScanPageSize = 100
+
+// ScanOptions 全量扫描参数
+type ScanOptions struct {
+	Order    ScanOrder // 为空时使用 ScanByID
+	PageSize int       // <=0 时使用 DefaultScanPageSize
+}
+
+// ListAll 自动翻页遍历满足 filter 的全部记录，见 ListAll 函数
+func (c *CloudBaseClient) ListAll(ctx context.Context, modelName string, filter map[string]interface{}, opts ScanOptions) iter.Seq2[map[string]interface{}, error] {
+	return ListAll(ctx, c, modelName, filter, opts)
+}
+
+// ListAll 基于任意 store.DataStore 自动翻页遍历满足 filter 的全部记录
+// 使用游标 (keyset) 分页而非 pageNumber：每页都从上一页最后一条之后开始，
+// 数据在扫描过程中变化时也不会跳页。filter 中的 orderBy 会被 opts.Order 取代，
+// where / select 原样保留。ctx 取消后以 ctx.Err() 结束遍历。
+//
+//	for rec, err := range tcb.Client.ListAll(ctx, "pois", filter, tcb.ScanOptions{}) {
+//		if err != nil { return err }
+//		...
+//	}
+func ListAll(ctx context.Context, ds store.DataStore, modelName string, filter map[string]interface{}, opts ScanOptions) iter.Seq2[map[string]interface{}, error] {
+	order := opts.Order
+	if order == "" {
+		order = ScanByID
+	}
+	size := opts.PageSize
+	if size <= 0 {
+		size = DefaultScanPageSize
+	}
+	where, _ := filter["where"].(map[string]interface{})
+
+	return func(yield func(map[string]interface{}, error) bool) {
+		var cursor query.Cond
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			q := query.New()
+			if len(where) > 0 {
+				q.Where(query.Cond(where))
+			}
+			if cursor != nil {
+				q.Where(cursor)
+			}
+			if order != ScanByID {
+				q.OrderBy(string(order), query.Asc)
+			}
+			q.OrderBy("_id", query.Asc)
+			page := q.Build()
+			if sel, ok := filter["select"].(map[string]interface{}); ok {
+				// 游标字段 (排序字段与 _id) 必须返回，否则无法定位下一页
+				withKey := make(map[string]interface{}, len(sel)+2)
+				for k, v := range sel {
+					withKey[k] = v
+				}
+				withKey["_id"] = true
+				withKey[string(order)] = true
+				page["select"] = withKey
+			}
+
+			result, err := ds.ListData(ctx, modelName, page, 1, size)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			records, err := pageRecords(modelName, result)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, rec := range records {
+				if !yield(rec, nil) {
+					return
+				}
+			}
+			if len(records) < size {
+				return
+			}
+
+			cursor, err = after(order, records[len(records)-1])
+			if err != nil {
+				yield(nil, fmt.Errorf("[%s] %w", modelName, err))
+				return
+			}
+		}
+	}
+}
+
+// All 类型化的全量遍历，语义同 ListAll
+func (r *Repository[T]) All(ctx context.Context, filter map[string]interface{}, opts ScanOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for rec, err := range ListAll(ctx, r.ds, r.model, filter, opts) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			var item T
+			if err := remarshal(rec, &item); err != nil {
+				yield(zero, fmt.Errorf("[%s] %w: 记录 %v 解码失败: %v", r.model, ErrUnexpectedResponse, rec["_id"], err))
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// pageRecords 取出 list 响应中的 data.records
+func pageRecords(modelName string, result map[string]interface{}) ([]map[string]interface{}, error) {
+	var env struct {
+		Data *struct {
+			Records []map[string]interface{} `json:"records"`
+		} `json:"data"`
+	}
+	if err := remarshal(result, &env); err != nil {
+		return nil, fmt.Errorf("[%s] %w: 解析列表响应失败: %v", modelName, ErrUnexpectedResponse, err)
+	}
+	if env.Data == nil || env.Data.Records == nil {
+		return nil, fmt.Errorf("[%s] %w: 缺少 data.records", modelName, ErrUnexpectedResponse)
+	}
+	return env.Data.Records, nil
+}
+
+// after 构造 "排在 last 之后" 的游标条件
+// 游标值来自网关返回的 JSON (string / float64)，DSL 的泛型函数无法直接使用，这里写 Cond 字面量
+func after(order ScanOrder, last map[string]interface{}) (query.Cond, error) {
+	id, ok := last["_id"].(string)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("%w: 记录缺少 _id，无法继续翻页", ErrUnexpectedResponse)
+	}
+	idAfter := query.Cond{"_id": map[string]interface{}{"$gt": id}}
+	if order == ScanByID {
+		return idAfter, nil
+	}
+
+	key := string(order)
+	v, ok := last[key]
+	if !ok || v == nil {
+		return nil, fmt.Errorf("%w: 记录 %s 缺少排序字段 %s", ErrUnexpectedResponse, id, key)
+	}
+	return query.Or(
+		query.Cond{key: map[string]interface{}{"$gt": v}},
+		query.And(query.Cond{key: map[string]interface{}{"$eq": v}}, idAfter),
+	), nil
+}
@@ -0,0 +1,47 @@
+package tcb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestListAllSelectKeepsCursorFields(t *testing.T) {
+	filter := map[string]interface{}{"select": map[string]interface{}{"name": true}}
+
+	for _, order := range []ScanOrder{ScanByID, ScanByUpdatedAt} {
+		var selects []map[string]interface{}
+		// 第一页返回满页，第二页返回空
+		g := &gateway{respond: func(hit int, w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				Select map[string]interface{} `json:"select"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			selects = append(selects, payload.Select)
+			if hit == 1 {
+				w.Write([]byte(`{"data":{"records":[{"_id":"a","name":"x","updated_at":"1"}]}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"records":[]}}`))
+		}}
+		c := newTestClient(t, g)
+		for _, err := range c.ListAll(context.Background(), "items", filter, ScanOptions{Order: order, PageSize: 1}) {
+			if err != nil {
+				t.Fatalf("%s: %v", order, err)
+			}
+		}
+		if len(selects) != 2 {
+			t.Fatalf("%s: %d requests, want 2", order, len(selects))
+		}
+		for _, sel := range selects {
+			if sel["_id"] != true || sel[string(order)] != true || sel["name"] != true {
+				t.Errorf("%s: select = %v, want name, _id and %s", order, sel, order)
+			}
+		}
+	}
+	// 调用方的 select 不被修改
+	if sel := filter["select"].(map[string]interface{}); len(sel) != 1 {
+		t.Errorf("caller select mutated: %v", sel)
+	}
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"cultural-tourism-backend/tcb"
+)
+
+// 默认配置
+const (
+	DefaultMaxAge = 2 * time.Hour // 临时链接有效期
+	maxBatch      = 50            // 单次换取 / 删除的 fileID 上限
+)
+
+// Client CloudBase 云存储 HTTP API
+// 网关请求经 tcb.CloudBaseClient 发出 (共享鉴权、超时、熔断与重试)，
+// 上传时先换取 COS 签名，再由 HTTPClient 直传对象。
+type Client struct {
+	TCB        *tcb.CloudBaseClient
+	HTTPClient *http.Client  // 直传 COS，为 nil 时使用 TCB.HTTPClient
+	MaxAge     time.Duration // 临时链接有效期，<=0 时使用 DefaultMaxAge
+}
+
+var _ Storage = (*Client)(nil)
+
+// New 基于已初始化的 CloudBaseClient 创建云存储客户端
+func New(c *tcb.CloudBaseClient) *Client {
+	return &Client{TCB: c, HTTPClient: c.HTTPClient, MaxAge: DefaultMaxAge}
+}
+
+// objectInfo 云存储接口返回的单个对象信息 (各接口只填充其中一部分)
+type objectInfo struct {
+	CloudObjectID   string `json:"cloudObjectId"`
+	UploadURL       string `json:"uploadUrl"`
+	DownloadURL     string `json:"downloadUrl"`
+	Authorization   string `json:"authorization"`
+	Token           string `json:"token"`
+	CloudObjectMeta string `json:"cloudObjectMeta"`
+	Code            string `json:"code"`
+	Message         string `json:"message"`
+}
+
+func (o objectInfo) failed() bool {
+	return o.Code != "" && o.Code != "SUCCESS"
+}
+
+// Upload 上传对象
+// API: POST /v1/storages/get-objects-upload-info 换取签名，再 PUT 到 uploadUrl
+func (s *Client) Upload(ctx context.Context, path string, data []byte, contentType string) (string, error) {
+	infos, err := s.objects(ctx, "get-objects-upload-info", []map[string]interface{}{{"objectId": path}})
+	if err != nil {
+		return "", err
+	}
+	if len(infos) != 1 {
+		return "", fmt.Errorf("%w: 上传签名返回 %d 条", tcb.ErrUnexpectedResponse, len(infos))
+	}
+	info := infos[0]
+	if info.failed() {
+		return "", fmt.Errorf("获取上传签名失败: %s %s", info.Code, info.Message)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, info.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("创建上传请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", info.Authorization)
+	req.Header.Set("X-Cos-Security-Token", info.Token)
+	req.Header.Set("X-Cos-Meta-Fileid", info.CloudObjectMeta)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("上传失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("上传失败: HTTP %d %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return info.CloudObjectID, nil
+}
+
+// Delete 删除对象
+// API: POST /v1/storages/delete-objects
+func (s *Client) Delete(ctx context.Context, fileIDs ...string) ([]Result, error) {
+	results := make([]Result, 0, len(fileIDs))
+	for chunk := range slices.Chunk(fileIDs, maxBatch) {
+		items := make([]map[string]interface{}, len(chunk))
+		for i, id := range chunk {
+			items[i] = map[string]interface{}{"cloudObjectId": id}
+		}
+		infos, err := s.objects(ctx, "delete-objects", items)
+		if err != nil {
+			return results, err
+		}
+		byID := indexByID(infos)
+		for _, id := range chunk {
+			info, ok := byID[id]
+			switch {
+			case !ok:
+				results = append(results, Result{FileID: id, Error: "网关未返回该对象"})
+			case info.failed():
+				results = append(results, Result{FileID: id, Error: info.Code + " " + info.Message})
+			default:
+				results = append(results, Result{FileID: id, OK: true})
+			}
+		}
+	}
+	return results, nil
+}
+
+// TempURLs 批量换取临时链接
+// API: POST /v1/storages/get-objects-download-info
+func (s *Client) TempURLs(ctx context.Context, fileIDs []string) (map[string]string, error) {
+	maxAge := s.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	urls := make(map[string]string, len(fileIDs))
+	for chunk := range slices.Chunk(fileIDs, maxBatch) {
+		items := make([]map[string]interface{}, len(chunk))
+		for i, id := range chunk {
+			items[i] = map[string]interface{}{"cloudObjectId": id, "maxAge": int(maxAge.Seconds())}
+		}
+		infos, err := s.objects(ctx, "get-objects-download-info", items)
+		if err != nil {
+			return urls, err
+		}
+		for _, info := range infos {
+			if !info.failed() && info.DownloadURL != "" {
+				urls[info.CloudObjectID] = info.DownloadURL
+			}
+		}
+	}
+	return urls, nil
+}
+
+// objects 调用云存储批量接口，请求与响应均为对象数组 (签名 / 换链 / 删除均可安全重试)
+func (s *Client) objects(ctx context.Context, action string, items []map[string]interface{}) ([]objectInfo, error) {
+	result, err := s.TCB.Invoke(ctx, true, http.MethodPost, "/v1/storages/"+action, items)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var infos []objectInfo
+	if err := json.Unmarshal(raw, &infos); err != nil {
+		return nil, fmt.Errorf("%w: %s 响应不是对象数组", tcb.ErrUnexpectedResponse, action)
+	}
+	return infos, nil
+}
+
+func (s *Client) httpClient() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return s.TCB.HTTPClient
+}
+
+func indexByID(infos []objectInfo) map[string]objectInfo {
+	out := make(map[string]objectInfo, len(infos))
+	for _, info := range infos {
+		out[info.CloudObjectID] = info
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localBucket 本地存储 fileID 的 "环境.存储桶" 部分
+const localBucket = "local"
+
+// Local 本地目录模拟云存储，供离线开发使用
+// fileID 形如 cloud://local/photos/a.jpg，临时链接为 BaseURL + "/photos/a.jpg"，
+// 由 Local 自身 (http.Handler) 挂载在 BaseURL 对应的路由下提供下载。
+type Local struct {
+	Dir     string // 存储根目录
+	BaseURL string // 下载地址前缀，如 http://localhost:8080/files
+}
+
+var _ Storage = (*Local)(nil)
+
+// NewLocal 创建本地存储，目录不存在时自动创建
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &Local{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (s *Local) Upload(ctx context.Context, objectPath string, data []byte, contentType string) (string, error) {
+	name, err := s.file(objectPath)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", fmt.Errorf("上传失败: %w", err)
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return "", fmt.Errorf("上传失败: %w", err)
+	}
+	return Scheme + localBucket + "/" + path.Clean(objectPath), nil
+}
+
+func (s *Local) Delete(ctx context.Context, fileIDs ...string) ([]Result, error) {
+	results := make([]Result, len(fileIDs))
+	for i, id := range fileIDs {
+		results[i] = Result{FileID: id}
+		name, err := s.fileFromID(id)
+		if err == nil {
+			err = os.Remove(name)
+		}
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			results[i].Error = "STORAGE_FILE_NONEXIST"
+		case err != nil:
+			results[i].Error = err.Error()
+		default:
+			results[i].OK = true
+		}
+	}
+	return results, nil
+}
+
+// TempURLs 本地文件的链接不会过期，只对存在的文件返回链接
+func (s *Local) TempURLs(ctx context.Context, fileIDs []string) (map[string]string, error) {
+	urls := make(map[string]string, len(fileIDs))
+	for _, id := range fileIDs {
+		name, err := s.fileFromID(id)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(name); err != nil {
+			continue
+		}
+		objectPath, _ := strings.CutPrefix(id, Scheme+localBucket+"/")
+		urls[id] = s.BaseURL + "/" + (&url.URL{Path: objectPath}).EscapedPath()
+	}
+	return urls, nil
+}
+
+// ServeHTTP 按对象路径下载文件 (挂载时需去掉路由前缀)
+func (s *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/") {
+		http.NotFound(w, r) // 不提供目录浏览
+		return
+	}
+	http.FileServer(http.Dir(s.Dir)).ServeHTTP(w, r)
+}
+
+// file 对象路径 -> 本地文件，拒绝跳出存储目录的路径
+func (s *Local) file(objectPath string) (string, error) {
+	if !filepath.IsLocal(objectPath) {
+		return "", fmt.Errorf("非法的对象路径: %q", objectPath)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(objectPath)), nil
+}
+
+func (s *Local) fileFromID(fileID string) (string, error) {
+	objectPath, ok := strings.CutPrefix(fileID, Scheme+localBucket+"/")
+	if !ok {
+		return "", fmt.Errorf("不是本地存储的 fileID: %q", fileID)
+	}
+	return s.file(objectPath)
+}
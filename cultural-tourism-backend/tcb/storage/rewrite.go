@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// URLCache 为 TempURLs 增加进程内缓存的装饰器
+// 临时链接在有效期内可重复使用，缓存 TTL 应明显短于链接有效期 (默认取一半)，
+// 保证下发给前端的链接至少还能用上 TTL 这么久。
+type URLCache struct {
+	Storage
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedURL
+}
+
+type cachedURL struct {
+	url     string
+	expires time.Time
+}
+
+// maxCachedURLs 缓存条目上限，超过时整体清空 (链接很快会被重新换取，无需精细淘汰)
+const maxCachedURLs = 10000
+
+// WithURLCache 包装 s，临时链接缓存 ttl
+func WithURLCache(s Storage, ttl time.Duration) *URLCache {
+	return &URLCache{Storage: s, ttl: ttl, entries: make(map[string]cachedURL)}
+}
+
+// TempURLs 命中缓存的直接返回，其余合并为一次批量换取
+func (c *URLCache) TempURLs(ctx context.Context, fileIDs []string) (map[string]string, error) {
+	urls := make(map[string]string, len(fileIDs))
+	var missing []string
+
+	now := time.Now()
+	c.mu.Lock()
+	for _, id := range fileIDs {
+		if e, ok := c.entries[id]; ok && now.Before(e.expires) {
+			urls[id] = e.url
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return urls, nil
+	}
+
+	fetched, err := c.Storage.TempURLs(ctx, missing)
+
+	c.mu.Lock()
+	if len(c.entries)+len(fetched) > maxCachedURLs {
+		clear(c.entries)
+	}
+	for id, u := range fetched {
+		c.entries[id] = cachedURL{url: u, expires: now.Add(c.ttl)}
+		urls[id] = u
+	}
+	c.mu.Unlock()
+	return urls, err
+}
+
+// Delete 删除对象并丢弃其缓存链接
+func (c *URLCache) Delete(ctx context.Context, fileIDs ...string) ([]Result, error) {
+	c.mu.Lock()
+	for _, id := range fileIDs {
+		delete(c.entries, id)
+	}
+	c.mu.Unlock()
+	return c.Storage.Delete(ctx, fileIDs...)
+}
+
+// Rewrite 把 JSON 解码结果 (map / slice / string 任意嵌套) 中的 fileID 原地替换为临时链接
+// 所有 fileID 合并为一次批量换取；换取失败的 fileID 保持原值，err 返回换取错误
+func Rewrite(ctx context.Context, s Storage, v interface{}) (interface{}, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	walk(v, func(str string) string {
+		if IsFileID(str) && !seen[str] {
+			seen[str] = true
+			ids = append(ids, str)
+		}
+		return str
+	})
+	if len(ids) == 0 {
+		return v, nil
+	}
+
+	urls, err := s.TempURLs(ctx, ids)
+	v = walk(v, func(str string) string {
+		if u, ok := urls[str]; ok {
+			return u
+		}
+		return str
+	})
+	return v, err
+}
+
+// walk 深度遍历，对每个字符串调用 fn 并用返回值替换
+func walk(v interface{}, fn func(string) string) interface{} {
+	switch t := v.(type) {
+	case string:
+		return fn(t)
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = walk(item, fn)
+		}
+	case []interface{}:
+		for i, item := range t {
+			t[i] = walk(item, fn)
+		}
+	}
+	return v
+}
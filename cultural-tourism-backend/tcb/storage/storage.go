@@ -0,0 +1,39 @@
+// Package storage 云存储：上传 / 删除对象，以及把 cloud:// fileID 批量换成临时 HTTPS 链接
+//
+// 记录中统一保存 fileID (cloud://...)，对外输出时再替换为临时链接 (见 Rewrite)，
+// 这样链接过期、存储桶调整都不需要改数据。
+// 线上使用 Client (CloudBase 云存储 HTTP API)，离线开发使用 Local (本地目录)。
+package storage
+
+import (
+	"context"
+	"strings"
+)
+
+// Scheme fileID 前缀
+const Scheme = "cloud://"
+
+// IsFileID 判断字符串是否为云存储 fileID
+func IsFileID(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// Storage 云存储后端
+type Storage interface {
+	// Upload 上传对象，path 为存储内路径 (如 photos/202601/xxx.jpg)，返回 fileID
+	Upload(ctx context.Context, path string, data []byte, contentType string) (string, error)
+	// Delete 删除对象，Results 与 fileIDs 按下标一一对应
+	Delete(ctx context.Context, fileIDs ...string) ([]Result, error)
+	// TempURLs 批量换取临时链接，返回 fileID -> URL；单个 fileID 换取失败时不出现在结果中
+	TempURLs(ctx context.Context, fileIDs []string) (map[string]string, error)
+}
+
+// Default 全局云存储 (启动时按配置选择，未配置时为 nil)
+var Default Storage
+
+// Result 单个对象的操作结果
+type Result struct {
+	FileID string `json:"file_id"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
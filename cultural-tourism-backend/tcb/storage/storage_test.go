@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/tcb/storage"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	s, err := storage.NewLocal(t.TempDir(), "http://localhost:8080/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	fileID, err := s.Upload(ctx, "themes/封面.png", []byte("png"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileID != "cloud://local/themes/封面.png" {
+		t.Errorf("fileID = %q", fileID)
+	}
+	if _, err := s.Upload(ctx, "../escape.png", []byte("x"), ""); err == nil {
+		t.Error("path outside the storage dir was accepted")
+	}
+
+	urls, _ := s.TempURLs(ctx, []string{fileID, "cloud://local/missing.png"})
+	if len(urls) != 1 || !strings.HasPrefix(urls[fileID], "http://localhost:8080/files/themes/") {
+		t.Errorf("urls = %v", urls)
+	}
+
+	results, _ := s.Delete(ctx, fileID, fileID)
+	if !results[0].OK || results[1].OK {
+		t.Errorf("delete results = %+v, want second delete to fail", results)
+	}
+}
+
+// countingStorage 记录 TempURLs 调用
+type countingStorage struct {
+	storage.Storage
+	calls [][]string
+}
+
+func (c *countingStorage) TempURLs(ctx context.Context, ids []string) (map[string]string, error) {
+	c.calls = append(c.calls, ids)
+	urls := make(map[string]string)
+	for _, id := range ids {
+		if !strings.Contains(id, "missing") {
+			urls[id] = "https://cdn.example.com/" + strings.TrimPrefix(id, storage.Scheme)
+		}
+	}
+	return urls, nil
+}
+
+func TestRewriteBatchesAndCaches(t *testing.T) {
+	backend := &countingStorage{}
+	s := storage.WithURLCache(backend, time.Minute)
+
+	var body interface{}
+	_ = json.Unmarshal([]byte(`{
+		"items": [
+			{"name": "雷峰塔", "images": ["cloud://env/a.jpg", "cloud://env/b.jpg"]},
+			{"name": "断桥", "images": ["cloud://env/a.jpg", "cloud://env/missing.jpg"], "desc": "https://x/y.jpg"}
+		],
+		"total": 2
+	}`), &body)
+
+	out, err := storage.Rewrite(context.Background(), s, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"items":[{"images":["https://cdn.example.com/env/a.jpg","https://cdn.example.com/env/b.jpg"],"name":"雷峰塔"},` +
+		`{"desc":"https://x/y.jpg","images":["https://cdn.example.com/env/a.jpg","cloud://env/missing.jpg"],"name":"断桥"}],"total":2}`
+	if string(got) != want {
+		t.Errorf("rewritten =\n%s\nwant\n%s", got, want)
+	}
+	if len(backend.calls) != 1 || len(backend.calls[0]) != 3 {
+		t.Fatalf("TempURLs calls = %v, want one batch of 3 distinct ids", backend.calls)
+	}
+
+	// 已缓存的链接不再换取，只换取未命中的
+	_, _ = storage.Rewrite(context.Background(), s, []interface{}{"cloud://env/a.jpg", "cloud://env/c.jpg"})
+	if len(backend.calls) != 2 || len(backend.calls[1]) != 1 || backend.calls[1][0] != "cloud://env/c.jpg" {
+		t.Errorf("TempURLs calls = %v, want second call only for c.jpg", backend.calls)
+	}
+}
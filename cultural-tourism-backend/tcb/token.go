@@ -0,0 +1,181 @@
+package tcb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token 网关访问令牌
+type Token struct {
+	Value  string
+	Expiry time.Time // 零值表示不过期 (如 API Key)
+}
+
+// expiresWithin 令牌是否会在 d 内过期
+func (t *Token) expiresWithin(d time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Until(t.Expiry) <= d
+}
+
+// TokenSource 令牌来源
+// 测试或本地联调使用 StaticTokenSource，线上由凭证换取并缓存 (CachedTokenSource)
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// StaticTokenSource 固定令牌 (API Key 或手工配置的 access token)
+type StaticTokenSource string
+
+func (s StaticTokenSource) Token(context.Context) (*Token, error) {
+	return &Token{Value: string(s)}, nil
+}
+
+// ClientCredentialsSource 使用 SecretId / SecretKey 换取 access token
+// API: POST /auth/v1/token (Basic 鉴权，grant_type=client_credentials)
+type ClientCredentialsSource struct {
+	TokenURL   string
+	SecretID   string
+	SecretKey  string
+	HTTPClient *http.Client
+}
+
+// Token 每次调用都会请求网关，需配合 CachedTokenSource 使用
+func (s *ClientCredentialsSource) Token(ctx context.Context) (*Token, error) {
+	body := strings.NewReader(`{"grant_type":"client_credentials"}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("创建令牌请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(s.SecretID, s.SecretKey)
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("获取令牌失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取令牌响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp, data)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"` // 秒
+	}
+	if err := json.Unmarshal(data, &payload); err != nil || payload.AccessToken == "" {
+		return nil, fmt.Errorf("%w: 令牌响应缺少 access_token", ErrUnexpectedResponse)
+	}
+
+	tok := &Token{Value: payload.AccessToken}
+	if payload.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+	return tok, nil
+}
+
+// DefaultRefreshAhead 令牌到期前提前刷新的时间窗口
+const DefaultRefreshAhead = 5 * time.Minute
+
+// backgroundRefreshTimeout 后台提前刷新的超时 (不绑定任何业务请求的 ctx)
+const backgroundRefreshTimeout = 10 * time.Second
+
+// CachedTokenSource 缓存令牌直至过期
+// 进入 RefreshAhead 窗口后先返回旧令牌并在后台刷新，已过期时同步刷新；
+// 并发请求只会触发一次换取。
+type CachedTokenSource struct {
+	Source       TokenSource
+	RefreshAhead time.Duration
+
+	mu         sync.Mutex
+	tok        *Token
+	refreshing bool
+
+	fetchMu sync.Mutex // 串行化同步换取
+}
+
+// NewCachedTokenSource 包装一个需要缓存的令牌来源
+func NewCachedTokenSource(src TokenSource) *CachedTokenSource {
+	return &CachedTokenSource{Source: src, RefreshAhead: DefaultRefreshAhead}
+}
+
+func (s *CachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	tok := s.tok
+	if tok != nil && !tok.expiresWithin(0) {
+		if tok.expiresWithin(s.RefreshAhead) && !s.refreshing {
+			s.refreshing = true
+			go s.refreshInBackground()
+		}
+		s.mu.Unlock()
+		return tok, nil
+	}
+	s.mu.Unlock()
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// 等锁期间可能已被其他请求刷新
+	s.mu.Lock()
+	tok = s.tok
+	s.mu.Unlock()
+	if tok != nil && !tok.expiresWithin(0) {
+		return tok, nil
+	}
+	return s.fetch(ctx)
+}
+
+// Invalidate 丢弃缓存的令牌 (网关返回 401 时调用)
+func (s *CachedTokenSource) Invalidate() {
+	s.mu.Lock()
+	s.tok = nil
+	s.mu.Unlock()
+}
+
+func (s *CachedTokenSource) fetch(ctx context.Context) (*Token, error) {
+	tok, err := s.Source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.tok = tok
+	s.mu.Unlock()
+	return tok, nil
+}
+
+func (s *CachedTokenSource) refreshInBackground() {
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), backgroundRefreshTimeout)
+	defer cancel()
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	if _, err := s.fetch(ctx); err != nil {
+		// 旧令牌仍然有效，下次请求会再次尝试
+		log.Printf("⚠️ 提前刷新云开发令牌失败: %v", err)
+	}
+}
+
+// invalidator 可丢弃缓存的令牌来源
+type invalidator interface {
+	Invalidate()
+}
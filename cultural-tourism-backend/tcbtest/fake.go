@@ -0,0 +1,395 @@
+// Package tcbtest 提供进程内的 CloudBase 数据模型假服务器
+// 实现 /v1/model/{stage}/{model}/create|list|update|delete 及对应的
+// createMany|updateMany|deleteMany 批量接口、/auth/v1/token 令牌接口以及 /v1/storages 云存储接口，
+// 用于 Handler 测试以及不依赖线上环境的本地联调 (见 cmd/tcbfake)。
+// 数据存取委托给 store/local，HTTP 层只负责协议转换与故障注入。
+package tcbtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	modeljson "cultural-tourism-backend/model-json"
+	"cultural-tourism-backend/schemacheck"
+	"cultural-tourism-backend/store/local"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/match"
+)
+
+// Fake 内存数据模型存储 + HTTP 处理器
+type Fake struct {
+	data *local.Store
+
+	mu       sync.Mutex // 保护 failures / auth
+	failures []injectedFailure
+	auth     *fakeAuth       // nil 表示不校验令牌
+	models   map[string]bool // 已定义的数据模型，nil 表示接受任意模型
+
+	objects     map[string]fakeObject // 云存储: 对象路径 -> 内容 (同样由 mu 保护)
+	uploadSigns map[string]string     // 对象路径 -> 未使用的上传签名
+}
+
+// fakeAuth client_credentials 令牌签发与校验
+type fakeAuth struct {
+	secretID, secretKey string
+	ttl                 time.Duration
+	tokens              map[string]time.Time // token -> 过期时间
+	issued              int
+}
+
+type injectedFailure struct {
+	model  string // 空表示任意模型
+	status int
+}
+
+// NewFake 创建空的假服务器处理器，按 model-json 的 x-unique 声明施加唯一约束
+// 唯一字段冲突按参数错误 (400) 返回而不是 409：网关的错误码没有文档约定，测试不应依赖它
+func NewFake() *Fake {
+	data := local.New()
+	uniques, err := schemacheck.UniqueFields(modeljson.FS)
+	if err != nil {
+		panic(fmt.Sprintf("tcbtest: 读取 model-json: %v", err))
+	}
+	for model, fields := range uniques {
+		data.Unique(model, fields...)
+	}
+	return &Fake{
+		data:        data,
+		objects:     make(map[string]fakeObject),
+		uploadSigns: make(map[string]string),
+	}
+}
+
+// Seed 写入初始数据，返回生成的 _id (记录自带 _id 时沿用)
+func (f *Fake) Seed(model string, records ...interface{}) []string {
+	ids, err := f.data.InsertMany(model, records)
+	if err != nil {
+		panic(fmt.Sprintf("tcbtest: 无法写入种子数据: %v", err))
+	}
+	return ids
+}
+
+// Records 返回某个模型当前的全部记录 (副本)
+func (f *Fake) Records(model string) []match.Record {
+	return f.data.Records(model)
+}
+
+// FailNext 让接下来 n 次请求返回指定状态码 (model 为空时对任意模型生效)
+func (f *Fake) FailNext(model string, status, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i < n; i++ {
+		f.failures = append(f.failures, injectedFailure{model: model, status: status})
+	}
+}
+
+// DefineModels 只接受这些数据模型，其余模型返回 404 (模拟线上集合名写错)
+func (f *Fake) DefineModels(models ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.models = make(map[string]bool, len(models))
+	for _, m := range models {
+		f.models[m] = true
+	}
+}
+
+// RequireAuth 开启令牌校验：模型接口要求由 /auth/v1/token 签发且未过期的 Bearer 令牌
+func (f *Fake) RequireAuth(secretID, secretKey string, ttl time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = &fakeAuth{secretID: secretID, secretKey: secretKey, ttl: ttl, tokens: make(map[string]time.Time)}
+}
+
+// RevokeTokens 吊销已签发的全部令牌 (模拟令牌提前失效)
+func (f *Fake) RevokeTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.auth != nil {
+		clear(f.auth.tokens)
+	}
+}
+
+// TokensIssued 已签发的令牌数量
+func (f *Fake) TokensIssued() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.auth == nil {
+		return 0
+	}
+	return f.auth.issued
+}
+
+// Reset 清空全部数据
+func (f *Fake) Reset() {
+	_ = f.data.Reset()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = nil
+	f.auth = nil
+	f.models = nil
+	clear(f.objects)
+	clear(f.uploadSigns)
+}
+
+// ServeHTTP 路由: /v1/model/{stage}/{model}/{action}、/v1/storages/{action}、/cos/{path}
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/auth/v1/token" {
+		f.issueToken(w, r)
+		return
+	}
+	if path, ok := strings.CutPrefix(r.URL.Path, "/cos/"); ok {
+		f.serveObject(w, r, path) // COS 使用上传签名，不校验网关令牌
+		return
+	}
+	if !f.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "INVALID_ACCESS_TOKEN", "令牌无效或已过期")
+		return
+	}
+	if action, ok := strings.CutPrefix(r.URL.Path, "/v1/storages/"); ok {
+		f.serveStorage(w, r, action)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "v1" || parts[1] != "model" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "未知接口: "+r.URL.Path)
+		return
+	}
+	stage, model, action := parts[2], parts[3], parts[4]
+	if stage != tcb.StageProd && stage != tcb.StagePre {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "未知数据模型阶段: "+stage)
+		return
+	}
+	if !f.defined(model) {
+		writeError(w, http.StatusNotFound, "DATAMODEL_NOT_EXIST", "数据模型不存在: "+model)
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", "请求体不是合法 JSON")
+			return
+		}
+	}
+
+	if status, ok := f.takeFailure(model); ok {
+		writeError(w, status, "INJECTED_FAILURE", "tcbtest 注入的错误")
+		return
+	}
+
+	var (
+		data interface{}
+		err  error
+	)
+	switch {
+	case action == "create" && r.Method == http.MethodPost:
+		data, err = f.create(model, body)
+	case action == "list" && r.Method == http.MethodPost:
+		data, err = f.list(model, body)
+	case action == "update" && r.Method == http.MethodPut:
+		data, err = f.update(model, body)
+	case action == "delete" && r.Method == http.MethodPost:
+		data, err = f.delete(model, body)
+	case action == "createMany" && r.Method == http.MethodPost:
+		data, err = f.createMany(model, body)
+	case action == "updateMany" && r.Method == http.MethodPut:
+		data, err = f.updateMany(model, body)
+	case action == "deleteMany" && r.Method == http.MethodPost:
+		data, err = f.deleteMany(model, body)
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("不支持 %s %s", r.Method, action))
+		return
+	}
+	if errors.Is(err, local.ErrDuplicateID) {
+		writeError(w, http.StatusConflict, "DUPLICATE_KEY", err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "requestId": local.NewID()})
+}
+
+// issueToken POST /auth/v1/token (Basic 鉴权，grant_type=client_credentials)
+func (f *Fake) issueToken(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.auth == nil || r.Method != http.MethodPost {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "未开启鉴权")
+		return
+	}
+	var body struct {
+		GrantType string `json:"grant_type"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	id, key, ok := r.BasicAuth()
+	if !ok || id != f.auth.secretID || key != f.auth.secretKey || body.GrantType != "client_credentials" {
+		writeError(w, http.StatusUnauthorized, "INVALID_CREDENTIALS", "凭证错误")
+		return
+	}
+
+	token := local.NewID()
+	f.auth.tokens[token] = time.Now().Add(f.auth.ttl)
+	f.auth.issued++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(f.auth.ttl.Seconds()),
+	})
+}
+
+// authorized 校验 Bearer 令牌 (未开启鉴权时总是通过)
+func (f *Fake) authorized(r *http.Request) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.auth == nil {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	expiry, ok := f.auth.tokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+func (f *Fake) defined(model string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.models == nil || f.models[model]
+}
+
+func (f *Fake) takeFailure(model string) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, fail := range f.failures {
+		if fail.model == "" || fail.model == model {
+			f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			return fail.status, true
+		}
+	}
+	return 0, false
+}
+
+func (f *Fake) create(model string, body map[string]interface{}) (interface{}, error) {
+	rec, ok := body["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("data 必须是对象")
+	}
+	id, err := f.data.Insert(model, rec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"id": id}, nil
+}
+
+func (f *Fake) createMany(model string, body map[string]interface{}) (interface{}, error) {
+	raw, ok := body["data"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("data 必须是数组")
+	}
+	for i, item := range raw {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("data[%d] 必须是对象", i)
+		}
+	}
+	ids, err := f.data.InsertMany(model, raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"idList": ids}, nil
+}
+
+func (f *Fake) list(model string, body map[string]interface{}) (interface{}, error) {
+	orderBy, _ := body["orderBy"].([]interface{})
+	page, size := intParam(body["pageNumber"], 1), intParam(body["pageSize"], 10)
+
+	matched, total, err := f.data.Query(model, where(body), orderBy, page, size)
+	if err != nil {
+		return nil, err
+	}
+
+	sel, _ := body["select"].(map[string]interface{})
+	records := make([]interface{}, len(matched))
+	for i, rec := range matched {
+		records[i] = match.Project(rec, sel)
+	}
+	result := map[string]interface{}{"records": records}
+	if getCount, _ := body["getCount"].(bool); getCount {
+		result["total"] = total
+	}
+	return result, nil
+}
+
+// update 仅更新第一条匹配记录
+func (f *Fake) update(model string, body map[string]interface{}) (interface{}, error) {
+	return f.updateWhere(model, body, false)
+}
+
+func (f *Fake) updateMany(model string, body map[string]interface{}) (interface{}, error) {
+	return f.updateWhere(model, body, true)
+}
+
+func (f *Fake) updateWhere(model string, body map[string]interface{}, all bool) (interface{}, error) {
+	data, ok := body["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("data 必须是对象")
+	}
+	n, err := f.data.Update(model, where(body), data, all)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"count": n}, nil
+}
+
+// delete 仅删除第一条匹配记录
+func (f *Fake) delete(model string, body map[string]interface{}) (interface{}, error) {
+	return f.deleteWhere(model, body, false)
+}
+
+func (f *Fake) deleteMany(model string, body map[string]interface{}) (interface{}, error) {
+	return f.deleteWhere(model, body, true)
+}
+
+func (f *Fake) deleteWhere(model string, body map[string]interface{}, all bool) (interface{}, error) {
+	n, err := f.data.Delete(model, where(body), all)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"count": n}, nil
+}
+
+// where 取出请求体中的 filter.where
+func where(body map[string]interface{}) map[string]interface{} {
+	filter, _ := body["filter"].(map[string]interface{})
+	w, _ := filter["where"].(map[string]interface{})
+	return w
+}
+
+func intParam(v interface{}, fallback int) int {
+	if n, ok := v.(float64); ok {
+		return int(n)
+	}
+	return fallback
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "message": message, "requestId": local.NewID()})
+}
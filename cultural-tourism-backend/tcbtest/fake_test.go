@@ -0,0 +1,234 @@
+package tcbtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcb/query"
+	"cultural-tourism-backend/tcbtest"
+)
+
+type item struct {
+	ID    string  `json:"_id"`
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	Score float64 `json:"score"`
+}
+
+func seeded(t *testing.T) *tcb.Repository[item] {
+	t.Helper()
+	srv := tcbtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Seed("items",
+		item{Name: "雷峰塔", Type: "scenic", Score: 4.8},
+		item{Name: "楼外楼", Type: "food", Score: 4.2},
+		item{Name: "灵隐寺", Type: "scenic", Score: 4.9},
+		item{Name: "西湖国宾馆", Type: "hotel", Score: 4.5},
+	)
+	return tcb.NewRepository[item](srv.Client(), "items")
+}
+
+func names(items []item) []string {
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		out = append(out, it.Name)
+	}
+	return out
+}
+
+func TestListOperators(t *testing.T) {
+	repo := seeded(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name  string
+		where map[string]interface{}
+		want  int
+	}{
+		{"eq", map[string]interface{}{"type": map[string]interface{}{"$eq": "scenic"}}, 2},
+		{"in", map[string]interface{}{"type": map[string]interface{}{"$in": []interface{}{"food", "hotel"}}}, 2},
+		{"regex", map[string]interface{}{"name": map[string]interface{}{"$regex": "^西湖"}}, 1},
+		{"range", map[string]interface{}{"score": map[string]interface{}{"$gt": 4.3, "$lt": 4.85}}, 2},
+		{"or", map[string]interface{}{"$or": []interface{}{
+			map[string]interface{}{"type": map[string]interface{}{"$eq": "food"}},
+			map[string]interface{}{"score": map[string]interface{}{"$gte": 4.9}},
+		}}, 2},
+		{"and", map[string]interface{}{"$and": []interface{}{
+			map[string]interface{}{"type": map[string]interface{}{"$eq": "scenic"}},
+			map[string]interface{}{"score": map[string]interface{}{"$lte": 4.8}},
+		}}, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := repo.List(ctx, map[string]interface{}{"where": tc.where}, 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != tc.want || page.Total != tc.want {
+				t.Errorf("got %v (total %d), want %d", names(page.Items), page.Total, tc.want)
+			}
+		})
+	}
+}
+
+func TestListPagingAndOrder(t *testing.T) {
+	srv := tcbtest.NewServer()
+	defer srv.Close()
+	srv.Seed("items", item{Name: "a", Score: 1}, item{Name: "b", Score: 3}, item{Name: "c", Score: 2})
+
+	result, err := srv.Client().Request(context.Background(), "POST", "/v1/model/prod/items/list", map[string]interface{}{
+		"pageNumber": 2,
+		"pageSize":   2,
+		"getCount":   true,
+		"orderBy":    []interface{}{map[string]interface{}{"score": "desc"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := result.(map[string]interface{})["data"].(map[string]interface{})
+	records := data["records"].([]interface{})
+	if data["total"] != float64(3) || len(records) != 1 || records[0].(map[string]interface{})["name"] != "a" {
+		t.Errorf("page 2 = %v, want [a] with total 3", data)
+	}
+}
+
+func TestUnsupportedOperatorIsRejected(t *testing.T) {
+	repo := seeded(t)
+	_, err := repo.List(context.Background(), map[string]interface{}{
+		"where": map[string]interface{}{"name": map[string]interface{}{"$near": 1}},
+	}, 1, 10)
+	if err == nil {
+		t.Fatal("expected error for unsupported operator")
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	repo := seeded(t)
+	ctx := context.Background()
+
+	created, err := repo.CreateMany(ctx, []item{{Name: "岳王庙", Type: "scenic"}, {Name: "知味观", Type: "food"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Count != 2 || len(created.Failed()) != 0 {
+		t.Fatalf("CreateMany = %+v, want 2 ok items", created)
+	}
+
+	scenic := query.New(query.Eq("type", "scenic")).Build()
+	updated, err := repo.UpdateMany(ctx, scenic, map[string]interface{}{"score": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Count != 3 {
+		t.Errorf("UpdateMany count = %d, want 3", updated.Count)
+	}
+
+	byIDs, err := repo.UpdateByIDs(ctx, []string{created.Items[1].ID, "missing"}, map[string]interface{}{"score": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byIDs.Count != 1 || !byIDs.Items[0].OK || byIDs.Items[1].OK {
+		t.Errorf("UpdateByIDs = %+v, want first ok and second failed", byIDs)
+	}
+
+	deleted, err := repo.DeleteMany(ctx, scenic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Count != 3 {
+		t.Errorf("DeleteMany count = %d, want 3", deleted.Count)
+	}
+	if n, _ := repo.Count(ctx, nil); n != 3 {
+		t.Errorf("remaining = %d, want 3", n)
+	}
+}
+
+func TestListAllKeyset(t *testing.T) {
+	srv := tcbtest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 25; i++ {
+		srv.Seed("items", map[string]interface{}{"name": fmt.Sprintf("n%02d", i), "updated_at": fmt.Sprintf("2024-01-%02dT00:00:00Z", 25-i)})
+	}
+	repo := tcb.NewRepository[item](srv.Client(), "items")
+	ctx := context.Background()
+
+	for _, order := range []tcb.ScanOrder{tcb.ScanByID, tcb.ScanByUpdatedAt} {
+		seen := make(map[string]bool)
+		inserted := false
+		for it, err := range repo.All(ctx, nil, tcb.ScanOptions{Order: order, PageSize: 10}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[it.ID] {
+				t.Fatalf("%s: %s visited twice", order, it.Name)
+			}
+			seen[it.ID] = true
+			// 扫描中途写入新记录不应影响已有记录的遍历
+			if !inserted {
+				srv.Seed("items", map[string]interface{}{"name": "late-" + string(order), "updated_at": "2030-01-01T00:00:00Z"})
+				inserted = true
+			}
+		}
+		if len(seen) < 25 {
+			t.Errorf("%s: visited %d records, want at least 25", order, len(seen))
+		}
+	}
+}
+
+func TestListAllStopsOnCancel(t *testing.T) {
+	repo := seeded(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, err := range repo.All(ctx, nil, tcb.ScanOptions{}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		return
+	}
+	t.Fatal("expected an error from a cancelled scan")
+}
+
+func TestIncrementIsAtomic(t *testing.T) {
+	repo := seeded(t)
+	ctx := context.Background()
+	page, err := repo.List(ctx, query.New(query.Eq("name", "雷峰塔")).Build(), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := page.Items[0].ID
+
+	const workers, perWorker = 4, 5
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker)
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				if _, err := repo.Increment(ctx, id, "likes", 1); err != nil {
+					errs <- err
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	n, err := repo.Increment(ctx, id, "likes", 0)
+	if err != nil || n != workers*perWorker {
+		t.Errorf("likes = %d, %v, want %d", n, err, workers*perWorker)
+	}
+
+	if _, err := repo.Increment(ctx, "missing", "likes", 1); !errors.Is(err, tcb.ErrNotFound) {
+		t.Errorf("missing record err = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.Increment(ctx, id, "name", 1); !errors.Is(err, tcb.ErrUnexpectedResponse) {
+		t.Errorf("non-numeric field err = %v, want ErrUnexpectedResponse", err)
+	}
+}
@@ -0,0 +1,92 @@
+package tcbtest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cultural-tourism-backend/config"
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcbtest"
+)
+
+func TestResolveProfile(t *testing.T) {
+	cfg := config.TCB{EnvID: "tour-prod-1a2b"}
+
+	// 未配置 profile：保持旧行为 (正式版 + 外网)
+	p, err := tcb.ResolveProfile(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "prod" || p.Stage != tcb.StageProd || p.GatewayURL() != "https://tour-prod-1a2b.api.tcloudbasegateway.com" {
+		t.Errorf("default profile = %+v (%s)", p, p.GatewayURL())
+	}
+
+	cfg.Profile = "staging"
+	cfg.EnvIDs = map[string]string{"staging": "tour-staging-3c4d"}
+	p, err = tcb.ResolveProfile(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Stage != tcb.StagePre || p.GatewayURL() != "https://tour-staging-3c4d.api.intra.tcloudbasegateway.com" {
+		t.Errorf("staging profile = %+v (%s)", p, p.GatewayURL())
+	}
+	if banner := p.Banner(); !strings.Contains(banner, "staging") || !strings.Contains(banner, "pre") {
+		t.Errorf("banner = %q", banner)
+	}
+
+	external := false
+	cfg.Internal = &external
+	cfg.Stage = tcb.StageProd
+	if p, _ = tcb.ResolveProfile(cfg); p.Stage != tcb.StageProd || p.Internal {
+		t.Errorf("overrides not applied: %+v", p)
+	}
+
+	cfg.Stage = "beta"
+	if _, err := tcb.ResolveProfile(cfg); err == nil {
+		t.Error("invalid stage accepted")
+	}
+
+	cfg.Stage, cfg.Profile = "", "qa"
+	if _, err := tcb.ResolveProfile(cfg); err == nil {
+		t.Error("unknown profile accepted")
+	}
+}
+
+func TestClientUsesModelStage(t *testing.T) {
+	fake := tcbtest.NewFake()
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := &tcb.CloudBaseClient{
+		Stage:      tcb.StagePre,
+		Tokens:     tcb.StaticTokenSource("tcbtest-token"),
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Client(),
+		Timeouts:   tcb.Timeouts{Read: tcb.DefaultReadTimeout, Write: tcb.DefaultWriteTimeout},
+	}
+	ctx := context.Background()
+	if _, err := client.CreateData(ctx, "items", map[string]interface{}{"name": "雷峰塔"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListData(ctx, "items", nil, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/v1/model/pre/items/create", "/v1/model/pre/items/list"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
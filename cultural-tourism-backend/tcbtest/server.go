@@ -0,0 +1,46 @@
+package tcbtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"cultural-tourism-backend/tcb"
+)
+
+// Server 基于 httptest.Server 的假 CloudBase 网关
+type Server struct {
+	*Fake
+	httpServer *httptest.Server
+}
+
+// NewServer 启动假服务器，测试结束时需调用 Close
+func NewServer() *Server {
+	fake := NewFake()
+	return &Server{Fake: fake, httpServer: httptest.NewServer(fake)}
+}
+
+// URL 假服务器地址
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close 关闭假服务器
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client 返回指向假服务器的 CloudBaseClient
+// 重试退避压缩到毫秒级且不启用熔断，便于测试快速、可预期地执行
+func (s *Server) Client() *tcb.CloudBaseClient {
+	return &tcb.CloudBaseClient{
+		EnvID:      "tcbtest",
+		Tokens:     tcb.StaticTokenSource("tcbtest-token"),
+		BaseURL:    s.httpServer.URL,
+		HTTPClient: &http.Client{},
+		Timeouts: tcb.Timeouts{
+			Read:  tcb.DefaultReadTimeout,
+			Write: tcb.DefaultWriteTimeout,
+		},
+		Retry: tcb.RetryPolicy{MaxAttempts: 3, BaseDelay: 0, MaxDelay: 0},
+	}
+}
@@ -0,0 +1,123 @@
+package tcbtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"cultural-tourism-backend/store/local"
+)
+
+// fakeBucket 假服务器签发的 fileID 前缀
+const fakeBucket = "cloud://tcbtest.bucket/"
+
+// fakeObject 假云存储中的对象
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+// Objects 返回云存储中全部对象的 fileID
+func (f *Fake) Objects() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.objects))
+	for path := range f.objects {
+		ids = append(ids, fakeBucket+path)
+	}
+	return ids
+}
+
+// serveStorage 路由: /v1/storages/{action}
+// 请求与响应均为对象数组，单个对象失败时在该项中返回 code / message
+func (f *Fake) serveStorage(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "不支持 "+r.Method)
+		return
+	}
+	var items []struct {
+		ObjectID      string `json:"objectId"`
+		CloudObjectID string `json:"cloudObjectId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "请求体必须是对象数组")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	base := "http://" + r.Host + "/cos/"
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, it := range items {
+		path, _ := strings.CutPrefix(it.CloudObjectID, fakeBucket)
+		_, exists := f.objects[path]
+		switch action {
+		case "get-objects-upload-info":
+			sign := local.NewID()
+			f.uploadSigns[it.ObjectID] = sign
+			out = append(out, map[string]interface{}{
+				"uploadUrl":       base + it.ObjectID,
+				"downloadUrl":     base + it.ObjectID,
+				"authorization":   sign,
+				"token":           "tcbtest-cos-token",
+				"cloudObjectMeta": "meta-" + it.ObjectID,
+				"cloudObjectId":   fakeBucket + it.ObjectID,
+			})
+		case "get-objects-download-info":
+			item := map[string]interface{}{"cloudObjectId": it.CloudObjectID}
+			if exists {
+				item["downloadUrl"] = base + path + "?sign=" + local.NewID()
+			} else {
+				item["code"], item["message"] = "STORAGE_FILE_NONEXIST", "文件不存在"
+			}
+			out = append(out, item)
+		case "delete-objects":
+			item := map[string]interface{}{"cloudObjectId": it.CloudObjectID, "code": "SUCCESS"}
+			if exists {
+				delete(f.objects, path)
+			} else {
+				item["code"], item["message"] = "STORAGE_FILE_NONEXIST", "文件不存在"
+			}
+			out = append(out, item)
+		default:
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "未知接口: "+action)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// serveObject 模拟 COS: PUT 校验上传签名后写入，GET 下载
+func (f *Fake) serveObject(w http.ResponseWriter, r *http.Request, path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		sign, ok := f.uploadSigns[path]
+		if !ok || r.Header.Get("Authorization") != sign || r.Header.Get("X-Cos-Meta-Fileid") != "meta-"+path {
+			writeError(w, http.StatusForbidden, "AccessDenied", "上传签名无效")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidBody", err.Error())
+			return
+		}
+		delete(f.uploadSigns, path)
+		f.objects[path] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := f.objects[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		_, _ = w.Write(obj.data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,54 @@
+package tcbtest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"cultural-tourism-backend/tcb/storage"
+	"cultural-tourism-backend/tcbtest"
+)
+
+func TestStorageUploadTempURLDelete(t *testing.T) {
+	srv := tcbtest.NewServer()
+	t.Cleanup(srv.Close)
+	s := storage.New(srv.Client())
+	ctx := context.Background()
+
+	fileID, err := s.Upload(ctx, "photos/a.jpg", []byte("jpeg-bytes"), "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !storage.IsFileID(fileID) {
+		t.Fatalf("fileID = %q", fileID)
+	}
+
+	urls, err := s.TempURLs(ctx, []string{fileID, "cloud://tcbtest.bucket/missing.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 1 || urls[fileID] == "" {
+		t.Fatalf("urls = %v, want only %s", urls, fileID)
+	}
+	resp, err := http.Get(urls[fileID])
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "jpeg-bytes" {
+		t.Errorf("downloaded %q", body)
+	}
+
+	results, err := s.Delete(ctx, fileID, "cloud://tcbtest.bucket/missing.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || !results[0].OK || results[1].OK {
+		t.Errorf("delete results = %+v, want first ok and second failed", results)
+	}
+	if objs := srv.Objects(); len(objs) != 0 {
+		t.Errorf("objects after delete = %v", objs)
+	}
+}
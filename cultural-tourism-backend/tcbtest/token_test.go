@@ -0,0 +1,89 @@
+package tcbtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cultural-tourism-backend/tcb"
+	"cultural-tourism-backend/tcbtest"
+)
+
+func credentialsClient(srv *tcbtest.Server, secretKey string) (*tcb.CloudBaseClient, *tcb.CachedTokenSource) {
+	src := tcb.NewCachedTokenSource(&tcb.ClientCredentialsSource{
+		TokenURL:  srv.URL() + "/auth/v1/token",
+		SecretID:  "AKID",
+		SecretKey: secretKey,
+	})
+	client := srv.Client()
+	client.Tokens = src
+	return client, src
+}
+
+func TestTokenCachedAndRefreshedOn401(t *testing.T) {
+	srv := tcbtest.NewServer()
+	defer srv.Close()
+	srv.RequireAuth("AKID", "secret", time.Hour)
+	srv.Seed("items", item{Name: "雷峰塔"})
+
+	client, _ := credentialsClient(srv, "secret")
+	repo := tcb.NewRepository[item](client, "items")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.List(ctx, nil, 1, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := srv.TokensIssued(); n != 1 {
+		t.Fatalf("tokens issued = %d, want 1 (cached)", n)
+	}
+
+	// 令牌被吊销后，写操作同样只重试一次即可恢复
+	srv.RevokeTokens()
+	if _, err := repo.Create(ctx, item{Name: "灵隐寺"}); err != nil {
+		t.Fatalf("create after revoke: %v", err)
+	}
+	if n := srv.TokensIssued(); n != 2 {
+		t.Errorf("tokens issued = %d, want 2", n)
+	}
+}
+
+func TestTokenRefreshAhead(t *testing.T) {
+	srv := tcbtest.NewServer()
+	defer srv.Close()
+	srv.RequireAuth("AKID", "secret", time.Minute)
+
+	_, src := credentialsClient(srv, "secret")
+	src.RefreshAhead = 2 * time.Minute // 每个令牌一拿到就进入提前刷新窗口
+	ctx := context.Background()
+
+	first, err := src.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 仍在有效期内：立即返回旧令牌，后台换新
+	if again, _ := src.Token(ctx); again.Value != first.Value {
+		t.Fatal("expected the cached token while refreshing in background")
+	}
+	deadline := time.Now().Add(time.Second)
+	for srv.TokensIssued() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := srv.TokensIssued(); n < 2 {
+		t.Fatalf("tokens issued = %d, want a background refresh", n)
+	}
+}
+
+func TestTokenBadCredentials(t *testing.T) {
+	srv := tcbtest.NewServer()
+	defer srv.Close()
+	srv.RequireAuth("AKID", "secret", time.Hour)
+
+	client, _ := credentialsClient(srv, "wrong")
+	_, err := tcb.NewRepository[item](client, "items").List(context.Background(), nil, 1, 10)
+	if !errors.Is(err, tcb.ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+}